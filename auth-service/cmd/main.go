@@ -4,11 +4,9 @@ import (
   "context"
   "flag"
   "main/internal/handler"
-  "os"
-  "os/signal"
-  "syscall"
 
   "github.com/UshakovN/stock-predictor-service/config"
+  "github.com/UshakovN/stock-predictor-service/lifecycle"
   log "github.com/sirupsen/logrus"
 )
 
@@ -25,7 +23,8 @@ import (
 // @name X-Auth-Token
 //
 func main() {
-  ctx := context.Background()
+  lc := lifecycle.New()
+  ctx := lc.Context()
 
   servePort := flag.String("port", "8080", "serving port")
   configPath := flag.String("path", "", "path to service config file")
@@ -36,21 +35,20 @@ func main() {
     log.Fatalf("cannot parse fetcher config: %v", err)
   }
 
-  h, err := handler.NewHandler(ctx, cfg)
+  // storage is closed on shutdown after in-flight requests are served
+  h, err := handler.NewHandler(lifecycle.WithoutCancel(ctx), cfg)
   if err != nil {
     log.Fatalf("cannot create new handler: %v", err)
   }
   h.BindRouter()
 
-  go h.ContinuouslyServeHttp(*servePort)
+  lc.ServeHttp(*servePort)
   log.Infof("ready for serve http on port: %s", *servePort)
 
-  serviceShutdown()
-}
-
-func serviceShutdown() {
-  exitSignal := make(chan os.Signal)
-  signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
+  lc.OnShutdown("auth service storage", func(context.Context) error {
+    h.Close()
+    return nil
+  })
 
-  <-exitSignal
+  lc.Wait()
 }
@@ -20,7 +20,6 @@ import (
   "github.com/UshakovN/stock-predictor-service/hash"
   "github.com/UshakovN/stock-predictor-service/swagger"
   "github.com/UshakovN/stock-predictor-service/utils"
-  log "github.com/sirupsen/logrus"
 )
 
 type Handler struct {
@@ -239,11 +238,8 @@ func (h *Handler) HandleCheck(w http.ResponseWriter, r *http.Request) error {
   return nil
 }
 
-func (h *Handler) ContinuouslyServeHttp(port string) {
-  err := http.ListenAndServe(fmt.Sprint(":", port), nil)
-  if err != nil {
-    log.Fatalf("listen and serve error: %v", err)
-  }
+func (h *Handler) Close() {
+  h.service.Close()
 }
 
 // HandleHealth
@@ -19,6 +19,7 @@ type UserAuthService interface {
   SignIn(input *domain.SignInInput) (*domain.Tokens, error)
   RefreshTokens(refreshToken string) (*domain.Tokens, error)
   CheckUser(userId string) (*domain.UserInfo, error)
+  Close()
 }
 
 type userAuthService struct {
@@ -39,6 +40,10 @@ func NewUserAuthService(ctx context.Context, config *Config) UserAuthService {
   }
 }
 
+func (s *userAuthService) Close() {
+  s.storage.Close()
+}
+
 func (s *userAuthService) SignUp(input *domain.SignUpInput) (*domain.Tokens, error) {
   passwordHash := s.passwordManager.Hash(input.Password)
 
@@ -21,6 +21,7 @@ type Storage interface {
   PutToken(token *RefreshToken) error
   GetToken(tokenId string) (*RefreshToken, bool, error)
   UpdateToken(token *RefreshToken) error
+  Close()
 }
 
 type storage struct {
@@ -39,6 +40,10 @@ func NewStorage(ctx context.Context, config *postgres.Config) (Storage, error) {
   }, nil
 }
 
+func (s *storage) Close() {
+  s.client.Close()
+}
+
 func (s *storage) PutUser(user *ServiceUser) error {
   return s.client.BeginTxFunc(s.ctx, pgx.TxOptions{
     IsoLevel: pgx.Serializable,
@@ -16,6 +16,122 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/fundamentals": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fundamentals method provide quarterly and annual financials of tickers for client with pagination, filtration, sorting.\nMetrics not reported for the fiscal period are null",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Fundamentals model method",
+                "parameters": [
+                    {
+                        "description": "Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/clientservice.FundamentalsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/clientservice.FundamentalsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/fundamentals/pages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fundamentals pages method calculate total fundamentals pages count for specified page size",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Fundamentals pages method",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/clientservice.PagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Health method check http server health",
@@ -36,6 +152,66 @@ const docTemplate = `{
                 }
             }
         },
+        "/indicators": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Indicators method provide technical indicators series of the ticker daily bars for the date range:\n'sma', 'ema', 'rsi', 'macd', 'bollinger', 'atr' and 'obv'. Not specified indicator params are defaulted,\nseries values are null until indicator has enough bars for the period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Indicators method",
+                "parameters": [
+                    {
+                        "description": "Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/clientservice.IndicatorsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/clientservice.IndicatorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/predictions": {
             "get": {
                 "security": [
@@ -98,7 +274,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Stocks method provide stocks models for client with pagination, filtration, sorting",
+                "description": "Stocks method provide stocks models for client with pagination, filtration, sorting.\nStocks are filtered by granularity, default is daily bars (timespan 'day', multiplier 1)\nRaw bars are returned by default, split adjusted bars are returned with 'adjusted' flag\nWith 'cursor' instead of 'pagination' stocks are paged by keyset, 'next_cursor' of the response\nis passed as cursor 'after' for the next page",
                 "produces": [
                     "application/json"
                 ],
@@ -151,6 +327,66 @@ const docTemplate = `{
                 }
             }
         },
+        "/stocks/aggregate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stocks aggregate method provide daily bars of the ticker resampled to 'week', 'month' or 'quarter' period\nfor the date range: first open, max high, min low, last close and summed volume of the period.\nPartial periods on the range borders are aggregated from the bars inside the range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Stocks aggregate method",
+                "parameters": [
+                    {
+                        "description": "Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/clientservice.StocksAggregateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/clientservice.StocksAggregateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errs.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/stocks/pages": {
             "get": {
                 "security": [
@@ -330,7 +566,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Tickers method provide tickers models for client with pagination, filtration, sorting and media fields",
+                "description": "Tickers method provide tickers models for client with pagination, filtration, sorting and media fields\nWith 'cursor' instead of 'pagination' tickers are paged by keyset, 'next_cursor' of the response\nis passed as cursor 'after' for the next page. Nullable fields cannot be sort field in cursor mode\nFilters are joined by 'and', filter can be 'and', 'or' and 'not' group of the nested filters.\nBorder compare is one of 'eq', 'neq', 'gt', 'gte', 'lt', 'lte', 'like' and 'ilike', like compares match value prefix\nTickers can be filtered and sorted by 'sector', 'primary_exchange', 'market' and 'list_date',\ne.g. list filter by 'sector' value 'technology' and 'primary_exchange' value 'XNAS' for NASDAQ tech tickers",
                 "produces": [
                     "application/json"
                 ],
@@ -501,6 +737,47 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "clientservice.AggregatedStock": {
+            "type": "object",
+            "properties": {
+                "adjusted": {
+                    "type": "boolean"
+                },
+                "bars_count": {
+                    "type": "integer"
+                },
+                "close_price": {
+                    "type": "number"
+                },
+                "first_stocked_time": {
+                    "type": "string"
+                },
+                "highest_price": {
+                    "type": "number"
+                },
+                "last_stocked_time": {
+                    "type": "string"
+                },
+                "lowest_price": {
+                    "type": "number"
+                },
+                "open_price": {
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "ticker_id": {
+                    "type": "string"
+                },
+                "trading_volume": {
+                    "type": "number"
+                }
+            }
+        },
         "clientservice.BetweenFilter": {
             "type": "object",
             "properties": {
@@ -523,17 +800,220 @@ const docTemplate = `{
                 "value": {}
             }
         },
+        "clientservice.Cursor": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
         "clientservice.Filter": {
             "type": "object",
             "properties": {
+                "and": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clientservice.Filter"
+                    }
+                },
                 "between": {
                     "$ref": "#/definitions/clientservice.BetweenFilter"
                 },
                 "border": {
                     "$ref": "#/definitions/clientservice.BorderFilter"
                 },
+                "is_null": {
+                    "$ref": "#/definitions/clientservice.NullFilter"
+                },
                 "list": {
                     "$ref": "#/definitions/clientservice.ListFilter"
+                },
+                "not": {
+                    "$ref": "#/definitions/clientservice.Filter"
+                },
+                "not_in": {
+                    "$ref": "#/definitions/clientservice.ListFilter"
+                },
+                "or": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clientservice.Filter"
+                    }
+                }
+            }
+        },
+        "clientservice.Financial": {
+            "type": "object",
+            "properties": {
+                "basic_eps": {
+                    "type": "number"
+                },
+                "diluted_eps": {
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "filing_date": {
+                    "type": "string"
+                },
+                "fiscal_period": {
+                    "type": "string"
+                },
+                "fiscal_year": {
+                    "type": "integer"
+                },
+                "net_income": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "shares_outstanding": {
+                    "type": "number"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "ticker_id": {
+                    "type": "string"
+                },
+                "timeframe": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "clientservice.FundamentalsRequest": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "$ref": "#/definitions/clientservice.Cursor"
+                },
+                "filters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clientservice.Filter"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/clientservice.Pagination"
+                },
+                "sort": {
+                    "$ref": "#/definitions/clientservice.Sort"
+                }
+            }
+        },
+        "clientservice.FundamentalsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "financials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clientservice.Financial"
+                    }
+                },
+                "next_cursor": {
+                    "description": "next cursor is returned in cursor mode, empty on the last page",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "clientservice.Indicator": {
+            "type": "object",
+            "properties": {
+                "fast": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "integer"
+                },
+                "signal": {
+                    "type": "integer"
+                },
+                "slow": {
+                    "type": "integer"
+                },
+                "width": {
+                    "type": "number"
+                }
+            }
+        },
+        "clientservice.IndicatorSeries": {
+            "type": "object",
+            "properties": {
+                "indicator": {
+                    "$ref": "#/definitions/clientservice.Indicator"
+                },
+                "lines": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                }
+            }
+        },
+        "clientservice.IndicatorsRequest": {
+            "type": "object",
+            "properties": {
+                "adjusted": {
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "indicators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clientservice.Indicator"
+                    }
+                },
+                "ticker_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "clientservice.IndicatorsResponse": {
+            "type": "object",
+            "properties": {
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clientservice.IndicatorSeries"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "ticker_id": {
+                    "type": "string"
+                },
+                "times": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -560,6 +1040,14 @@ const docTemplate = `{
                 }
             }
         },
+        "clientservice.NullFilter": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
         "clientservice.PagesResponse": {
             "type": "object",
             "properties": {
@@ -630,6 +1118,9 @@ const docTemplate = `{
         "clientservice.Stock": {
             "type": "object",
             "properties": {
+                "adjusted": {
+                    "type": "boolean"
+                },
                 "close_price": {
                     "type": "number"
                 },
@@ -642,6 +1133,9 @@ const docTemplate = `{
                 "lowest_price": {
                     "type": "number"
                 },
+                "multiplier": {
+                    "type": "integer"
+                },
                 "open_price": {
                     "type": "number"
                 },
@@ -651,25 +1145,84 @@ const docTemplate = `{
                 "ticker_id": {
                     "type": "string"
                 },
+                "timespan": {
+                    "type": "string"
+                },
                 "trading_volume": {
                     "type": "number"
                 }
             }
         },
+        "clientservice.StocksAggregateRequest": {
+            "type": "object",
+            "properties": {
+                "adjusted": {
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/clientservice.Pagination"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "ticker_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "clientservice.StocksAggregateResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "next cursor is returned in cursor mode, empty on the last page",
+                    "type": "string"
+                },
+                "stocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clientservice.AggregatedStock"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
         "clientservice.StocksRequest": {
             "type": "object",
             "properties": {
+                "adjusted": {
+                    "type": "boolean"
+                },
+                "cursor": {
+                    "$ref": "#/definitions/clientservice.Cursor"
+                },
                 "filters": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/clientservice.Filter"
                     }
                 },
+                "multiplier": {
+                    "type": "integer"
+                },
                 "pagination": {
                     "$ref": "#/definitions/clientservice.Pagination"
                 },
                 "sort": {
                     "$ref": "#/definitions/clientservice.Sort"
+                },
+                "timespan": {
+                    "type": "string"
                 }
             }
         },
@@ -679,6 +1232,10 @@ const docTemplate = `{
                 "count": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "description": "next cursor is returned in cursor mode, empty on the last page",
+                    "type": "string"
+                },
                 "stocks": {
                     "type": "array",
                     "items": {
@@ -781,6 +1338,24 @@ const docTemplate = `{
                 "homepage_url": {
                     "type": "string"
                 },
+                "list_date": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "primary_exchange": {
+                    "type": "string"
+                },
+                "sector": {
+                    "type": "string"
+                },
+                "sic_code": {
+                    "type": "string"
+                },
+                "sic_description": {
+                    "type": "string"
+                },
                 "ticker_id": {
                     "type": "string"
                 },
@@ -803,6 +1378,9 @@ const docTemplate = `{
         "clientservice.TickersRequest": {
             "type": "object",
             "properties": {
+                "cursor": {
+                    "$ref": "#/definitions/clientservice.Cursor"
+                },
                 "filters": {
                     "type": "array",
                     "items": {
@@ -826,6 +1404,10 @@ const docTemplate = `{
                 "count": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "description": "next cursor is returned in cursor mode, empty on the last page",
+                    "type": "string"
+                },
                 "success": {
                     "type": "boolean"
                 },
@@ -2,20 +2,60 @@ package domain
 
 import (
   "main/internal/storage"
+  "time"
 )
 
 type GetInput struct {
   Pagination *PaginationInput `json:"pagination"`
+  Cursor     *CursorInput     `json:"cursor"`
   Sort       *SortInput       `json:"sort"`
   Filters    []*FilterInput   `json:"filters"`
   With       *WithFields      `json:"with"`
 }
 
+type GetStocksInput struct {
+  *GetInput
+  Timespan   string `json:"timespan"`
+  Multiplier int    `json:"multiplier"`
+  Adjusted   bool   `json:"adjusted"`
+}
+
+type AggregateStocksInput struct {
+  Pagination *PaginationInput `json:"pagination"`
+  TickerId   string           `json:"ticker_id"`
+  Period     string           `json:"period"`
+  From       time.Time        `json:"from"`
+  To         time.Time        `json:"to"`
+  Adjusted   bool             `json:"adjusted"`
+}
+
+type IndicatorsInput struct {
+  TickerId   string            `json:"ticker_id"`
+  From       time.Time         `json:"from"`
+  To         time.Time         `json:"to"`
+  Adjusted   bool              `json:"adjusted"`
+  Indicators []*IndicatorInput `json:"indicators"`
+}
+
+type IndicatorInput struct {
+  Name   string  `json:"name"`
+  Period int     `json:"period"`
+  Fast   int     `json:"fast"`
+  Slow   int     `json:"slow"`
+  Signal int     `json:"signal"`
+  Width  float64 `json:"width"`
+}
+
 type PaginationInput struct {
   Page  int `json:"page"`
   Count int `json:"count"`
 }
 
+type CursorInput struct {
+  After string `json:"after"`
+  Count int    `json:"count"`
+}
+
 type SortInput struct {
   Field string `json:"field"`
   Order string `json:"order"`
@@ -25,6 +65,11 @@ type FilterInput struct {
   Border  *BorderFilter  `json:"border"`
   Between *BetweenFilter `json:"between"`
   List    *ListFilter    `json:"list"`
+  NotIn   *ListFilter    `json:"not_in"`
+  IsNull  *NullFilter    `json:"is_null"`
+  And     []*FilterInput `json:"and"`
+  Or      []*FilterInput `json:"or"`
+  Not     *FilterInput   `json:"not"`
 }
 
 type BorderFilter struct {
@@ -44,6 +89,10 @@ type ListFilter struct {
   Values []any  `json:"values"`
 }
 
+type NullFilter struct {
+  Field string `json:"field"`
+}
+
 type WithFields struct {
   Media bool `json:"media"`
 }
@@ -58,6 +107,16 @@ func (p *PaginationInput) Option() *storage.PaginationOption {
   }
 }
 
+func (c *CursorInput) Option() *storage.CursorOption {
+  if c == nil {
+    return nil
+  }
+  return &storage.CursorOption{
+    After: c.After,
+    Count: c.Count,
+  }
+}
+
 func (s *SortInput) Option() *storage.SortOption {
   if s == nil {
     return nil
@@ -77,6 +136,8 @@ func (f *BorderFilter) Option() *storage.BorderFilter {
   switch f.Compare {
   case "eq":
     compare = storage.EqTokenizer{}
+  case "neq":
+    compare = storage.NeqTokenizer{}
   case "gt":
     compare = storage.GtTokenizer{}
   case "gte":
@@ -85,6 +146,10 @@ func (f *BorderFilter) Option() *storage.BorderFilter {
     compare = storage.LtTokenizer{}
   case "lte":
     compare = storage.LteTokenizer{}
+  case "like":
+    compare = storage.LikeTokenizer{}
+  case "ilike":
+    compare = storage.IlikeTokenizer{}
   }
 
   return &storage.BorderFilter{
@@ -115,20 +180,61 @@ func (f *ListFilter) Option() *storage.ListFilter {
   }
 }
 
-func (g *GetInput) ParseOption() *storage.GetOption {
-  filters := make([]*storage.FilterPart, 0, len(g.Filters))
+func (f *NullFilter) Option() *storage.NullFilter {
+  if f == nil {
+    return nil
+  }
+  return &storage.NullFilter{
+    Field: f.Field,
+  }
+}
+
+func (f *FilterInput) Option() *storage.FilterPart {
+  if f == nil {
+    return nil
+  }
+  return &storage.FilterPart{
+    Border:  f.Border.Option(),
+    Between: f.Between.Option(),
+    List:    f.List.Option(),
+    NotIn:   f.NotIn.Option(),
+    IsNull:  f.IsNull.Option(),
+    And:     filtersOption(f.And),
+    Or:      filtersOption(f.Or),
+    Not:     f.Not.Option(),
+  }
+}
 
-  for _, filter := range g.Filters {
-    filters = append(filters, &storage.FilterPart{
-      Border:  filter.Border.Option(),
-      Between: filter.Between.Option(),
-      List:    filter.List.Option(),
-    })
+// filtersOption keep nil group as nil, so not specified group is not counted as filter type
+func filtersOption(filters []*FilterInput) []*storage.FilterPart {
+  if filters == nil {
+    return nil
   }
+  parts := make([]*storage.FilterPart, 0, len(filters))
+
+  for _, filter := range filters {
+    parts = append(parts, filter.Option())
+  }
+  return parts
+}
+
+func (g *GetInput) ParseOption() *storage.GetOption {
   return &storage.GetOption{
     Pagination: g.Pagination.Option(),
+    Cursor:     g.Cursor.Option(),
     Sort:       g.Sort.Option(),
-    Filters:    filters,
+    Filters:    filtersOption(g.Filters),
+  }
+}
+
+func (a *AggregateStocksInput) ParseOption() *storage.AggregateOption {
+  return &storage.AggregateOption{
+    TickerId:   a.TickerId,
+    Period:     a.Period,
+    From:       a.From,
+    To:         a.To,
+    Adjusted:   a.Adjusted,
+    Pagination: a.Pagination.Option(),
   }
 }
 
@@ -142,4 +248,7 @@ func (w *WithFields) HasMedia() bool {
 type CalculatePagesInput struct {
   Resource string
   PageSize int
+  // granularity of the stocks resource
+  Timespan   string
+  Multiplier int
 }
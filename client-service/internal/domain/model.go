@@ -8,18 +8,24 @@ type Ticker struct {
 }
 
 type TickerFields struct {
-  TickerId           string    `json:"ticker_id"`
-  CompanyName        string    `json:"company_name"`
-  CompanyLocale      string    `json:"company_locale"`
-  CompanyDescription string    `json:"company_description"`
-  CompanyState       string    `json:"company_state"`
-  CompanyCity        string    `json:"company_city"`
-  CompanyAddress     string    `json:"company_address"`
-  HomepageUrl        string    `json:"homepage_url"`
-  CurrencyName       string    `json:"currency_name"`
-  TotalEmployees     int       `json:"total_employees"`
-  Active             bool      `json:"active"`
-  CreatedAt          time.Time `json:"created_at"`
+  TickerId           string     `json:"ticker_id"`
+  CompanyName        string     `json:"company_name"`
+  CompanyLocale      string     `json:"company_locale"`
+  CompanyDescription string     `json:"company_description"`
+  CompanyState       string     `json:"company_state"`
+  CompanyCity        string     `json:"company_city"`
+  CompanyAddress     string     `json:"company_address"`
+  HomepageUrl        string     `json:"homepage_url"`
+  CurrencyName       string     `json:"currency_name"`
+  TotalEmployees     int        `json:"total_employees"`
+  SicCode            string     `json:"sic_code"`
+  SicDescription     string     `json:"sic_description"`
+  Sector             string     `json:"sector"`
+  PrimaryExchange    string     `json:"primary_exchange"`
+  Market             string     `json:"market"`
+  ListDate           *time.Time `json:"list_date"`
+  Active             bool       `json:"active"`
+  CreatedAt          time.Time  `json:"created_at"`
 }
 
 type TickerMedia struct {
@@ -35,10 +41,55 @@ type Stock struct {
   HighestPrice  float64   `json:"highest_price"`
   LowestPrice   float64   `json:"lowest_price"`
   TradingVolume int       `json:"trading_volume"`
+  Adjusted      bool      `json:"adjusted"`
+  Timespan      string    `json:"timespan"`
+  Multiplier    int       `json:"multiplier"`
   StockedAt     time.Time `json:"stocked_time"`
   CreatedAt     time.Time `json:"created_at"`
 }
 
+type AggregatedStock struct {
+  TickerId         string    `json:"ticker_id"`
+  Period           string    `json:"period"`
+  PeriodStart      time.Time `json:"period_start"`
+  OpenPrice        float64   `json:"open_price"`
+  ClosePrice       float64   `json:"close_price"`
+  HighestPrice     float64   `json:"highest_price"`
+  LowestPrice      float64   `json:"lowest_price"`
+  TradingVolume    float64   `json:"trading_volume"`
+  Adjusted         bool      `json:"adjusted"`
+  BarsCount        int       `json:"bars_count"`
+  FirstStockedTime time.Time `json:"first_stocked_time"`
+  LastStockedTime  time.Time `json:"last_stocked_time"`
+}
+
+type Indicators struct {
+  TickerId string             `json:"ticker_id"`
+  Times    []time.Time        `json:"times"`
+  Series   []*IndicatorSeries `json:"series"`
+}
+
+type IndicatorSeries struct {
+  Indicator *IndicatorInput       `json:"indicator"`
+  Lines     map[string][]*float64 `json:"lines"`
+}
+
+type Financial struct {
+  TickerId          string     `json:"ticker_id"`
+  Timeframe         string     `json:"timeframe"`
+  FiscalPeriod      string     `json:"fiscal_period"`
+  FiscalYear        int        `json:"fiscal_year"`
+  StartDate         time.Time  `json:"start_date"`
+  EndDate           time.Time  `json:"end_date"`
+  FilingDate        *time.Time `json:"filing_date"`
+  Revenue           *float64   `json:"revenue"`
+  NetIncome         *float64   `json:"net_income"`
+  BasicEps          *float64   `json:"basic_eps"`
+  DilutedEps        *float64   `json:"diluted_eps"`
+  SharesOutstanding *float64   `json:"shares_outstanding"`
+  UpdatedAt         time.Time  `json:"updated_at"`
+}
+
 type Subscription struct {
   UserId     string    `json:"user_id"`
   TickerId   string    `json:"ticker_id"`
@@ -18,7 +18,6 @@ import (
   "github.com/UshakovN/stock-predictor-service/errs"
   "github.com/UshakovN/stock-predictor-service/swagger"
   "github.com/UshakovN/stock-predictor-service/utils"
-  log "github.com/sirupsen/logrus"
 )
 
 type Handler struct {
@@ -33,6 +32,10 @@ func (h *Handler) BindRouter() {
   http.Handle("/tickers", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleTickers)))
   http.Handle("/stocks", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleStocks)))
   http.Handle("/stocks/pages", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleStocksPages)))
+  http.Handle("/stocks/aggregate", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleStocksAggregate)))
+  http.Handle("/indicators", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleIndicators)))
+  http.Handle("/fundamentals", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleFundamentals)))
+  http.Handle("/fundamentals/pages", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleFundamentalsPages)))
   http.Handle("/subscribe", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleSubscribe)))
   http.Handle("/unsubscribe", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleUnsubscribe)))
   http.Handle("/subscriptions", errs.MiddlewareErr(h.auth.AuthMiddleware(h.HandleSubscriptions)))
@@ -86,6 +89,12 @@ func (h *Handler) HandleTickersPages(w http.ResponseWriter, r *http.Request) err
 //
 // @Summary Tickers model method
 // @Description Tickers method provide tickers models for client with pagination, filtration, sorting and media fields
+// @Description With 'cursor' instead of 'pagination' tickers are paged by keyset, 'next_cursor' of the response
+// @Description is passed as cursor 'after' for the next page. Nullable fields cannot be sort field in cursor mode
+// @Description Filters are joined by 'and', filter can be 'and', 'or' and 'not' group of the nested filters.
+// @Description Border compare is one of 'eq', 'neq', 'gt', 'gte', 'lt', 'lte', 'like' and 'ilike', like compares match value prefix
+// @Description Tickers can be filtered and sorted by 'sector', 'primary_exchange', 'market' and 'list_date',
+// @Description e.g. list filter by 'sector' value 'technology' and 'primary_exchange' value 'XNAS' for NASDAQ tech tickers
 // @Tags Resources
 // @Produce            application/json
 // @Param request body clientservice.TickersRequest true "Request"
@@ -108,7 +117,7 @@ func (h *Handler) HandleTickers(w http.ResponseWriter, r *http.Request) error {
   if err := utils.FillFrom(req, input); err != nil {
     return err
   }
-  tickers, err := h.service.GetTickers(input)
+  tickers, nextCursor, err := h.service.GetTickers(input)
   if err != nil {
     return err
   }
@@ -118,8 +127,9 @@ func (h *Handler) HandleTickers(w http.ResponseWriter, r *http.Request) error {
     return err
   }
   resp.ResourceResponse = &clientservice.ResourceResponse{
-    Success: true,
-    Count:   len(resp.Tickers),
+    Success:    true,
+    Count:      len(resp.Tickers),
+    NextCursor: nextCursor,
   }
   if err := utils.WriteResponse(w, resp, http.StatusOK); err != nil {
     return err
@@ -131,22 +141,40 @@ func (h *Handler) HandleTickers(w http.ResponseWriter, r *http.Request) error {
 //
 // @Summary Stocks pages method
 // @Description Stocks pages method calculate total stocks pages count for specified page size
+// @Description Stocks are counted by granularity, default is daily bars (timespan 'day', multiplier 1)
 // @Tags Resources
 // @Produce            application/json
-// @Param request query clientservice.PagesRequest true "Request"
+// @Param request query clientservice.StocksPagesRequest true "Request"
 // @Success 200 {object} clientservice.PagesResponse
 // @Failure 400,401,403,500 {object} errs.Error
 // @Security ApiKeyAuth
 // @Router /stocks/pages [get]
 //
 func (h *Handler) HandleStocksPages(w http.ResponseWriter, r *http.Request) error {
-  return h.handleCalculatePages(service.CalculatePagesResourceStock)(w, r)
+  req := &clientservice.StocksPagesRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := req.Validate(); err != nil {
+    return err
+  }
+  return h.calculatePages(w, &domain.CalculatePagesInput{
+    Resource:   service.CalculatePagesResourceStock,
+    PageSize:   req.PageSize,
+    Timespan:   req.Timespan,
+    Multiplier: req.Multiplier,
+  })
 }
 
 // HandleStocks
 //
 // @Summary Stocks model method
-// @Description Stocks method provide stocks models for client with pagination, filtration, sorting
+// @Description Stocks method provide stocks models for client with pagination, filtration, sorting.
+// @Description Stocks are filtered by granularity, default is daily bars (timespan 'day', multiplier 1)
+// @Description Raw bars are returned by default, split adjusted bars are returned with 'adjusted' flag
+// @Description With 'cursor' instead of 'pagination' stocks are paged by keyset, 'next_cursor' of the response
+// @Description is passed as cursor 'after' for the next page
 // @Tags Resources
 // @Produce            application/json
 // @Param request body clientservice.StocksRequest true "Request"
@@ -161,20 +189,78 @@ func (h *Handler) HandleStocks(w http.ResponseWriter, r *http.Request) error {
   if err := utils.ReadRequest(r, req); err != nil {
     return err
   }
+  if err := req.Validate(); err != nil {
+    return err
+  }
   if err := confirmResourceRequest(r, req.ResourceRequest); err != nil {
     return err
   }
-  input := &domain.GetInput{}
+  input := &domain.GetStocksInput{
+    GetInput: &domain.GetInput{},
+  }
 
   if err := utils.FillFrom(req, input); err != nil {
     return err
   }
-  stocks, err := h.service.GetStocks(input)
+  stocks, nextCursor, err := h.service.GetStocks(input)
   if err != nil {
     return err
   }
   resp := &clientservice.StocksResponse{}
 
+  if err := utils.FillFrom(stocks, &resp.Stocks); err != nil {
+    return err
+  }
+  resp.ResourceResponse = &clientservice.ResourceResponse{
+    Success:    true,
+    Count:      len(resp.Stocks),
+    NextCursor: nextCursor,
+  }
+  if err := utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+// HandleStocksAggregate
+//
+// @Summary Stocks aggregate method
+// @Description Stocks aggregate method provide daily bars of the ticker resampled to 'week', 'month' or 'quarter' period
+// @Description for the date range: first open, max high, min low, last close and summed volume of the period.
+// @Description Partial periods on the range borders are aggregated from the bars inside the range
+// @Tags Resources
+// @Produce            application/json
+// @Param request body clientservice.StocksAggregateRequest true "Request"
+// @Success 200 {object} clientservice.StocksAggregateResponse
+// @Failure 400,401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /stocks/aggregate [post]
+//
+func (h *Handler) HandleStocksAggregate(w http.ResponseWriter, r *http.Request) error {
+  req := &clientservice.StocksAggregateRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := req.Validate(); err != nil {
+    return err
+  }
+  if err := confirmResourceRequest(r, &clientservice.ResourceRequest{
+    Pagination: req.Pagination,
+  }); err != nil {
+    return err
+  }
+  input := &domain.AggregateStocksInput{}
+
+  if err := utils.FillFrom(req, input); err != nil {
+    return err
+  }
+  stocks, err := h.service.GetAggregatedStocks(input)
+  if err != nil {
+    return err
+  }
+  resp := &clientservice.StocksAggregateResponse{}
+
   if err := utils.FillFrom(stocks, &resp.Stocks); err != nil {
     return err
   }
@@ -188,6 +274,113 @@ func (h *Handler) HandleStocks(w http.ResponseWriter, r *http.Request) error {
   return nil
 }
 
+// HandleIndicators
+//
+// @Summary Indicators method
+// @Description Indicators method provide technical indicators series of the ticker daily bars for the date range:
+// @Description 'sma', 'ema', 'rsi', 'macd', 'bollinger', 'atr' and 'obv'. Not specified indicator params are defaulted,
+// @Description series values are null until indicator has enough bars for the period
+// @Tags Resources
+// @Produce            application/json
+// @Param request body clientservice.IndicatorsRequest true "Request"
+// @Success 200 {object} clientservice.IndicatorsResponse
+// @Failure 400,401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /indicators [post]
+//
+func (h *Handler) HandleIndicators(w http.ResponseWriter, r *http.Request) error {
+  req := &clientservice.IndicatorsRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := req.Validate(); err != nil {
+    return err
+  }
+  input := &domain.IndicatorsInput{}
+
+  if err := utils.FillFrom(req, input); err != nil {
+    return err
+  }
+  indicators, err := h.service.GetIndicators(input)
+  if err != nil {
+    return err
+  }
+  resp := &clientservice.IndicatorsResponse{
+    Success: true,
+  }
+  if err = utils.FillFrom(indicators, resp); err != nil {
+    return err
+  }
+  if err = utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+// HandleFundamentalsPages
+//
+// @Summary Fundamentals pages method
+// @Description Fundamentals pages method calculate total fundamentals pages count for specified page size
+// @Tags Resources
+// @Produce            application/json
+// @Param request query clientservice.PagesRequest true "Request"
+// @Success 200 {object} clientservice.PagesResponse
+// @Failure 400,401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /fundamentals/pages [get]
+//
+func (h *Handler) HandleFundamentalsPages(w http.ResponseWriter, r *http.Request) error {
+  return h.handleCalculatePages(service.CalculatePagesResourceFinancial)(w, r)
+}
+
+// HandleFundamentals
+//
+// @Summary Fundamentals model method
+// @Description Fundamentals method provide quarterly and annual financials of tickers for client with pagination, filtration, sorting.
+// @Description Metrics not reported for the fiscal period are null
+// @Tags Resources
+// @Produce            application/json
+// @Param request body clientservice.FundamentalsRequest true "Request"
+// @Success 200 {object} clientservice.FundamentalsResponse
+// @Failure 400,401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /fundamentals [post]
+//
+func (h *Handler) HandleFundamentals(w http.ResponseWriter, r *http.Request) error {
+  req := &clientservice.FundamentalsRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := confirmResourceRequest(r, req.ResourceRequest); err != nil {
+    return err
+  }
+  input := &domain.GetInput{}
+
+  if err := utils.FillFrom(req, input); err != nil {
+    return err
+  }
+  financials, nextCursor, err := h.service.GetFinancials(input)
+  if err != nil {
+    return err
+  }
+  resp := &clientservice.FundamentalsResponse{}
+
+  if err := utils.FillFrom(financials, &resp.Financials); err != nil {
+    return err
+  }
+  resp.ResourceResponse = &clientservice.ResourceResponse{
+    Success:    true,
+    Count:      len(resp.Financials),
+    NextCursor: nextCursor,
+  }
+  if err := utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
 // HandleSubscribe
 //
 // @Summary Subscribe method subscribe client to the ticker
@@ -358,11 +551,8 @@ func (h *Handler) HandleHealth(w http.ResponseWriter, _ *http.Request) error {
   return nil
 }
 
-func (h *Handler) ContinuouslyServeHttp(port string) {
-  err := http.ListenAndServe(fmt.Sprint(":", port), nil)
-  if err != nil {
-    log.Fatalf("listen and serve error: %v", err)
-  }
+func (h *Handler) Close() {
+  h.service.Close()
 }
 
 func (h *Handler) handleCalculatePages(resource string) func(w http.ResponseWriter, r *http.Request) error {
@@ -375,24 +565,33 @@ func (h *Handler) handleCalculatePages(resource string) func(w http.ResponseWrit
     if err := req.Validate(); err != nil {
       return err
     }
-    pagesCount, err := h.service.CalculatePages(&domain.CalculatePagesInput{
+    return h.calculatePages(w, &domain.CalculatePagesInput{
       Resource: resource,
       PageSize: req.PageSize,
     })
-    if err != nil {
-      return err
-    }
-    if err = utils.WriteResponse(w, &clientservice.PagesResponse{
-      Success:    true,
-      TotalCount: pagesCount,
-    }, http.StatusOK); err != nil {
-      return err
-    }
-    return nil
   }
 }
 
+func (h *Handler) calculatePages(w http.ResponseWriter, input *domain.CalculatePagesInput) error {
+  pagesCount, err := h.service.CalculatePages(input)
+  if err != nil {
+    return err
+  }
+  if err = utils.WriteResponse(w, &clientservice.PagesResponse{
+    Success:    true,
+    TotalCount: pagesCount,
+  }, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
 func confirmResourceRequest(r *http.Request, req *clientservice.ResourceRequest) error {
+  if req != nil {
+    if err := req.Validate(); err != nil {
+      return err
+    }
+  }
   serviceAccess, err := getServiceAccessFromReqCtx(r)
   if err != nil {
     return fmt.Errorf("cannot get service access from request content: %v", err)
@@ -403,9 +602,9 @@ func confirmResourceRequest(r *http.Request, req *clientservice.ResourceRequest)
   if req == nil {
     return errs.NewError(errs.ErrTypeMalformedRequest, nil)
   }
-  if req.Pagination == nil {
+  if req.Pagination == nil && req.Cursor == nil {
     return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
-      "pagination must be specified", nil)
+      "pagination or cursor must be specified", nil)
   }
   return nil
 }
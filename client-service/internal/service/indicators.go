@@ -0,0 +1,292 @@
+package service
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "main/internal/storage"
+  "math"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/errs"
+  "github.com/UshakovN/stock-predictor-service/indicators"
+)
+
+const (
+  indicatorSMA       = "sma"
+  indicatorEMA       = "ema"
+  indicatorRSI       = "rsi"
+  indicatorMACD      = "macd"
+  indicatorBollinger = "bollinger"
+  indicatorATR       = "atr"
+  indicatorOBV       = "obv"
+)
+
+const (
+  defaultAveragePeriod    = 20
+  defaultOscillatorPeriod = 14
+  defaultMACDFast         = 12
+  defaultMACDSlow         = 26
+  defaultMACDSignal       = 9
+  defaultBollingerWidth   = 2
+)
+
+const (
+  indicatorsTimespan   = "day"
+  indicatorsMultiplier = 1
+  // bars of the range are limited, so series are computed in memory
+  maxIndicatorsBars = 5000
+  // exponential averages are seeded by several periods of the bars before the range
+  warmupPeriods = 3
+  maxWarmupBars = 1000
+)
+
+const (
+  lineValue     = "value"
+  lineMACD      = "macd"
+  lineSignal    = "signal"
+  lineHistogram = "histogram"
+  lineMiddle    = "middle"
+  lineUpper     = "upper"
+  lineLower     = "lower"
+)
+
+// GetIndicators compute indicator series of the ticker daily bars for the date range.
+// bars before the range are used for warmup, so series are valid from the range start when history allows
+func (s *service) GetIndicators(input *domain.IndicatorsInput) (*domain.Indicators, error) {
+  for _, indicator := range input.Indicators {
+    defaultIndicator(indicator)
+  }
+  stocks, rangeStart, err := s.getIndicatorsStocks(input, warmupBars(input.Indicators))
+  if err != nil {
+    return nil, err
+  }
+  result := &domain.Indicators{
+    TickerId: input.TickerId,
+    Times:    make([]time.Time, 0, len(stocks)-rangeStart),
+    Series:   make([]*domain.IndicatorSeries, 0, len(input.Indicators)),
+  }
+  for _, stock := range stocks[rangeStart:] {
+    result.Times = append(result.Times, stock.Time)
+  }
+  for _, indicator := range input.Indicators {
+    lines, err := computeIndicator(indicator, stocks, rangeStart)
+    if err != nil {
+      if errs.ErrIs(err, indicators.ErrMalformedParam) {
+        return nil, errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+          fmt.Sprintf("indicator '%s': %v", indicator.Name, err), nil)
+      }
+      return nil, fmt.Errorf("cannot compute indicator '%s': %v", indicator.Name, err)
+    }
+    series := &domain.IndicatorSeries{
+      Indicator: indicator,
+      Lines:     make(map[string][]*float64, len(lines)),
+    }
+    for name, line := range lines {
+      series.Lines[name] = formIndicatorLine(line[rangeStart:])
+    }
+    result.Series = append(result.Series, series)
+  }
+  return result, nil
+}
+
+func defaultIndicator(indicator *domain.IndicatorInput) {
+  switch indicator.Name {
+  case indicatorSMA, indicatorEMA, indicatorBollinger:
+    if indicator.Period == 0 {
+      indicator.Period = defaultAveragePeriod
+    }
+  case indicatorRSI, indicatorATR:
+    if indicator.Period == 0 {
+      indicator.Period = defaultOscillatorPeriod
+    }
+  case indicatorMACD:
+    if indicator.Fast == 0 {
+      indicator.Fast = defaultMACDFast
+    }
+    if indicator.Slow == 0 {
+      indicator.Slow = defaultMACDSlow
+    }
+    if indicator.Signal == 0 {
+      indicator.Signal = defaultMACDSignal
+    }
+  }
+  if indicator.Name == indicatorBollinger && indicator.Width == 0 {
+    indicator.Width = defaultBollingerWidth
+  }
+}
+
+// warmupBars return count of the bars before the range required by the indicators
+func warmupBars(inputs []*domain.IndicatorInput) int {
+  var warmup int
+
+  for _, indicator := range inputs {
+    var bars int
+
+    switch indicator.Name {
+    case indicatorSMA, indicatorBollinger:
+      bars = indicator.Period
+    case indicatorEMA, indicatorRSI, indicatorATR:
+      bars = warmupPeriods * indicator.Period
+    case indicatorMACD:
+      bars = warmupPeriods * (indicator.Slow + indicator.Signal)
+    }
+    if bars > warmup {
+      warmup = bars
+    }
+  }
+  if warmup > maxWarmupBars {
+    warmup = maxWarmupBars
+  }
+  return warmup
+}
+
+// getIndicatorsStocks return warmup bars followed by the range bars and index of the first range bar
+func (s *service) getIndicatorsStocks(input *domain.IndicatorsInput, warmup int) ([]indicators.Stock, int, error) {
+  filters := append(granularityFilters(indicatorsTimespan, indicatorsMultiplier),
+    &storage.FilterPart{
+      Border: &storage.BorderFilter{
+        Field:   "ticker_id",
+        Value:   input.TickerId,
+        Compare: storage.EqTokenizer{},
+      },
+    },
+  )
+  // filters are shared by range and warmup queries, so they are copied on append
+  stored, _, err := s.storage.GetStocks(&storage.GetOption{
+    Pagination: &storage.PaginationOption{
+      Page:  1,
+      Count: maxIndicatorsBars + 1,
+    },
+    Sort: &storage.SortOption{
+      Field: "stocked_at",
+      Order: storage.SortOrderAsc,
+    },
+    Filters: append(filters[:len(filters):len(filters)], &storage.FilterPart{
+      Between: &storage.BetweenFilter{
+        Field:       "stocked_at",
+        LeftBorder:  input.From.UTC().Format(time.RFC3339Nano),
+        RightBorder: input.To.UTC().Format(time.RFC3339Nano),
+      },
+    }),
+  })
+  if err := handleStorageError(err); err != nil {
+    return nil, 0, err
+  }
+  if len(stored) > maxIndicatorsBars {
+    return nil, 0, errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("date range must contain at most %d bars", maxIndicatorsBars), nil)
+  }
+  var warmupStored []*storage.Stock
+
+  if warmup > 0 && len(stored) != 0 {
+    warmupStored, _, err = s.storage.GetStocks(&storage.GetOption{
+      Pagination: &storage.PaginationOption{
+        Page:  1,
+        Count: warmup,
+      },
+      Sort: &storage.SortOption{
+        Field: "stocked_at",
+        Order: storage.SortOrderDesc,
+      },
+      Filters: append(filters[:len(filters):len(filters)], &storage.FilterPart{
+        Border: &storage.BorderFilter{
+          Field:   "stocked_at",
+          Value:   input.From.UTC().Format(time.RFC3339Nano),
+          Compare: storage.LtTokenizer{},
+        },
+      }),
+    })
+    if err := handleStorageError(err); err != nil {
+      return nil, 0, err
+    }
+  }
+  stocks := make([]indicators.Stock, 0, len(warmupStored)+len(stored))
+
+  // warmup bars are queried in descending order
+  for idx := len(warmupStored) - 1; idx >= 0; idx-- {
+    stocks = append(stocks, formIndicatorStock(warmupStored[idx], input.Adjusted))
+  }
+  for _, stored := range stored {
+    stocks = append(stocks, formIndicatorStock(stored, input.Adjusted))
+  }
+  return stocks, len(warmupStored), nil
+}
+
+func formIndicatorStock(stored *storage.Stock, adjusted bool) indicators.Stock {
+  stock := formStock(stored, adjusted)
+
+  return indicators.Stock{
+    Time:   stock.StockedAt,
+    Open:   stock.OpenPrice,
+    High:   stock.HighestPrice,
+    Low:    stock.LowestPrice,
+    Close:  stock.ClosePrice,
+    Volume: float64(stock.TradingVolume),
+  }
+}
+
+// computeIndicator return named lines of the indicator for the stocks with warmup bars before the range start
+func computeIndicator(indicator *domain.IndicatorInput, stocks []indicators.Stock, rangeStart int) (map[string][]float64, error) {
+  switch indicator.Name {
+  case indicatorSMA:
+    line, err := indicators.SMA(indicators.Closes(stocks), indicator.Period)
+    return map[string][]float64{lineValue: line}, err
+  case indicatorEMA:
+    line, err := indicators.EMA(indicators.Closes(stocks), indicator.Period)
+    return map[string][]float64{lineValue: line}, err
+  case indicatorRSI:
+    line, err := indicators.RSI(stocks, indicator.Period)
+    return map[string][]float64{lineValue: line}, err
+  case indicatorATR:
+    line, err := indicators.ATR(stocks, indicator.Period)
+    return map[string][]float64{lineValue: line}, err
+  case indicatorOBV:
+    line := indicators.OBV(stocks)
+
+    // warmup depend on the other requested indicators, so volume is accumulated from the range start
+    if rangeStart < len(line) {
+      base := line[rangeStart]
+
+      for idx := range line {
+        line[idx] -= base
+      }
+    }
+    return map[string][]float64{lineValue: line}, nil
+  case indicatorMACD:
+    series, err := indicators.MACD(stocks, indicator.Fast, indicator.Slow, indicator.Signal)
+    if err != nil {
+      return nil, err
+    }
+    return map[string][]float64{
+      lineMACD:      series.MACD,
+      lineSignal:    series.Signal,
+      lineHistogram: series.Histogram,
+    }, nil
+  case indicatorBollinger:
+    series, err := indicators.Bollinger(stocks, indicator.Period, indicator.Width)
+    if err != nil {
+      return nil, err
+    }
+    return map[string][]float64{
+      lineMiddle: series.Middle,
+      lineUpper:  series.Upper,
+      lineLower:  series.Lower,
+    }, nil
+  default:
+    return nil, fmt.Errorf("unknown indicator '%s'", indicator.Name)
+  }
+}
+
+// formIndicatorLine replace NaN values before the first full period with null
+func formIndicatorLine(line []float64) []*float64 {
+  values := make([]*float64, 0, len(line))
+
+  for idx := range line {
+    if math.IsNaN(line[idx]) {
+      values = append(values, nil)
+      continue
+    }
+    values = append(values, &line[idx])
+  }
+  return values
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "testing"
+
+  "github.com/UshakovN/stock-predictor-service/indicators"
+)
+
+func TestComputeOBVFromRangeStart(t *testing.T) {
+  closes := []float64{10, 11, 10.5, 10.5, 12, 11}
+  volumes := []float64{100, 200, 150, 300, 250, 400}
+
+  stocks := make([]indicators.Stock, 0, len(closes))
+
+  for idx := range closes {
+    stocks = append(stocks, indicators.Stock{Close: closes[idx], Volume: volumes[idx]})
+  }
+  obv := &domain.IndicatorInput{Name: indicatorOBV}
+
+  // the same range is requested with different count of the warmup bars
+  var expected string
+
+  for _, rangeStart := range []int{0, 1, 2} {
+    lines, err := computeIndicator(obv, stocks[2-rangeStart:], rangeStart)
+    if err != nil {
+      t.Fatalf("cannot compute obv: %v", err)
+    }
+    line := fmt.Sprint(lines[lineValue][rangeStart:])
+
+    if rangeStart == 0 {
+      expected = line
+      continue
+    }
+    if line != expected {
+      t.Errorf("expected obv %s with %d warmup bars, got %s", expected, rangeStart, line)
+    }
+  }
+  if expected != "[0 0 250 -150]" {
+    t.Errorf("expected obv accumulated from zero at the range start, got %s", expected)
+  }
+}
+
+func TestWarmupBars(t *testing.T) {
+  testCases := []struct {
+    inputs []*domain.IndicatorInput
+    warmup int
+  }{
+    {
+      inputs: []*domain.IndicatorInput{{Name: indicatorOBV}},
+      warmup: 0,
+    },
+    {
+      inputs: []*domain.IndicatorInput{{Name: indicatorOBV}, {Name: indicatorSMA, Period: 20}},
+      warmup: 20,
+    },
+    {
+      inputs: []*domain.IndicatorInput{{Name: indicatorRSI, Period: 14}, {Name: indicatorMACD, Fast: 12, Slow: 26, Signal: 9}},
+      warmup: warmupPeriods * (26 + 9),
+    },
+    {
+      inputs: []*domain.IndicatorInput{{Name: indicatorEMA, Period: 500}},
+      warmup: maxWarmupBars,
+    },
+  }
+  for _, testCase := range testCases {
+    if warmup := warmupBars(testCase.inputs); warmup != testCase.warmup {
+      t.Errorf("expected %d warmup bars, got %d", testCase.warmup, warmup)
+    }
+  }
+}
@@ -5,8 +5,8 @@ import (
   "fmt"
   "main/internal/domain"
   "main/internal/storage"
-  "time"
 
+  "github.com/UshakovN/stock-predictor-service/calendar"
   mediaservice "github.com/UshakovN/stock-predictor-service/contract/media-service"
   "github.com/UshakovN/stock-predictor-service/errs"
   "github.com/UshakovN/stock-predictor-service/utils"
@@ -14,18 +14,23 @@ import (
 )
 
 const (
-  CalculatePagesResourceTicker = "tickers"
-  CalculatePagesResourceStock  = "stocks"
+  CalculatePagesResourceTicker    = "tickers"
+  CalculatePagesResourceStock     = "stocks"
+  CalculatePagesResourceFinancial = "fundamentals"
 )
 
 type ClientService interface {
   CalculatePages(input *domain.CalculatePagesInput) (int, error)
-  GetTickers(input *domain.GetInput) ([]*domain.Ticker, error)
-  GetStocks(input *domain.GetInput) ([]*domain.Stock, error)
+  GetTickers(input *domain.GetInput) ([]*domain.Ticker, string, error)
+  GetStocks(input *domain.GetStocksInput) ([]*domain.Stock, string, error)
+  GetFinancials(input *domain.GetInput) ([]*domain.Financial, string, error)
+  GetAggregatedStocks(input *domain.AggregateStocksInput) ([]*domain.AggregatedStock, error)
+  GetIndicators(input *domain.IndicatorsInput) (*domain.Indicators, error)
   Subscribe(userId, tickerId string) error
   Unsubscribe(userId, tickerId string) error
   GetSubscriptions(userId string, filterActive bool) ([]*domain.Subscription, error)
   GetStocksPredicts(userId string) (*domain.StocksPredicts, error)
+  Close()
 }
 
 type service struct {
@@ -42,10 +47,15 @@ func NewClientService(ctx context.Context, config *Config) ClientService {
   }
 }
 
-func (s *service) GetTickers(input *domain.GetInput) ([]*domain.Ticker, error) {
-  stored, err := s.storage.GetTickers(input.ParseOption())
+func (s *service) Close() {
+  s.storage.Close()
+}
+
+// GetTickers return tickers and cursor of the next page, cursor is empty without cursor option or on the last page
+func (s *service) GetTickers(input *domain.GetInput) ([]*domain.Ticker, string, error) {
+  stored, nextCursor, err := s.storage.GetTickers(input.ParseOption())
   if err := handleStorageError(err); err != nil {
-    return nil, err
+    return nil, "", err
   }
   tickers := make([]*domain.Ticker, 0, len(stored))
 
@@ -58,18 +68,81 @@ func (s *service) GetTickers(input *domain.GetInput) ([]*domain.Ticker, error) {
     }
   }
 
-  return tickers, nil
+  return tickers, nextCursor, nil
 }
 
-func (s *service) GetStocks(input *domain.GetInput) ([]*domain.Stock, error) {
-  stored, err := s.storage.GetStocks(input.ParseOption())
+func (s *service) GetStocks(input *domain.GetStocksInput) ([]*domain.Stock, string, error) {
+  option := input.ParseOption()
+  option.Filters = append(option.Filters, stocksGranularityFilters(input.Timespan, input.Multiplier)...)
+
+  stored, nextCursor, err := s.storage.GetStocks(option)
   if err := handleStorageError(err); err != nil {
-    return nil, err
+    return nil, "", err
   }
   stocks := make([]*domain.Stock, 0, len(stored))
 
   for _, stored := range stored {
-    stocks = append(stocks, formStock(stored))
+    stocks = append(stocks, formStock(stored, input.Adjusted))
+  }
+  return stocks, nextCursor, nil
+}
+
+// stocksGranularityFilters return filters of the requested stocks granularity, default is daily bars
+func stocksGranularityFilters(timespan string, multiplier int) []*storage.FilterPart {
+  const (
+    defaultTimespan   = "day"
+    defaultMultiplier = 1
+  )
+  if timespan == "" {
+    timespan = defaultTimespan
+  }
+  if multiplier == 0 {
+    multiplier = defaultMultiplier
+  }
+  return granularityFilters(timespan, multiplier)
+}
+
+func granularityFilters(timespan string, multiplier int) []*storage.FilterPart {
+  return []*storage.FilterPart{
+    {
+      Border: &storage.BorderFilter{
+        Field:   "timespan",
+        Value:   timespan,
+        Compare: storage.EqTokenizer{},
+      },
+    },
+    {
+      Border: &storage.BorderFilter{
+        Field:   "multiplier",
+        Value:   multiplier,
+        Compare: storage.EqTokenizer{},
+      },
+    },
+  }
+}
+
+func (s *service) GetFinancials(input *domain.GetInput) ([]*domain.Financial, string, error) {
+  stored, nextCursor, err := s.storage.GetFinancials(input.ParseOption())
+  if err := handleStorageError(err); err != nil {
+    return nil, "", err
+  }
+  financials := make([]*domain.Financial, 0, len(stored))
+
+  for _, stored := range stored {
+    financials = append(financials, formFinancial(stored))
+  }
+  return financials, nextCursor, nil
+}
+
+func (s *service) GetAggregatedStocks(input *domain.AggregateStocksInput) ([]*domain.AggregatedStock, error) {
+  stored, err := s.storage.GetAggregatedStocks(input.ParseOption())
+  if err := handleStorageError(err); err != nil {
+    return nil, err
+  }
+  stocks := make([]*domain.AggregatedStock, 0, len(stored))
+
+  for _, stored := range stored {
+    stocks = append(stocks, formAggregatedStock(stored, input))
   }
   return stocks, nil
 }
@@ -87,13 +160,36 @@ func formTicker(stored *storage.Ticker) *domain.Ticker {
       HomepageUrl:        stored.HomepageUrl,
       CurrencyName:       stored.CurrencyName,
       TotalEmployees:     stored.TotalEmployees,
+      SicCode:            stored.SicCode,
+      SicDescription:     stored.SicDescription,
+      Sector:             stored.Sector,
+      PrimaryExchange:    stored.PrimaryExchange,
+      Market:             stored.Market,
+      ListDate:           stored.ListDate,
       Active:             stored.Active,
       CreatedAt:          stored.CreatedAt,
     },
   }
 }
 
-func formStock(stored *storage.Stock) *domain.Stock {
+// formStock return raw or split adjusted series of the stored stock
+func formStock(stored *storage.Stock, adjusted bool) *domain.Stock {
+  if adjusted {
+    return &domain.Stock{
+      StockId:       stored.StockId,
+      TickerId:      stored.TickerId,
+      OpenPrice:     stored.AdjOpenPrice,
+      ClosePrice:    stored.AdjClosePrice,
+      HighestPrice:  stored.AdjHighestPrice,
+      LowestPrice:   stored.AdjLowestPrice,
+      TradingVolume: stored.AdjTradingVolume,
+      Adjusted:      true,
+      Timespan:      stored.Timespan,
+      Multiplier:    stored.Multiplier,
+      StockedAt:     stored.StockedAt,
+      CreatedAt:     stored.CreatedAt,
+    }
+  }
   return &domain.Stock{
     StockId:       stored.StockId,
     TickerId:      stored.TickerId,
@@ -102,15 +198,37 @@ func formStock(stored *storage.Stock) *domain.Stock {
     HighestPrice:  stored.HighestPrice,
     LowestPrice:   stored.LowestPrice,
     TradingVolume: stored.TradingVolume,
+    Timespan:      stored.Timespan,
+    Multiplier:    stored.Multiplier,
     StockedAt:     stored.StockedAt,
     CreatedAt:     stored.CreatedAt,
   }
 }
 
+func formFinancial(stored *storage.Financial) *domain.Financial {
+  return &domain.Financial{
+    TickerId:          stored.TickerId,
+    Timeframe:         stored.Timeframe,
+    FiscalPeriod:      stored.FiscalPeriod,
+    FiscalYear:        stored.FiscalYear,
+    StartDate:         stored.StartDate,
+    EndDate:           stored.EndDate,
+    FilingDate:        stored.FilingDate,
+    Revenue:           stored.Revenue,
+    NetIncome:         stored.NetIncome,
+    BasicEps:          stored.BasicEps,
+    DilutedEps:        stored.DilutedEps,
+    SharesOutstanding: stored.SharesOutstanding,
+    UpdatedAt:         stored.UpdatedAt,
+  }
+}
+
 func handleStorageError(err error) error {
   if errs.ErrIs(err,
     storage.ErrMalformedPagination,
-    storage.ErrMalformedFilter,
+    storage.ErrMalformedCursor,
+    storage.ErrMalformedPeriod,
+    storage.ErrMalformedSort,
     storage.ErrMalformedFilter,
     storage.ErrMustContainOneFilterType,
   ) {
@@ -227,7 +345,10 @@ func (s *service) GetSubscriptions(userId string, filterActive bool) ([]*domain.
 }
 
 func (s *service) GetStocksPredicts(userId string) (*domain.StocksPredicts, error) {
-  stored, err := s.storage.GetStocksPredicts(userId, utils.NowDateUTC().Add(24*time.Hour))
+  // predictions are made for the next exchange session
+  datePredict := calendar.NextTradingDay(calendar.Today())
+
+  stored, err := s.storage.GetStocksPredicts(userId, datePredict)
   if err != nil {
     if errs.ErrIs(err, storage.ErrNotFoundInStorage) {
       return nil, errs.NewError(errs.ErrTypeNotFoundContent, nil)
@@ -240,7 +361,7 @@ func (s *service) GetStocksPredicts(userId string) (*domain.StocksPredicts, erro
 }
 
 func (s *service) mustFoundTicker(tickerId string) error {
-  tickers, err := s.storage.GetTickers(s.storage.GetOptionForTicker(tickerId))
+  tickers, _, err := s.storage.GetTickers(s.storage.GetOptionForTicker(tickerId))
   if err != nil {
     return fmt.Errorf("cannot get tickers from storage: %v", err)
   }
@@ -259,6 +380,23 @@ func formSubscription(stored *storage.Subscription) *domain.Subscription {
   }
 }
 
+func formAggregatedStock(stored *storage.AggregatedStock, input *domain.AggregateStocksInput) *domain.AggregatedStock {
+  return &domain.AggregatedStock{
+    TickerId:         input.TickerId,
+    Period:           input.Period,
+    PeriodStart:      stored.PeriodStart,
+    OpenPrice:        stored.OpenPrice,
+    ClosePrice:       stored.ClosePrice,
+    HighestPrice:     stored.HighestPrice,
+    LowestPrice:      stored.LowestPrice,
+    TradingVolume:    stored.TradingVolume,
+    Adjusted:         input.Adjusted,
+    BarsCount:        stored.BarsCount,
+    FirstStockedTime: stored.FirstStockedTime,
+    LastStockedTime:  stored.LastStockedTime,
+  }
+}
+
 func formStockPredictions(stored *storage.StocksPredicts) *domain.StocksPredicts {
   parts := make([]*domain.Predict, 0, len(stored.Parts))
 
@@ -300,17 +438,22 @@ func formStockPredictions(stored *storage.StocksPredicts) *domain.StocksPredicts
 func (s *service) CalculatePages(input *domain.CalculatePagesInput) (int, error) {
   var (
     storageResource storage.Resource
+    filters         storage.FiltersOption
   )
   switch input.Resource {
   case CalculatePagesResourceTicker:
     storageResource = storage.ResourceTicker
   case CalculatePagesResourceStock:
     storageResource = storage.ResourceStock
+    // pages are counted for the same stocks as returned by stocks method
+    filters = stocksGranularityFilters(input.Timespan, input.Multiplier)
+  case CalculatePagesResourceFinancial:
+    storageResource = storage.ResourceFinancial
   default:
     return 0, errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
       "specified wrong resource", nil)
   }
-  pagesCount, err := s.storage.CalculatePages(storageResource, input.PageSize)
+  pagesCount, err := s.storage.CalculatePages(storageResource, input.PageSize, filters)
   if err != nil {
     return 0, fmt.Errorf("cannot calculate storage pages: %v", err)
   }
@@ -0,0 +1,122 @@
+package storage
+
+import (
+  "errors"
+  "fmt"
+  "time"
+
+  sq "github.com/Masterminds/squirrel"
+  "github.com/UshakovN/stock-predictor-service/postgres"
+)
+
+var ErrMalformedPeriod = errors.New("malformed aggregate period")
+
+const (
+  AggregatePeriodWeek    = "week"
+  AggregatePeriodMonth   = "month"
+  AggregatePeriodQuarter = "quarter"
+)
+
+const (
+  aggregateTimespan   = "day"
+  aggregateMultiplier = 1
+)
+
+// AggregateOption select daily bars of the ticker in the date range to resample them to the period
+type AggregateOption struct {
+  TickerId   string
+  Period     string
+  From       time.Time
+  To         time.Time
+  Adjusted   bool
+  Pagination *PaginationOption
+}
+
+// aggregatePeriodColumn return truncated bar time for the period. period is whitelisted,
+// because date_trunc field cannot be bound to the grouped expression
+func aggregatePeriodColumn(period string) (string, error) {
+  switch period {
+  case AggregatePeriodWeek, AggregatePeriodMonth, AggregatePeriodQuarter:
+    return fmt.Sprintf(`date_trunc('%s', stocked_at)`, period), nil
+  default:
+    return "", fmt.Errorf("%w: unknown period '%s'", ErrMalformedPeriod, period)
+  }
+}
+
+// GetAggregatedStocks resample daily bars of the ticker: first open, max high, min low, last close and summed volume
+func (s *storage) GetAggregatedStocks(option *AggregateOption) ([]*AggregatedStock, error) {
+  periodColumn, err := aggregatePeriodColumn(option.Period)
+  if err != nil {
+    return nil, err
+  }
+  var (
+    openColumn   = `open_price`
+    closeColumn  = `close_price`
+    highColumn   = `highest_price`
+    lowColumn    = `lowest_price`
+    volumeColumn = `trading_volume`
+  )
+  if option.Adjusted {
+    openColumn = `coalesce(adj_open_price, open_price)`
+    closeColumn = `coalesce(adj_close_price, close_price)`
+    highColumn = `coalesce(adj_highest_price, highest_price)`
+    lowColumn = `coalesce(adj_lowest_price, lowest_price)`
+    volumeColumn = `coalesce(adj_trading_volume, trading_volume)`
+  }
+  builder := postgres.NewSelectBuilder().
+    Columns(
+      periodColumn,
+      fmt.Sprintf(`((array_agg(%s order by stocked_at))[1])::float8`, openColumn),
+      fmt.Sprintf(`((array_agg(%s order by stocked_at desc))[1])::float8`, closeColumn),
+      fmt.Sprintf(`max(%s)::float8`, highColumn),
+      fmt.Sprintf(`min(%s)::float8`, lowColumn),
+      fmt.Sprintf(`sum(%s)::float8`, volumeColumn),
+      `count(*)`,
+      `min(stocked_at)`,
+      `max(stocked_at)`,
+    ).
+    From(`stock`).
+    Where(sq.Eq{
+      `ticker_id`:  option.TickerId,
+      `timespan`:   aggregateTimespan,
+      `multiplier`: aggregateMultiplier,
+    }).
+    Where(sq.Expr(`stocked_at between ? and ?`, option.From, option.To)).
+    GroupBy(periodColumn).
+    OrderBy(periodColumn)
+
+  if option.Pagination != nil {
+    if builder, err = option.Pagination.Apply(builder, nil); err != nil {
+      return nil, err
+    }
+  }
+  rows, err := s.doQuery(nil, builder)
+  if err != nil {
+    return nil, err
+  }
+  var stocks []*AggregatedStock
+
+  for {
+    stock := &AggregatedStock{}
+    found, err := scanQueriedRow(rows,
+      &stock.PeriodStart,
+      &stock.OpenPrice,
+      &stock.ClosePrice,
+      &stock.HighestPrice,
+      &stock.LowestPrice,
+      &stock.TradingVolume,
+      &stock.BarsCount,
+      &stock.FirstStockedTime,
+      &stock.LastStockedTime,
+    )
+    if err != nil {
+      return nil, fmt.Errorf("cannot scan queried row: %v", err)
+    }
+    if !found {
+      break
+    }
+    stocks = append(stocks, stock)
+  }
+
+  return stocks, nil
+}
@@ -5,17 +5,24 @@ type CompareTokenizer interface {
 }
 
 type (
-  EqTokenizer  struct{}
-  GtTokenizer  struct{}
-  GteTokenizer struct{}
-  LtTokenizer  struct{}
-  LteTokenizer struct{}
+  EqTokenizer    struct{}
+  NeqTokenizer   struct{}
+  GtTokenizer    struct{}
+  GteTokenizer   struct{}
+  LtTokenizer    struct{}
+  LteTokenizer   struct{}
+  LikeTokenizer  struct{}
+  IlikeTokenizer struct{}
 )
 
 func (EqTokenizer) Token() string {
   return "="
 }
 
+func (NeqTokenizer) Token() string {
+  return "<>"
+}
+
 func (GtTokenizer) Token() string {
   return ">"
 }
@@ -31,3 +38,12 @@ func (LtTokenizer) Token() string {
 func (LteTokenizer) Token() string {
   return "<="
 }
+
+// LikeTokenizer and IlikeTokenizer compare by value prefix, case sensitive and insensitive
+func (LikeTokenizer) Token() string {
+  return "like"
+}
+
+func (IlikeTokenizer) Token() string {
+  return "ilike"
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+  "encoding/base64"
+  "encoding/json"
+  "errors"
+  "fmt"
+
+  sq "github.com/Masterminds/squirrel"
+)
+
+var ErrMalformedCursor = errors.New("malformed cursor option")
+
+// CursorOption is keyset pagination. After is opaque cursor returned with the previous page,
+// empty After mean the first page
+type CursorOption struct {
+  After string
+  Count int
+}
+
+// cursorKey is sort key and id of the last row of the page
+type cursorKey struct {
+  Field string `json:"f,omitempty"`
+  Order string `json:"o,omitempty"`
+  Value string `json:"v,omitempty"`
+  Id    string `json:"id"`
+}
+
+func encodeCursor(key *cursorKey) (string, error) {
+  content, err := json.Marshal(key)
+  if err != nil {
+    return "", fmt.Errorf("cannot marshal cursor key: %v", err)
+  }
+  return base64.RawURLEncoding.EncodeToString(content), nil
+}
+
+func decodeCursor(cursor string) (*cursorKey, error) {
+  content, err := base64.RawURLEncoding.DecodeString(cursor)
+  if err != nil {
+    return nil, ErrMalformedCursor
+  }
+  key := &cursorKey{}
+
+  if err = json.Unmarshal(content, key); err != nil || key.Id == "" {
+    return nil, ErrMalformedCursor
+  }
+  return key, nil
+}
+
+// keysetPage collect keys of the queried rows to form the next cursor
+type keysetPage struct {
+  field string
+  order string
+  count int
+  keys  []*cursorKey
+}
+
+func (c *CursorOption) Apply(
+  builder sq.SelectBuilder,
+  columns *resourceColumns,
+  sort *SortOption,
+) (sq.SelectBuilder, *keysetPage, error) {
+  if c.Count < 1 {
+    return builder, nil, ErrMalformedCursor
+  }
+  page := &keysetPage{
+    order: SortOrderAsc,
+    count: c.Count,
+  }
+  var keyColumns []string
+
+  if sort != nil {
+    column, ok := columns.column(sort.Field, sortable)
+    if !ok {
+      return builder, nil, fmt.Errorf("%w: field '%s' is not sortable", ErrMalformedSort, sort.Field)
+    }
+    // null values cannot be compared, so rows with them are lost between pages
+    if column.flags&nullable != 0 {
+      return builder, nil, fmt.Errorf("%w: field '%s' is nullable and cannot be used with cursor",
+        ErrMalformedSort, sort.Field)
+    }
+    if sort.Order != SortOrderAsc && sort.Order != SortOrderDesc {
+      return builder, nil, ErrMalformedSort
+    }
+    page.field = sort.Field
+    page.order = sort.Order
+    keyColumns = append(keyColumns, column.name)
+  }
+  keyColumns = append(keyColumns, columns.id)
+
+  if c.After != "" {
+    key, err := decodeCursor(c.After)
+    if err != nil {
+      return builder, nil, err
+    }
+    // cursor is valid only with the same sort
+    if key.Field != page.field || key.Order != page.order {
+      return builder, nil, fmt.Errorf("%w: cursor does not match sort option", ErrMalformedCursor)
+    }
+    builder = builder.Where(keysetWhere(keyColumns, page.order, key))
+  }
+  for _, column := range keyColumns {
+    builder = builder.
+      Column(fmt.Sprint(column, "::text")).
+      OrderBy(fmt.Sprint(column, " ", page.order))
+  }
+  // extra row show that the next page exists
+  return builder.Limit(uint64(c.Count + 1)), page, nil
+}
+
+// keysetWhere return row comparison of the key columns with the cursor key.
+// key values are bound as text and converted by postgres to the column types
+func keysetWhere(keyColumns []string, order string, key *cursorKey) sq.Sqlizer {
+  compare := ">"
+  if order == SortOrderDesc {
+    compare = "<"
+  }
+  if len(keyColumns) == 1 {
+    return sq.Expr(fmt.Sprint(keyColumns[0], " ", compare, " ?"), key.Id)
+  }
+  return sq.Expr(fmt.Sprint("(", keyColumns[0], ", ", keyColumns[1], ") ", compare, " (?, ?)"),
+    key.Value, key.Id)
+}
+
+// dest append destinations of the selected key columns to the row fields
+func (p *keysetPage) dest(fields ...any) []any {
+  if p == nil {
+    return fields
+  }
+  key := &cursorKey{
+    Field: p.field,
+    Order: p.order,
+  }
+  p.keys = append(p.keys, key)
+
+  if p.field != "" {
+    fields = append(fields, &key.Value)
+  }
+  return append(fields, &key.Id)
+}
+
+// trimPage cut extra row of the keyset page and return cursor of the next page.
+// empty cursor mean the last page
+func trimPage[T any](items []T, page *keysetPage) ([]T, string, error) {
+  if page == nil || len(items) <= page.count {
+    return items, "", nil
+  }
+  cursor, err := encodeCursor(page.keys[page.count-1])
+  if err != nil {
+    return nil, "", err
+  }
+  return items[:page.count], cursor, nil
+}
@@ -0,0 +1,229 @@
+package storage
+
+import (
+  "encoding/base64"
+  "errors"
+  "fmt"
+  "testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+  for _, key := range []*cursorKey{
+    {Id: "AAPL-1677801600000"},
+    {Field: "stocked_at", Order: SortOrderDesc, Value: "2023-03-03 00:00:00+00", Id: "AAPL-1677801600000"},
+  } {
+    cursor, err := encodeCursor(key)
+    if err != nil {
+      t.Fatalf("cannot encode cursor: %v", err)
+    }
+    decoded, err := decodeCursor(cursor)
+    if err != nil {
+      t.Fatalf("cannot decode cursor '%s': %v", cursor, err)
+    }
+    if *decoded != *key {
+      t.Errorf("expected key %+v, got %+v", *key, *decoded)
+    }
+  }
+}
+
+func TestDecodeMalformedCursor(t *testing.T) {
+  encode := func(content string) string {
+    return base64.RawURLEncoding.EncodeToString([]byte(content))
+  }
+  for name, cursor := range map[string]string{
+    "garbage":         "not a cursor!",
+    "padded base64":   base64.URLEncoding.EncodeToString([]byte(`{"id":"AAPL"}`)),
+    "not json":        encode("AAPL"),
+    "without id":      encode(`{"f":"stocked_at","o":"asc","v":"2023-03-03"}`),
+    "wrong id type":   encode(`{"id":1}`),
+    "truncated json":  encode(`{"id":"AAPL"`),
+    "tampered cursor": encode(`{"id":"AAPL"}`)[1:],
+  } {
+    t.Run(name, func(t *testing.T) {
+      if key, err := decodeCursor(cursor); !errors.Is(err, ErrMalformedCursor) {
+        t.Errorf("expected malformed cursor error, got key %+v and error '%v'", key, err)
+      }
+    })
+  }
+}
+
+func TestCursorApply(t *testing.T) {
+  const selectStocks = "SELECT stock_id"
+
+  after := func(key *cursorKey) string {
+    cursor, err := encodeCursor(key)
+    if err != nil {
+      t.Fatalf("cannot encode cursor: %v", err)
+    }
+    return cursor
+  }
+  testCases := []struct {
+    name   string
+    cursor *CursorOption
+    sort   *SortOption
+    query  string
+    args   []any
+  }{
+    {
+      name:   "first page by id",
+      cursor: &CursorOption{Count: 10},
+      query:  selectStocks + ", stock.stock_id::text FROM stock ORDER BY stock.stock_id asc LIMIT 11",
+    },
+    {
+      name:   "next page by id",
+      cursor: &CursorOption{Count: 10, After: after(&cursorKey{Order: SortOrderAsc, Id: "AAPL-1"})},
+      query:  selectStocks + ", stock.stock_id::text FROM stock WHERE stock.stock_id > $1 ORDER BY stock.stock_id asc LIMIT 11",
+      args:   []any{"AAPL-1"},
+    },
+    {
+      name:   "next page ascending",
+      cursor: &CursorOption{Count: 5, After: after(&cursorKey{Field: "close_price", Order: SortOrderAsc, Value: "151.03", Id: "AAPL-1"})},
+      sort:   &SortOption{Field: "close_price", Order: SortOrderAsc},
+      query: selectStocks + ", stock.close_price::text, stock.stock_id::text FROM stock" +
+        " WHERE (stock.close_price, stock.stock_id) > ($1, $2)" +
+        " ORDER BY stock.close_price asc, stock.stock_id asc LIMIT 6",
+      args: []any{"151.03", "AAPL-1"},
+    },
+    {
+      name:   "next page descending",
+      cursor: &CursorOption{Count: 5, After: after(&cursorKey{Field: "stocked_at", Order: SortOrderDesc, Value: "2023-03-03", Id: "AAPL-1"})},
+      sort:   &SortOption{Field: "stocked_at", Order: SortOrderDesc},
+      query: selectStocks + ", stock.stocked_at::text, stock.stock_id::text FROM stock" +
+        " WHERE (stock.stocked_at, stock.stock_id) < ($1, $2)" +
+        " ORDER BY stock.stocked_at desc, stock.stock_id desc LIMIT 6",
+      args: []any{"2023-03-03", "AAPL-1"},
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      builder, page, err := testCase.cursor.Apply(newTestStocksBuilder(), stockColumns, testCase.sort)
+      if err != nil {
+        t.Fatalf("cannot apply cursor: %v", err)
+      }
+      if page == nil || page.count != testCase.cursor.Count {
+        t.Fatalf("expected keyset page of %d rows, got %+v", testCase.cursor.Count, page)
+      }
+      query, args, err := builder.ToSql()
+      if err != nil {
+        t.Fatalf("cannot build query: %v", err)
+      }
+      if query != testCase.query {
+        t.Errorf("expected query:\n%s\ngot:\n%s", testCase.query, query)
+      }
+      if fmt.Sprint(args) != fmt.Sprint(testCase.args) {
+        t.Errorf("expected args %v, got %v", testCase.args, args)
+      }
+    })
+  }
+}
+
+func TestCursorApplyErrors(t *testing.T) {
+  sortCursor, err := encodeCursor(&cursorKey{Field: "close_price", Order: SortOrderAsc, Value: "1", Id: "AAPL-1"})
+  if err != nil {
+    t.Fatalf("cannot encode cursor: %v", err)
+  }
+  testCases := []struct {
+    name    string
+    columns *resourceColumns
+    cursor  *CursorOption
+    sort    *SortOption
+    err     error
+  }{
+    {
+      name:    "not positive count",
+      columns: stockColumns,
+      cursor:  &CursorOption{},
+      err:     ErrMalformedCursor,
+    },
+    {
+      name:    "garbage cursor",
+      columns: stockColumns,
+      cursor:  &CursorOption{Count: 10, After: "garbage"},
+      err:     ErrMalformedCursor,
+    },
+    {
+      name:    "cursor of the other sort field",
+      columns: stockColumns,
+      cursor:  &CursorOption{Count: 10, After: sortCursor},
+      sort:    &SortOption{Field: "open_price", Order: SortOrderAsc},
+      err:     ErrMalformedCursor,
+    },
+    {
+      name:    "cursor of the other sort order",
+      columns: stockColumns,
+      cursor:  &CursorOption{Count: 10, After: sortCursor},
+      sort:    &SortOption{Field: "close_price", Order: SortOrderDesc},
+      err:     ErrMalformedCursor,
+    },
+    {
+      name:    "cursor without sort",
+      columns: stockColumns,
+      cursor:  &CursorOption{Count: 10, After: sortCursor},
+      err:     ErrMalformedCursor,
+    },
+    {
+      name:    "nullable sort field",
+      columns: tickerColumns,
+      cursor:  &CursorOption{Count: 10},
+      sort:    &SortOption{Field: "sector", Order: SortOrderAsc},
+      err:     ErrMalformedSort,
+    },
+    {
+      name:    "not sortable field",
+      columns: stockColumns,
+      cursor:  &CursorOption{Count: 10},
+      sort:    &SortOption{Field: "multiplier", Order: SortOrderAsc},
+      err:     ErrMalformedSort,
+    },
+    {
+      name:    "malformed sort order",
+      columns: stockColumns,
+      cursor:  &CursorOption{Count: 10},
+      sort:    &SortOption{Field: "close_price", Order: "up"},
+      err:     ErrMalformedSort,
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      _, _, err := testCase.cursor.Apply(newTestStocksBuilder(), testCase.columns, testCase.sort)
+      if !errors.Is(err, testCase.err) {
+        t.Errorf("expected error '%v', got '%v'", testCase.err, err)
+      }
+    })
+  }
+}
+
+func TestTrimPage(t *testing.T) {
+  page := &keysetPage{
+    field: "close_price",
+    order: SortOrderAsc,
+    count: 2,
+  }
+  items := make([]string, 0, 3)
+
+  for idx := 0; idx < 3; idx++ {
+    var value string
+    page.dest(&value)
+    page.keys[idx].Value = fmt.Sprint(idx)
+    page.keys[idx].Id = fmt.Sprint("id-", idx)
+    items = append(items, fmt.Sprint("item-", idx))
+  }
+  trimmed, cursor, err := trimPage(items, page)
+  if err != nil {
+    t.Fatalf("cannot trim page: %v", err)
+  }
+  if fmt.Sprint(trimmed) != "[item-0 item-1]" {
+    t.Errorf("expected extra row trimmed, got %v", trimmed)
+  }
+  key, err := decodeCursor(cursor)
+  if err != nil {
+    t.Fatalf("cannot decode next cursor: %v", err)
+  }
+  expected := cursorKey{Field: "close_price", Order: SortOrderAsc, Value: "1", Id: "id-1"}
+  if *key != expected {
+    t.Errorf("expected cursor of the last row %+v, got %+v", expected, *key)
+  }
+  if _, cursor, _ = trimPage(items[:2], page); cursor != "" {
+    t.Errorf("expected empty cursor of the last page, got '%s'", cursor)
+  }
+}
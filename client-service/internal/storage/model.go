@@ -3,30 +3,72 @@ package storage
 import "time"
 
 type Ticker struct {
-  TickerId           string    `json:"ticker_id"`
-  CompanyName        string    `json:"company_name"`
-  CompanyLocale      string    `json:"company_locale"`
-  CompanyDescription string    `json:"company_description"`
-  CompanyState       string    `json:"company_state"`
-  CompanyCity        string    `json:"company_city"`
-  CompanyAddress     string    `json:"company_address"`
-  HomepageUrl        string    `json:"homepage_url"`
-  CurrencyName       string    `json:"currency_name"`
-  TotalEmployees     int       `json:"total_employees"`
-  Active             bool      `json:"active"`
-  CreatedAt          time.Time `json:"created_at"`
+  TickerId           string     `json:"ticker_id"`
+  CompanyName        string     `json:"company_name"`
+  CompanyLocale      string     `json:"company_locale"`
+  CompanyDescription string     `json:"company_description"`
+  CompanyState       string     `json:"company_state"`
+  CompanyCity        string     `json:"company_city"`
+  CompanyAddress     string     `json:"company_address"`
+  HomepageUrl        string     `json:"homepage_url"`
+  CurrencyName       string     `json:"currency_name"`
+  TotalEmployees     int        `json:"total_employees"`
+  SicCode            string     `json:"sic_code"`
+  SicDescription     string     `json:"sic_description"`
+  Sector             string     `json:"sector"`
+  PrimaryExchange    string     `json:"primary_exchange"`
+  Market             string     `json:"market"`
+  ListDate           *time.Time `json:"list_date"`
+  Active             bool       `json:"active"`
+  CreatedAt          time.Time  `json:"created_at"`
 }
 
 type Stock struct {
-  StockId       string    `json:"stock_id"`
-  TickerId      string    `json:"ticker_id"`
-  OpenPrice     float64   `json:"open_price"`
-  ClosePrice    float64   `json:"close_price"`
-  HighestPrice  float64   `json:"highest_price"`
-  LowestPrice   float64   `json:"lowest_price"`
-  TradingVolume int       `json:"trading_volume"`
-  StockedAt     time.Time `json:"stocked_time"`
-  CreatedAt     time.Time `json:"created_at"`
+  StockId          string    `json:"stock_id"`
+  TickerId         string    `json:"ticker_id"`
+  OpenPrice        float64   `json:"open_price"`
+  ClosePrice       float64   `json:"close_price"`
+  HighestPrice     float64   `json:"highest_price"`
+  LowestPrice      float64   `json:"lowest_price"`
+  TradingVolume    int       `json:"trading_volume"`
+  AdjOpenPrice     float64   `json:"adj_open_price"`
+  AdjClosePrice    float64   `json:"adj_close_price"`
+  AdjHighestPrice  float64   `json:"adj_highest_price"`
+  AdjLowestPrice   float64   `json:"adj_lowest_price"`
+  AdjTradingVolume int       `json:"adj_trading_volume"`
+  Timespan         string    `json:"timespan"`
+  Multiplier       int       `json:"multiplier"`
+  StockedAt        time.Time `json:"stocked_time"`
+  CreatedAt        time.Time `json:"created_at"`
+}
+
+type AggregatedStock struct {
+  PeriodStart      time.Time `json:"period_start"`
+  OpenPrice        float64   `json:"open_price"`
+  ClosePrice       float64   `json:"close_price"`
+  HighestPrice     float64   `json:"highest_price"`
+  LowestPrice      float64   `json:"lowest_price"`
+  TradingVolume    float64   `json:"trading_volume"`
+  BarsCount        int       `json:"bars_count"`
+  FirstStockedTime time.Time `json:"first_stocked_time"`
+  LastStockedTime  time.Time `json:"last_stocked_time"`
+}
+
+type Financial struct {
+  FinancialId       string     `json:"financial_id"`
+  TickerId          string     `json:"ticker_id"`
+  Timeframe         string     `json:"timeframe"`
+  FiscalPeriod      string     `json:"fiscal_period"`
+  FiscalYear        int        `json:"fiscal_year"`
+  StartDate         time.Time  `json:"start_date"`
+  EndDate           time.Time  `json:"end_date"`
+  FilingDate        *time.Time `json:"filing_date"`
+  Revenue           *float64   `json:"revenue"`
+  NetIncome         *float64   `json:"net_income"`
+  BasicEps          *float64   `json:"basic_eps"`
+  DilutedEps        *float64   `json:"diluted_eps"`
+  SharesOutstanding *float64   `json:"shares_outstanding"`
+  UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 type Subscription struct {
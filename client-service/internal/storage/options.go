@@ -3,10 +3,9 @@ package storage
 import (
   "errors"
   "fmt"
-  "regexp"
   "strings"
 
-  "github.com/UshakovN/stock-predictor-service/postgres"
+  sq "github.com/Masterminds/squirrel"
 )
 
 var (
@@ -16,12 +15,6 @@ var (
   ErrMustContainOneFilterType = errors.New("filter part must contain one filter type")
 )
 
-var (
-  sanFieldRegex       = regexp.MustCompile(`[^a-zA-Z-_]`)
-  sanQueryFirstRegex  = regexp.MustCompile(`\s`)
-  sanQuerySecondRegex = regexp.MustCompile(` {2,}`)
-)
-
 const (
   SortOrderAsc  = "asc"
   SortOrderDesc = "desc"
@@ -29,6 +22,7 @@ const (
 
 type GetOption struct {
   Pagination *PaginationOption
+  Cursor     *CursorOption
   Sort       *SortOption
   Filters    FiltersOption
 }
@@ -37,6 +31,10 @@ func (o *GetOption) HasPagination() bool {
   return o != nil && o.Pagination != nil
 }
 
+func (o *GetOption) HasCursor() bool {
+  return o != nil && o.Cursor != nil
+}
+
 func (o *GetOption) HasSort() bool {
   return o != nil && o.Sort != nil
 }
@@ -45,26 +43,21 @@ func (o *GetOption) HasFilters() bool {
   return o != nil && len(o.Filters) > 0
 }
 
-type QueryStuff interface {
-  Stuff(query string) (string, error)
-}
-
 type PaginationOption struct {
   Page  int
   Count int
 }
 
-func (p *PaginationOption) Stuff(query string) (string, error) {
-  if p.Page < 0 || p.Count < 0 {
-    return "", ErrMalformedPagination
-  }
+func (p *PaginationOption) Apply(builder sq.SelectBuilder, _ *resourceColumns) (sq.SelectBuilder, error) {
   const pageShift = 1
 
-  offset := (p.Page - pageShift) * p.Count
-  limit := p.Count
-  query = fmt.Sprintf(`%s offset %d limit %d`, sanitizeQuery(query), offset, limit)
+  if p.Page < pageShift || p.Count < 0 {
+    return builder, ErrMalformedPagination
+  }
+  offset := uint64((p.Page - pageShift) * p.Count)
+  limit := uint64(p.Count)
 
-  return query, nil
+  return builder.Offset(offset).Limit(limit), nil
 }
 
 type SortOption struct {
@@ -72,81 +65,151 @@ type SortOption struct {
   Order string
 }
 
-func (s *SortOption) Stuff(query string) (string, error) {
-  if s.Field == "" || (s.Order != SortOrderAsc && s.Order != SortOrderDesc) {
-    return "", ErrMalformedSort
+func (s *SortOption) Apply(builder sq.SelectBuilder, columns *resourceColumns) (sq.SelectBuilder, error) {
+  if s.Order != SortOrderAsc && s.Order != SortOrderDesc {
+    return builder, ErrMalformedSort
   }
-  query = fmt.Sprintf(`%s order by %s %s`,
-    sanitizeQuery(query),
-    sanitizeField(s.Field),
-    sanitizeField(s.Order))
-
-  return query, nil
+  column, ok := columns.sortable(s.Field)
+  if !ok {
+    return builder, fmt.Errorf("%w: field '%s' is not sortable", ErrMalformedSort, s.Field)
+  }
+  return builder.OrderBy(fmt.Sprint(column, " ", s.Order)), nil
 }
 
-type FiltersOption []*FilterPart
+// maxFilterDepth limit nesting of the filter groups
+const maxFilterDepth = 8
 
-func (f FiltersOption) Stuff(query string) (string, error) {
-  var err error
+type FiltersOption []*FilterPart
 
-  for _, filter := range f {
-    if query, err = filter.stuff(query); err != nil {
-      return "", err
+func (f FiltersOption) Apply(builder sq.SelectBuilder, columns *resourceColumns) (sq.SelectBuilder, error) {
+  for idx, filter := range f {
+    where, err := filter.where(columns, fmt.Sprintf("filters[%d]", idx), 0)
+    if err != nil {
+      return builder, err
     }
+    // where clauses of the builder are joined by 'and'
+    builder = builder.Where(where)
   }
-  const (
-    first       = 1
-    other       = -1
-    union       = "and"
-    placeholder = "{where}"
-    statement   = "where"
-  )
-  query = strings.Replace(query, placeholder, statement, first)
-  query = strings.Replace(query, placeholder, union, other)
-
-  return query, nil
+  return builder, nil
 }
 
+// FilterPart is one condition or group of the conditions.
+// And, Or and Not groups contain nested filter parts
 type FilterPart struct {
   Border  *BorderFilter
   Between *BetweenFilter
   List    *ListFilter
+  NotIn   *ListFilter
+  IsNull  *NullFilter
+  And     []*FilterPart
+  Or      []*FilterPart
+  Not     *FilterPart
 }
 
 func (f *FilterPart) count() int {
   var count int
 
-  if f.Border != nil {
-    count++
-  }
-  if f.Between != nil {
-    count++
-  }
-  if f.List != nil {
-    count++
+  for _, specified := range []bool{
+    f.Border != nil,
+    f.Between != nil,
+    f.List != nil,
+    f.NotIn != nil,
+    f.IsNull != nil,
+    f.And != nil,
+    f.Or != nil,
+    f.Not != nil,
+  } {
+    if specified {
+      count++
+    }
   }
   return count
 }
 
-func (f *FilterPart) stuff(query string) (string, error) {
+// where return condition of the filter part. path point to the part in the option for errors,
+// e.g. filters[0].or[1].border
+func (f *FilterPart) where(columns *resourceColumns, path string, depth int) (sq.Sqlizer, error) {
   const requiredCount = 1
 
-  if f.count() > requiredCount {
-    return "", ErrMustContainOneFilterType
+  if depth > maxFilterDepth {
+    return nil, filterError(path, fmt.Errorf("%w: nesting deeper than %d", ErrMalformedFilter, maxFilterDepth))
   }
-  var err error
+  if f == nil || f.count() != requiredCount {
+    return nil, filterError(path, ErrMustContainOneFilterType)
+  }
+  var (
+    where sq.Sqlizer
+    err   error
+  )
+  switch {
+  case f.And != nil:
+    return groupWhere(f.And, columns, path+".and", depth, func(parts []sq.Sqlizer) sq.Sqlizer {
+      return sq.And(parts)
+    })
+  case f.Or != nil:
+    return groupWhere(f.Or, columns, path+".or", depth, func(parts []sq.Sqlizer) sq.Sqlizer {
+      return sq.Or(parts)
+    })
+  case f.Not != nil:
+    if where, err = f.Not.where(columns, path+".not", depth+1); err != nil {
+      return nil, err
+    }
+    return notExpr{where}, nil
+  case f.Border != nil:
+    path += ".border"
+    where, err = f.Border.where(columns)
+  case f.Between != nil:
+    path += ".between"
+    where, err = f.Between.where(columns)
+  case f.List != nil:
+    path += ".list"
+    where, err = f.List.where(columns, false)
+  case f.NotIn != nil:
+    path += ".not_in"
+    where, err = f.NotIn.where(columns, true)
+  default:
+    path += ".is_null"
+    where, err = f.IsNull.where(columns)
+  }
+  if err != nil {
+    return nil, filterError(path, err)
+  }
+  return where, nil
+}
 
-  if f.Border != nil {
-    query, err = f.Border.stuff(query)
-  } else
-  if f.Between != nil {
-    query, err = f.Between.stuff(query)
-  } else
-  if f.List != nil {
-    query, err = f.List.stuff(query)
+func groupWhere(
+  group []*FilterPart,
+  columns *resourceColumns,
+  path string,
+  depth int,
+  join func(parts []sq.Sqlizer) sq.Sqlizer,
+) (sq.Sqlizer, error) {
+  if len(group) == 0 {
+    return nil, filterError(path, fmt.Errorf("%w: group is empty", ErrMalformedFilter))
   }
+  parts := make([]sq.Sqlizer, 0, len(group))
 
-  return query, err
+  for idx, part := range group {
+    where, err := part.where(columns, fmt.Sprintf("%s[%d]", path, idx), depth+1)
+    if err != nil {
+      return nil, err
+    }
+    parts = append(parts, where)
+  }
+  return join(parts), nil
+}
+
+// notExpr negate nested condition
+type notExpr struct {
+  where sq.Sqlizer
+}
+
+func (e notExpr) ToSql() (string, []any, error) {
+  query, args, err := e.where.ToSql()
+  if err != nil {
+    return "", nil, err
+  }
+  return fmt.Sprint("not (", query, ")"), args, nil
 }
 
 type BorderFilter struct {
@@ -166,93 +229,125 @@ type ListFilter struct {
   Values []any
 }
 
-func (f *BorderFilter) stuff(query string) (string, error) {
-  if f.Field == "" || f.Value == nil || f.Compare == nil {
-    return "", ErrMalformedFilter
-  }
-  query = fmt.Sprintf("%s {where} %s %s %v",
-    sanitizeQuery(query),
-    sanitizeField(f.Field),
-    f.Compare.Token(),
-    quoteValue(f.Value))
-
-  return query, nil
+type NullFilter struct {
+  Field string
 }
 
-func (f *BetweenFilter) stuff(query string) (string, error) {
-  if f.Field == "" || f.LeftBorder == nil || f.RightBorder == nil {
-    return "", ErrMalformedFilter
+func (f *BorderFilter) where(columns *resourceColumns) (sq.Sqlizer, error) {
+  if f.Compare == nil || !scalarValue(f.Value) {
+    return nil, ErrMalformedFilter
   }
-  query = fmt.Sprintf("%s {where} %s between %v and %v",
-    sanitizeQuery(query),
-    sanitizeField(f.Field),
-    quoteValue(f.LeftBorder),
-    quoteValue(f.RightBorder))
-
-  return query, nil
+  column, err := filterableColumn(columns, f.Field)
+  if err != nil {
+    return nil, err
+  }
+  switch f.Compare.(type) {
+  case LikeTokenizer, IlikeTokenizer:
+    prefix, ok := f.Value.(string)
+    if !ok || prefix == "" {
+      return nil, fmt.Errorf("%w: value of '%s' compare must be non empty string", ErrMalformedFilter, f.Compare.Token())
+    }
+    // column is compared as text, so prefix search is available for any column
+    return sq.Expr(fmt.Sprint(column, "::text ", f.Compare.Token(), " ?"), prefixPattern(prefix)), nil
+  }
+  return sq.Expr(fmt.Sprint(column, " ", f.Compare.Token(), " ?"), f.Value), nil
 }
 
-func (f *ListFilter) stuff(query string) (string, error) {
-  if f.Field == "" || len(f.Values) == 0 {
-    return "", ErrMalformedFilter
+func (f *BetweenFilter) where(columns *resourceColumns) (sq.Sqlizer, error) {
+  if !scalarValue(f.LeftBorder) || !scalarValue(f.RightBorder) {
+    return nil, ErrMalformedFilter
+  }
+  column, err := filterableColumn(columns, f.Field)
+  if err != nil {
+    return nil, err
   }
-  values := make([]string, 0, len(f.Values))
+  return sq.Expr(fmt.Sprint(column, " between ? and ?"), f.LeftBorder, f.RightBorder), nil
+}
 
+func (f *ListFilter) where(columns *resourceColumns, exclude bool) (sq.Sqlizer, error) {
+  if len(f.Values) == 0 {
+    return nil, ErrMalformedFilter
+  }
   for _, value := range f.Values {
-    values = append(values, quoteValue(value))
+    if !scalarValue(value) {
+      return nil, ErrMalformedFilter
+    }
   }
-  const (
-    commaSep = ","
-  )
-  listValues := strings.Join(values, commaSep)
-
-  query = fmt.Sprintf("%s {where} %s in (%s)",
-    sanitizeQuery(query),
-    sanitizeField(f.Field),
-    listValues)
+  column, err := filterableColumn(columns, f.Field)
+  if err != nil {
+    return nil, err
+  }
+  // slice value is expanded to 'in' and 'not in' lists
+  if exclude {
+    return sq.NotEq{column: f.Values}, nil
+  }
+  return sq.Eq{column: f.Values}, nil
+}
 
-  return query, nil
+func (f *NullFilter) where(columns *resourceColumns) (sq.Sqlizer, error) {
+  column, err := filterableColumn(columns, f.Field)
+  if err != nil {
+    return nil, err
+  }
+  return sq.Eq{column: nil}, nil
 }
 
-func (o *GetOption) Stuff(query string) (string, error) {
+// Apply add option clauses to the query builder. keyset page is returned in cursor mode only
+func (o *GetOption) Apply(builder sq.SelectBuilder, columns *resourceColumns) (sq.SelectBuilder, *keysetPage, error) {
   if o == nil {
-    return query, nil
+    return builder, nil, nil
+  }
+  if o.HasPagination() && o.HasCursor() {
+    return builder, nil, fmt.Errorf("%w: pagination and cursor cannot be used together", ErrMalformedPagination)
   }
   var err error
 
   if o.HasFilters() {
-    if query, err = o.Filters.Stuff(query); err != nil {
-      return "", err
+    if builder, err = o.Filters.Apply(builder, columns); err != nil {
+      return builder, nil, err
     }
   }
+  // cursor order rows by sort key and id itself
+  if o.HasCursor() {
+    return o.Cursor.Apply(builder, columns, o.Sort)
+  }
   if o.HasSort() {
-    if query, err = o.Sort.Stuff(query); err != nil {
-      return "", err
+    if builder, err = o.Sort.Apply(builder, columns); err != nil {
+      return builder, nil, err
     }
   }
   if o.HasPagination() {
-    if query, err = o.Pagination.Stuff(query); err != nil {
-      return "", err
+    if builder, err = o.Pagination.Apply(builder, columns); err != nil {
+      return builder, nil, err
     }
   }
+  return builder, nil, nil
+}
 
-  return query, nil
+func filterableColumn(columns *resourceColumns, field string) (string, error) {
+  column, ok := columns.filterable(field)
+  if !ok {
+    return "", fmt.Errorf("%w: field '%s' is not filterable", ErrMalformedFilter, field)
+  }
+  return column, nil
 }
 
-func sanitizeQuery(query string) string {
-  const space = " "
-  query = sanQueryFirstRegex.ReplaceAllLiteralString(query, space)
-  query = sanQuerySecondRegex.ReplaceAllLiteralString(query, space)
-  return query
+// filterError point to the filter part with the malformed condition
+func filterError(path string, err error) error {
+  return fmt.Errorf("%s: %w", path, err)
 }
 
-func sanitizeField(field string) string {
-  const blank = ""
-  field = sanFieldRegex.ReplaceAllLiteralString(field, blank)
-  field = strings.ToLower(field)
-  return field
+// prefixPattern escape like wildcards of the prefix and match any suffix
+func prefixPattern(prefix string) string {
+  return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
 }
 
-func quoteValue(value any) string {
-  return postgres.QuoteArg(value)
+// scalarValue report that filter value is json scalar, values are bound as query parameters
+func scalarValue(value any) bool {
+  switch value.(type) {
+  case string, float64, bool, int, int64:
+    return true
+  default:
+    return false
+  }
 }
@@ -0,0 +1,284 @@
+package storage
+
+import (
+  "errors"
+  "fmt"
+  "strings"
+  "testing"
+
+  sq "github.com/Masterminds/squirrel"
+  "github.com/UshakovN/stock-predictor-service/postgres"
+)
+
+func newTestStocksBuilder() sq.SelectBuilder {
+  return postgres.NewSelectBuilder().
+    Columns(`stock_id`).
+    From(`stock`)
+}
+
+// buildTestQuery return query of the option applied to the stocks builder
+func buildTestQuery(t *testing.T, option *GetOption) (string, []any, error) {
+  t.Helper()
+
+  builder, _, err := option.Apply(newTestStocksBuilder(), stockColumns)
+  if err != nil {
+    return "", nil, err
+  }
+  query, args, err := builder.ToSql()
+  if err != nil {
+    t.Fatalf("cannot build query: %v", err)
+  }
+  return query, args, nil
+}
+
+func TestGetOptionApply(t *testing.T) {
+  const selectStocks = "SELECT stock_id FROM stock"
+
+  testCases := []struct {
+    name   string
+    option *GetOption
+    query  string
+    args   []any
+  }{
+    {
+      name:  "without option",
+      query: selectStocks,
+    },
+    {
+      name: "border filter",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Border: &BorderFilter{Field: "close_price", Value: 10.5, Compare: GtTokenizer{}}},
+        },
+      },
+      query: selectStocks + " WHERE stock.close_price > $1",
+      args:  []any{10.5},
+    },
+    {
+      name: "filters are joined by and",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Border: &BorderFilter{Field: "ticker_id", Value: "AAPL", Compare: EqTokenizer{}}},
+          {Between: &BetweenFilter{Field: "stocked_at", LeftBorder: "2023-03-01", RightBorder: "2023-03-31"}},
+        },
+      },
+      query: selectStocks + " WHERE stock.ticker_id = $1 AND stock.stocked_at between $2 and $3",
+      args:  []any{"AAPL", "2023-03-01", "2023-03-31"},
+    },
+    {
+      name: "like prefix",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Border: &BorderFilter{Field: "ticker_id", Value: "A_%", Compare: IlikeTokenizer{}}},
+        },
+      },
+      query: selectStocks + " WHERE stock.ticker_id::text ilike $1",
+      args:  []any{`A\_\%%`},
+    },
+    {
+      name: "list and not in",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {List: &ListFilter{Field: "ticker_id", Values: []any{"AAPL", "MSFT"}}},
+          {NotIn: &ListFilter{Field: "timespan", Values: []any{"minute"}}},
+        },
+      },
+      query: selectStocks + " WHERE stock.ticker_id IN ($1,$2) AND stock.timespan NOT IN ($3)",
+      args:  []any{"AAPL", "MSFT", "minute"},
+    },
+    {
+      name: "groups",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Or: []*FilterPart{
+            {Border: &BorderFilter{Field: "ticker_id", Value: "AAPL", Compare: EqTokenizer{}}},
+            {Not: &FilterPart{IsNull: &NullFilter{Field: "created_at"}}},
+          }},
+        },
+      },
+      query: selectStocks + " WHERE (stock.ticker_id = $1 OR not (stock.created_at IS NULL))",
+      args:  []any{"AAPL"},
+    },
+    {
+      name: "sort and pagination",
+      option: &GetOption{
+        Sort:       &SortOption{Field: "stocked_at", Order: SortOrderDesc},
+        Pagination: &PaginationOption{Page: 3, Count: 20},
+      },
+      query: selectStocks + " ORDER BY stock.stocked_at desc LIMIT 20 OFFSET 40",
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      query, args, err := buildTestQuery(t, testCase.option)
+      if err != nil {
+        t.Fatalf("cannot apply option: %v", err)
+      }
+      if query != testCase.query {
+        t.Errorf("expected query:\n%s\ngot:\n%s", testCase.query, query)
+      }
+      if fmt.Sprint(args) != fmt.Sprint(testCase.args) {
+        t.Errorf("expected args %v, got %v", testCase.args, args)
+      }
+    })
+  }
+}
+
+func TestGetOptionApplyErrors(t *testing.T) {
+  deepFilter := &FilterPart{Border: &BorderFilter{Field: "ticker_id", Value: "AAPL", Compare: EqTokenizer{}}}
+
+  for depth := 0; depth <= maxFilterDepth; depth++ {
+    deepFilter = &FilterPart{Not: deepFilter}
+  }
+  testCases := []struct {
+    name   string
+    option *GetOption
+    err    error
+    // error must point to the malformed part
+    errContains string
+  }{
+    {
+      name: "unknown filter field",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Border: &BorderFilter{Field: "close_price; drop table stock", Value: 1, Compare: EqTokenizer{}}},
+        },
+      },
+      err:         ErrMalformedFilter,
+      errContains: "filters[0].border",
+    },
+    {
+      name: "not filterable field",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {IsNull: &NullFilter{Field: "company_name"}},
+        },
+      },
+      err:         ErrMalformedFilter,
+      errContains: "filters[0].is_null",
+    },
+    {
+      name: "nested unknown field",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Border: &BorderFilter{Field: "ticker_id", Value: "AAPL", Compare: EqTokenizer{}}},
+          {And: []*FilterPart{
+            {Border: &BorderFilter{Field: "ticker_id", Value: "AAPL", Compare: EqTokenizer{}}},
+            {Or: []*FilterPart{{List: &ListFilter{Field: "unknown", Values: []any{1}}}}},
+          }},
+        },
+      },
+      err:         ErrMalformedFilter,
+      errContains: "filters[1].and[1].or[0].list",
+    },
+    {
+      name: "not scalar value",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Border: &BorderFilter{Field: "ticker_id", Value: []any{"AAPL"}, Compare: EqTokenizer{}}},
+        },
+      },
+      err: ErrMalformedFilter,
+    },
+    {
+      name: "empty like prefix",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {Border: &BorderFilter{Field: "ticker_id", Value: "", Compare: LikeTokenizer{}}},
+        },
+      },
+      err: ErrMalformedFilter,
+    },
+    {
+      name: "empty group",
+      option: &GetOption{
+        Filters: FiltersOption{{Or: []*FilterPart{}}},
+      },
+      err:         ErrMalformedFilter,
+      errContains: "filters[0].or",
+    },
+    {
+      name: "several filter types",
+      option: &GetOption{
+        Filters: FiltersOption{
+          {
+            IsNull: &NullFilter{Field: "ticker_id"},
+            List:   &ListFilter{Field: "ticker_id", Values: []any{"AAPL"}},
+          },
+        },
+      },
+      err: ErrMustContainOneFilterType,
+    },
+    {
+      name: "too deep nesting",
+      option: &GetOption{
+        Filters: FiltersOption{deepFilter},
+      },
+      err: ErrMalformedFilter,
+    },
+    {
+      name: "not sortable field",
+      option: &GetOption{
+        Sort: &SortOption{Field: "timespan", Order: SortOrderAsc},
+      },
+      err:         ErrMalformedSort,
+      errContains: "'timespan'",
+    },
+    {
+      name: "unknown sort field",
+      option: &GetOption{
+        Sort: &SortOption{Field: "stocked_at desc, (select 1)", Order: SortOrderAsc},
+      },
+      err: ErrMalformedSort,
+    },
+    {
+      name: "malformed sort order",
+      option: &GetOption{
+        Sort: &SortOption{Field: "stocked_at", Order: "random"},
+      },
+      err: ErrMalformedSort,
+    },
+    {
+      name: "malformed page",
+      option: &GetOption{
+        Pagination: &PaginationOption{Page: 0, Count: 10},
+      },
+      err: ErrMalformedPagination,
+    },
+    {
+      name: "pagination with cursor",
+      option: &GetOption{
+        Pagination: &PaginationOption{Page: 1, Count: 10},
+        Cursor:     &CursorOption{Count: 10},
+      },
+      err: ErrMalformedPagination,
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      query, _, err := buildTestQuery(t, testCase.option)
+      if err == nil {
+        t.Fatalf("expected error, got query '%s'", query)
+      }
+      if !errors.Is(err, testCase.err) {
+        t.Errorf("expected error '%v', got '%v'", testCase.err, err)
+      }
+      if !strings.Contains(err.Error(), testCase.errContains) {
+        t.Errorf("expected error to contain '%s', got '%v'", testCase.errContains, err)
+      }
+    })
+  }
+}
+
+func TestResourcesColumns(t *testing.T) {
+  for resource, columns := range resourcesColumns {
+    if !strings.HasPrefix(columns.id, string(resource)+".") {
+      t.Errorf("id column '%s' of resource '%s' is not qualified by the resource", columns.id, resource)
+    }
+    for field, column := range columns.fields {
+      if !strings.HasSuffix(column.name, "."+field) {
+        t.Errorf("column '%s' of resource '%s' do not match field '%s'", column.name, resource, field)
+      }
+    }
+  }
+}
@@ -17,13 +17,16 @@ import (
 var ErrNotFoundInStorage = errors.New("not found in storage")
 
 type Storage interface {
-  CalculatePages(resource Resource, pageSize int) (int, error)
-  GetTickers(option *GetOption) ([]*Ticker, error)
-  GetStocks(option *GetOption) ([]*Stock, error)
+  CalculatePages(resource Resource, pageSize int, filters FiltersOption) (int, error)
+  GetTickers(option *GetOption) ([]*Ticker, string, error)
+  GetStocks(option *GetOption) ([]*Stock, string, error)
+  GetFinancials(option *GetOption) ([]*Financial, string, error)
+  GetAggregatedStocks(option *AggregateOption) ([]*AggregatedStock, error)
   UpdateSubscription(sub *Subscription) error
   GetSubscriptions(userId string, filterActive bool) ([]*Subscription, error)
   GetStocksPredicts(userId string, datePredict time.Time) (*StocksPredicts, error)
   GetOptionForTicker(tickerId string) *GetOption
+  Close()
 }
 
 type queryBuilder interface {
@@ -46,43 +49,48 @@ func NewStorage(ctx context.Context, config *postgres.Config) (Storage, error) {
   }, nil
 }
 
-func (s *storage) GetTickers(option *GetOption) ([]*Ticker, error) {
-  var (
-    query string
-    err   error
-  )
-  query = sanitizeQuery(
-    `select
-        ticker_id,
-        company_name,
-        company_locale,
-        company_description,
-        company_state,
-        company_city,
-        company_address,
-        homepage_url,
-        currency_name,
-        total_employees,
-        active,
-        created_at
-    from ticker inner join ticker_details
-        on ticker_id = ticker_details.ticker_id`)
+func (s *storage) Close() {
+  s.client.Close()
+}
+
+func (s *storage) GetTickers(option *GetOption) ([]*Ticker, string, error) {
+  builder := postgres.NewSelectBuilder().
+    Columns(
+      `ticker.ticker_id`,
+      `ticker.company_name`,
+      `ticker.company_locale`,
+      `ticker_details.company_description`,
+      `ticker_details.company_state`,
+      `ticker_details.company_city`,
+      `ticker_details.company_address`,
+      `ticker_details.homepage_url`,
+      `ticker.currency_name`,
+      `ticker_details.total_employees`,
+      `coalesce(ticker_details.sic_code, '')`,
+      `coalesce(ticker_details.sic_description, '')`,
+      `coalesce(ticker_details.sector, '')`,
+      `coalesce(ticker_details.primary_exchange, '')`,
+      `coalesce(ticker_details.market, '')`,
+      `ticker_details.list_date`,
+      `ticker.active`,
+      `ticker.created_at`,
+    ).
+    From(`ticker`).
+    Join(`ticker_details on ticker_details.ticker_id = ticker.ticker_id`)
 
-  query, err = option.Stuff(query)
+  builder, page, err := option.Apply(builder, tickerColumns)
   if err != nil {
-    return nil, err
+    return nil, "", err
   }
-  query = unambiguousGetTickersQuery(query)
-
-  rows, err := s.doRawQuery(query)
+  rows, err := s.doQuery(nil, builder)
   if err != nil {
-    return nil, err
+    return nil, "", err
   }
   var tickers []*Ticker
 
   for {
     ticker := &Ticker{}
-    found, err := scanQueriedRow(rows,
+    found, err := scanQueriedRow(rows, page.dest(
       &ticker.TickerId,
       &ticker.CompanyName,
       &ticker.CompanyLocale,
@@ -93,11 +101,17 @@ func (s *storage) GetTickers(option *GetOption) ([]*Ticker, error) {
       &ticker.HomepageUrl,
       &ticker.CurrencyName,
       &ticker.TotalEmployees,
+      &ticker.SicCode,
+      &ticker.SicDescription,
+      &ticker.Sector,
+      &ticker.PrimaryExchange,
+      &ticker.Market,
+      &ticker.ListDate,
       &ticker.Active,
       &ticker.CreatedAt,
-    )
+    )...)
     if err != nil {
-      return nil, fmt.Errorf("cannot scan queried row: %v", err)
+      return nil, "", fmt.Errorf("cannot scan queried row: %v", err)
     }
     if !found {
       break
@@ -105,40 +119,44 @@ func (s *storage) GetTickers(option *GetOption) ([]*Ticker, error) {
     tickers = append(tickers, ticker)
   }
 
-  return tickers, nil
+  return trimPage(tickers, page)
 }
 
-func (s *storage) GetStocks(options *GetOption) ([]*Stock, error) {
-  var (
-    query string
-    err   error
-  )
-  query = sanitizeQuery(
-    `select 
-        stock_id,
-        ticker_id, 
-        open_price,
-        close_price,
-        highest_price,
-        lowest_price,
-        trading_volume,
-        stocked_at,
-        created_at
-      from stock`)
+func (s *storage) GetStocks(option *GetOption) ([]*Stock, string, error) {
+  builder := postgres.NewSelectBuilder().
+    Columns(
+      `stock_id`,
+      `ticker_id`,
+      `open_price`,
+      `close_price`,
+      `highest_price`,
+      `lowest_price`,
+      `trading_volume`,
+      `coalesce(adj_open_price, open_price)`,
+      `coalesce(adj_close_price, close_price)`,
+      `coalesce(adj_highest_price, highest_price)`,
+      `coalesce(adj_lowest_price, lowest_price)`,
+      `coalesce(adj_trading_volume, trading_volume)`,
+      `timespan`,
+      `multiplier`,
+      `stocked_at`,
+      `created_at`,
+    ).
+    From(`stock`)
 
-  query, err = options.Stuff(query)
+  builder, page, err := option.Apply(builder, stockColumns)
   if err != nil {
-    return nil, err
+    return nil, "", err
   }
-  rows, err := s.doRawQuery(query)
+  rows, err := s.doQuery(nil, builder)
   if err != nil {
-    return nil, err
+    return nil, "", err
   }
   var stocks []*Stock
 
   for {
     stock := &Stock{}
-    found, err := scanQueriedRow(rows,
+    found, err := scanQueriedRow(rows, page.dest(
       &stock.StockId,
       &stock.TickerId,
       &stock.OpenPrice,
@@ -146,11 +164,18 @@ func (s *storage) GetStocks(options *GetOption) ([]*Stock, error) {
       &stock.HighestPrice,
       &stock.LowestPrice,
       &stock.TradingVolume,
+      &stock.AdjOpenPrice,
+      &stock.AdjClosePrice,
+      &stock.AdjHighestPrice,
+      &stock.AdjLowestPrice,
+      &stock.AdjTradingVolume,
+      &stock.Timespan,
+      &stock.Multiplier,
       &stock.StockedAt,
       &stock.CreatedAt,
-    )
+    )...)
     if err != nil {
-      return nil, fmt.Errorf("cannot scan queried row: %v", err)
+      return nil, "", fmt.Errorf("cannot scan queried row: %v", err)
     }
     if !found {
       break
@@ -158,7 +183,67 @@ func (s *storage) GetStocks(options *GetOption) ([]*Stock, error) {
     stocks = append(stocks, stock)
   }
 
-  return stocks, nil
+  return trimPage(stocks, page)
+}
+
+func (s *storage) GetFinancials(option *GetOption) ([]*Financial, string, error) {
+  builder := postgres.NewSelectBuilder().
+    Columns(
+      `financial_id`,
+      `ticker_id`,
+      `timeframe`,
+      `fiscal_period`,
+      `fiscal_year`,
+      `start_date`,
+      `end_date`,
+      `filing_date`,
+      `revenue`,
+      `net_income`,
+      `basic_eps`,
+      `diluted_eps`,
+      `shares_outstanding`,
+      `updated_at`,
+    ).
+    From(`ticker_financial`)
+
+  builder, page, err := option.Apply(builder, financialColumns)
+  if err != nil {
+    return nil, "", err
+  }
+  rows, err := s.doQuery(nil, builder)
+  if err != nil {
+    return nil, "", err
+  }
+  var financials []*Financial
+
+  for {
+    financial := &Financial{}
+    found, err := scanQueriedRow(rows, page.dest(
+      &financial.FinancialId,
+      &financial.TickerId,
+      &financial.Timeframe,
+      &financial.FiscalPeriod,
+      &financial.FiscalYear,
+      &financial.StartDate,
+      &financial.EndDate,
+      &financial.FilingDate,
+      &financial.Revenue,
+      &financial.NetIncome,
+      &financial.BasicEps,
+      &financial.DilutedEps,
+      &financial.SharesOutstanding,
+      &financial.UpdatedAt,
+    )...)
+    if err != nil {
+      return nil, "", fmt.Errorf("cannot scan queried row: %v", err)
+    }
+    if !found {
+      break
+    }
+    financials = append(financials, financial)
+  }
+
+  return trimPage(financials, page)
 }
 
 func (s *storage) UpdateSubscription(sub *Subscription) error {
@@ -395,16 +480,26 @@ func (s *storage) getModelInfo(modelId string) (*ModelInfo, error) {
   return modelInfo, nil
 }
 
-func (s *storage) CalculatePages(resource Resource, pageSize int) (int, error) {
-  query := sanitizeQuery(fmt.Sprintf(`select ceil(count(*) :: double precision / %d) from %s`,
-    pageSize, resource))
+// CalculatePages return count of the resource pages with rows matched by the filters
+func (s *storage) CalculatePages(resource Resource, pageSize int, filters FiltersOption) (int, error) {
+  columns, ok := resourcesColumns[resource]
+  if !ok {
+    return 0, fmt.Errorf("unknown resource '%s'", resource)
+  }
+  builder := postgres.NewSelectBuilder().
+    Column(sq.Expr(`ceil(count(*) :: double precision / ?)`, pageSize)).
+    From(string(resource))
 
-  queriedRows, err := s.doRawQuery(query)
+  builder, err := filters.Apply(builder, columns)
   if err != nil {
-    return 0, fmt.Errorf("cannot do query: %v", err)
+    return 0, err
+  }
+  queriedRows, err := s.doQuery(nil, builder)
+  if err != nil {
+    return 0, err
   }
   var pagesCount int
-  
+
   if _, err := scanFirstQueriedRow(queriedRows, &pagesCount); err != nil {
     return 0, err
   }
@@ -486,12 +581,15 @@ func scanFirstQueriedRow(rows pgx.Rows, fields ...any) (bool, error) {
   return hasRows, err
 }
 
-var ambiguousColumn = regexp.MustCompile(`[^.]ticker_id`)
+var (
+  sanQueryFirstRegex  = regexp.MustCompile(`\s`)
+  sanQuerySecondRegex = regexp.MustCompile(` {2,}`)
+)
 
-func unambiguousGetTickersQuery(query string) string {
-  const unambiguous = ` ticker.ticker_id `
-  query = ambiguousColumn.ReplaceAllLiteralString(query, unambiguous)
-  query = sanitizeQuery(query)
+func sanitizeQuery(query string) string {
+  const space = " "
+  query = sanQueryFirstRegex.ReplaceAllLiteralString(query, space)
+  query = sanQuerySecondRegex.ReplaceAllLiteralString(query, space)
   return query
 }
 
@@ -3,6 +3,120 @@ package storage
 type Resource string
 
 const (
-  ResourceTicker Resource = "ticker"
-  ResourceStock  Resource = "stock"
+  ResourceTicker    Resource = "ticker"
+  ResourceStock     Resource = "stock"
+  ResourceFinancial Resource = "ticker_financial"
 )
+
+// resourcesColumns is columns whitelist of the resource tables
+var resourcesColumns = map[Resource]*resourceColumns{
+  ResourceTicker:    tickerColumns,
+  ResourceStock:     stockColumns,
+  ResourceFinancial: financialColumns,
+}
+
+type columnFlag int
+
+const (
+  filterable columnFlag = 1 << iota
+  sortable
+  // nullable column cannot be sort key of the cursor pagination
+  nullable
+)
+
+// resourceColumn is qualified column of the resource query available for clients
+type resourceColumn struct {
+  name  string
+  flags columnFlag
+}
+
+// resourceColumns whitelist client fields of the resource, other fields are rejected.
+// id is unique column, it is the last key of the cursor pagination
+type resourceColumns struct {
+  id     string
+  fields map[string]*resourceColumn
+}
+
+func (c *resourceColumns) column(field string, flag columnFlag) (*resourceColumn, bool) {
+  column, ok := c.fields[field]
+  if !ok || column.flags&flag == 0 {
+    return nil, false
+  }
+  return column, true
+}
+
+func (c *resourceColumns) filterable(field string) (string, bool) {
+  column, ok := c.column(field, filterable)
+  if !ok {
+    return "", false
+  }
+  return column.name, true
+}
+
+func (c *resourceColumns) sortable(field string) (string, bool) {
+  column, ok := c.column(field, sortable)
+  if !ok {
+    return "", false
+  }
+  return column.name, true
+}
+
+var tickerColumns = &resourceColumns{
+  id: `ticker.ticker_id`,
+  fields: map[string]*resourceColumn{
+    "ticker_id":           {`ticker.ticker_id`, filterable | sortable},
+    "company_name":        {`ticker.company_name`, filterable | sortable},
+    "company_locale":      {`ticker.company_locale`, filterable | sortable},
+    "company_description": {`ticker_details.company_description`, 0},
+    "company_state":       {`ticker_details.company_state`, filterable | sortable | nullable},
+    "company_city":        {`ticker_details.company_city`, filterable | sortable | nullable},
+    "company_address":     {`ticker_details.company_address`, 0},
+    "homepage_url":        {`ticker_details.homepage_url`, 0},
+    "currency_name":       {`ticker.currency_name`, filterable | sortable},
+    "total_employees":     {`ticker_details.total_employees`, filterable | sortable | nullable},
+    "sic_code":            {`ticker_details.sic_code`, filterable | sortable | nullable},
+    "sic_description":     {`ticker_details.sic_description`, filterable | sortable | nullable},
+    "sector":              {`ticker_details.sector`, filterable | sortable | nullable},
+    "primary_exchange":    {`ticker_details.primary_exchange`, filterable | sortable | nullable},
+    "market":              {`ticker_details.market`, filterable | sortable | nullable},
+    "list_date":           {`ticker_details.list_date`, filterable | sortable | nullable},
+    "active":              {`ticker.active`, filterable | sortable},
+    "created_at":          {`ticker.created_at`, filterable | sortable},
+  },
+}
+
+var stockColumns = &resourceColumns{
+  id: `stock.stock_id`,
+  fields: map[string]*resourceColumn{
+    "stock_id":       {`stock.stock_id`, filterable | sortable},
+    "ticker_id":      {`stock.ticker_id`, filterable | sortable},
+    "open_price":     {`stock.open_price`, filterable | sortable},
+    "close_price":    {`stock.close_price`, filterable | sortable},
+    "highest_price":  {`stock.highest_price`, filterable | sortable},
+    "lowest_price":   {`stock.lowest_price`, filterable | sortable},
+    "trading_volume": {`stock.trading_volume`, filterable | sortable},
+    "timespan":       {`stock.timespan`, filterable},
+    "multiplier":     {`stock.multiplier`, filterable},
+    "stocked_at":     {`stock.stocked_at`, filterable | sortable},
+    "created_at":     {`stock.created_at`, filterable | sortable},
+  },
+}
+
+var financialColumns = &resourceColumns{
+  id: `ticker_financial.financial_id`,
+  fields: map[string]*resourceColumn{
+    "ticker_id":          {`ticker_financial.ticker_id`, filterable | sortable},
+    "timeframe":          {`ticker_financial.timeframe`, filterable | sortable},
+    "fiscal_period":      {`ticker_financial.fiscal_period`, filterable | sortable},
+    "fiscal_year":        {`ticker_financial.fiscal_year`, filterable | sortable},
+    "start_date":         {`ticker_financial.start_date`, filterable | sortable},
+    "end_date":           {`ticker_financial.end_date`, filterable | sortable},
+    "filing_date":        {`ticker_financial.filing_date`, filterable | sortable | nullable},
+    "revenue":            {`ticker_financial.revenue`, filterable | sortable | nullable},
+    "net_income":         {`ticker_financial.net_income`, filterable | sortable | nullable},
+    "basic_eps":          {`ticker_financial.basic_eps`, filterable | sortable | nullable},
+    "diluted_eps":        {`ticker_financial.diluted_eps`, filterable | sortable | nullable},
+    "shares_outstanding": {`ticker_financial.shares_outstanding`, filterable | sortable | nullable},
+    "updated_at":         {`ticker_financial.updated_at`, filterable | sortable},
+  },
+}
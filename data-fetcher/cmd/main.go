@@ -4,13 +4,10 @@ import (
   "context"
   "flag"
   "main/internal/fetcher"
-  "net/http"
-  "os"
-  "os/signal"
-  "syscall"
+  "main/internal/handler"
 
   "github.com/UshakovN/stock-predictor-service/config"
-  "github.com/UshakovN/stock-predictor-service/utils"
+  "github.com/UshakovN/stock-predictor-service/lifecycle"
   log "github.com/sirupsen/logrus"
 )
 
@@ -22,8 +19,13 @@ import (
 // @BasePath /
 // @schemes http
 //
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-Service-Token
+//
 func main() {
-  ctx := context.Background()
+  lc := lifecycle.New()
+  ctx := lc.Context()
 
   servePort := flag.String("port", "8082", "serving port")
   configPath := flag.String("path", "", "path to service config file")
@@ -43,33 +45,33 @@ func main() {
     f.SetTickerId(*tickerId)
   }
 
-  http.Handle("/health", HandleHealthWrapper())
+  h := handler.NewHandler(ctx, f, cfg.ControlApiToken)
+  h.BindRouter()
 
-  go utils.ContinuouslyServe(*servePort)
+  lc.ServeHttp(*servePort)
   log.Infof("ready for serve http on port: %s", *servePort)
 
-  go f.ContinuouslyFetch()
-  defer f.SaveFetcherState()
-
-  serviceShutdown()
-}
+  lc.Go("fetching", func() {
+    // closers still save the fetcher state when fetching failed
+    if err := f.ContinuouslyFetch(); err != nil {
+      log.Errorf("fetching stopped: %v", err)
+      lc.Stop()
+    }
+  })
+  lc.Go("gaps filling", f.ContinuouslyFillGaps)
+  lc.Go("branding outbox relay", f.ContinuouslyRelayOutbox)
+  lc.Go("stream ingestion", f.ContinuouslyStream)
 
-func serviceShutdown() {
-  exitSignal := make(chan os.Signal)
-  signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
+  // closers are called after fetching and gaps filling stopped,
+  // tickers run requested by the control API is waited before the state saving
+  lc.OnShutdown("tickers run", f.WaitRuns)
+  lc.OnShutdown("fetcher state", func(context.Context) error {
+    f.SaveFetcherState()
+    return nil
+  })
+  lc.OnShutdown("fetcher clients", func(context.Context) error {
+    return f.Close()
+  })
 
-  <-exitSignal
-}
-
-// HandleHealthWrapper
-//
-// @Summary Health check method
-// @Description Health method check http server health
-// @Tags Health
-// @Produce application/json
-// @Success 200 {object} common.HealthResponse
-// @Router /health [get]
-//
-func HandleHealthWrapper() http.HandlerFunc {
-  return utils.HandleHealth()
+  lc.Wait()
 }
@@ -7,6 +7,7 @@ replace github.com/UshakovN/stock-predictor-service v0.0.0-20230414193523-7fa2be
 require (
 	github.com/Masterminds/squirrel v1.5.4
 	github.com/UshakovN/stock-predictor-service v0.0.0-20230414193523-7fa2be658f07
+	github.com/gorilla/websocket v1.5.0
 	github.com/jackc/pgx/v4 v4.18.1
 	github.com/rabbitmq/amqp091-go v1.8.0
 	github.com/sirupsen/logrus v1.9.0
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type FetcherProgress struct {
+  Mode         string           `json:"mode"`
+  Paused       bool             `json:"paused"`
+  Running      bool             `json:"running"`
+  Finished     bool             `json:"finished"`
+  UpdatedAt    *time.Time       `json:"updated_at"`
+  TickerCursor string           `json:"ticker_cursor"`
+  Counters     *StorageCounters `json:"counters"`
+}
+
+type ScheduledTicker struct {
+  TickerId      string     `json:"ticker_id"`
+  Tier          string     `json:"tier"`
+  Priority      float64    `json:"priority"`
+  Subscribers   int        `json:"subscribers"`
+  LastBarAt     *time.Time `json:"last_bar_at"`
+  RefreshedAt   *time.Time `json:"refreshed_at"`
+  NextRefreshAt time.Time  `json:"next_refresh_at"`
+  Due           bool       `json:"due"`
+}
+
+type FetchError struct {
+  TickerId   string    `json:"ticker_id"`
+  Message    string    `json:"message"`
+  OccurredAt time.Time `json:"occurred_at"`
+}
@@ -14,35 +14,167 @@ type Ticker struct {
 }
 
 type TickerDetails struct {
-  TickerId           string    `json:"ticker_id"`
-  CompanyDescription string    `json:"company_description"`
-  HomepageUrl        string    `json:"homepage_url"`
-  PhoneNumber        string    `json:"phone_number"`
-  TotalEmployees     int       `json:"total_employees"`
-  CompanyState       string    `json:"company_state"`
-  CompanyCity        string    `json:"company_city"`
-  CompanyAddress     string    `json:"company_address"`
-  CompanyPostalCode  string    `json:"company_postal_code"`
-  CreatedAt          time.Time `json:"created_at"`
+  TickerId           string     `json:"ticker_id"`
+  CompanyDescription string     `json:"company_description"`
+  HomepageUrl        string     `json:"homepage_url"`
+  PhoneNumber        string     `json:"phone_number"`
+  TotalEmployees     int        `json:"total_employees"`
+  CompanyState       string     `json:"company_state"`
+  CompanyCity        string     `json:"company_city"`
+  CompanyAddress     string     `json:"company_address"`
+  CompanyPostalCode  string     `json:"company_postal_code"`
+  SicCode            string     `json:"sic_code"`
+  SicDescription     string     `json:"sic_description"`
+  Sector             string     `json:"sector"`
+  PrimaryExchange    string     `json:"primary_exchange"`
+  Market             string     `json:"market"`
+  ListDate           *time.Time `json:"list_date"`
+  CreatedAt          time.Time  `json:"created_at"`
+  UpdatedAt          time.Time  `json:"updated_at"`
 }
 
 type Stock struct {
+  StockId          string    `json:"stock_id"`
+  TickerId         string    `json:"ticker_id"`
+  OpenPrice        float64   `json:"open_price"`
+  ClosePrice       float64   `json:"close_price"`
+  HighestPrice     float64   `json:"highest_price"`
+  LowestPrice      float64   `json:"lowest_price"`
+  TradingVolume    float64   `json:"trading_volume"`
+  AdjOpenPrice     float64   `json:"adj_open_price"`
+  AdjClosePrice    float64   `json:"adj_close_price"`
+  AdjHighestPrice  float64   `json:"adj_highest_price"`
+  AdjLowestPrice   float64   `json:"adj_lowest_price"`
+  AdjTradingVolume float64   `json:"adj_trading_volume"`
+  Timespan         string    `json:"timespan"`
+  Multiplier       int       `json:"multiplier"`
+  StockedAt        time.Time `json:"stocked_time"`
+  CreatedAt        time.Time `json:"created_at"`
+}
+
+type FetcherState struct {
+  StateId             int       `json:"state_id"`
+  TickerReqUrl        string    `json:"ticker_req_url"`
+  TickerDetailsReqUrl string    `json:"ticker_details_req_url"`
+  StockReqUrl         string    `json:"stock_req_url"`
+  CreatedAt           time.Time `json:"created_at"`
+  Finished            bool      `json:"finished"`
+}
+
+type StockGap struct {
+  GapId       string    `json:"gap_id"`
+  TickerId    string    `json:"ticker_id"`
+  Timespan    string    `json:"timespan"`
+  Multiplier  int       `json:"multiplier"`
+  DateFrom    time.Time `json:"date_from"`
+  DateTo      time.Time `json:"date_to"`
+  MissingDays int       `json:"missing_days"`
+  FilledDays  int       `json:"filled_days"`
+  Status      string    `json:"status"`
+  Attempts    int       `json:"attempts"`
+  CreatedAt   time.Time `json:"created_at"`
+  UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type FetcherCheckpoint struct {
+  TickerId   string    `json:"ticker_id"`
+  Timespan   string    `json:"timespan"`
+  Multiplier int       `json:"multiplier"`
+  LastBarAt  time.Time `json:"last_bar_at"`
+  Cursor     string    `json:"cursor"`
+  LastError  string    `json:"last_error"`
+  UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TickerActivity summarize fetcher checkpoints of the ticker over all granularities
+type TickerActivity struct {
+  TickerId    string     `json:"ticker_id"`
+  LastBarAt   *time.Time `json:"last_bar_at"`
+  RefreshedAt *time.Time `json:"refreshed_at"`
+}
+
+// BrandingOutbox is media service message stored together with ticker details
+// and published by the relay, so branding is not lost while the queue is unavailable
+type BrandingOutbox struct {
+  OutboxId      string    `json:"outbox_id"`
+  TickerId      string    `json:"ticker_id"`
+  BrandingType  string    `json:"branding_type"`
+  ImageUrl      string    `json:"image_url"`
+  Status        string    `json:"status"`
+  Attempts      int       `json:"attempts"`
+  LastError     string    `json:"last_error"`
+  NextAttemptAt time.Time `json:"next_attempt_at"`
+  CreatedAt     time.Time `json:"created_at"`
+  UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type StorageCounters struct {
+  Tickers       uint64 `json:"tickers"`
+  TickerDetails uint64 `json:"ticker_details"`
+  Stocks        uint64 `json:"stocks"`
+}
+
+type StockQuarantine struct {
+  QuarantineId  string    `json:"quarantine_id"`
   StockId       string    `json:"stock_id"`
   TickerId      string    `json:"ticker_id"`
+  Timespan      string    `json:"timespan"`
+  Multiplier    int       `json:"multiplier"`
   OpenPrice     float64   `json:"open_price"`
   ClosePrice    float64   `json:"close_price"`
   HighestPrice  float64   `json:"highest_price"`
   LowestPrice   float64   `json:"lowest_price"`
   TradingVolume float64   `json:"trading_volume"`
   StockedAt     time.Time `json:"stocked_time"`
+  Rule          string    `json:"rule"`
+  Reason        string    `json:"reason"`
   CreatedAt     time.Time `json:"created_at"`
 }
 
-type FetcherState struct {
-  StateId             int       `json:"state_id"`
-  TickerReqUrl        string    `json:"ticker_req_url"`
-  TickerDetailsReqUrl string    `json:"ticker_details_req_url"`
-  StockReqUrl         string    `json:"stock_req_url"`
-  CreatedAt           time.Time `json:"created_at"`
-  Finished            bool      `json:"finished"`
+type QuarantineCount struct {
+  Rule  string `json:"rule"`
+  Count int    `json:"count"`
+}
+
+type StockSplit struct {
+  SplitId       string    `json:"split_id"`
+  TickerId      string    `json:"ticker_id"`
+  ExecutionDate time.Time `json:"execution_date"`
+  SplitFrom     float64   `json:"split_from"`
+  SplitTo       float64   `json:"split_to"`
+  CreatedAt     time.Time `json:"created_at"`
+}
+
+type StockDividend struct {
+  DividendId      string     `json:"dividend_id"`
+  TickerId        string     `json:"ticker_id"`
+  CashAmount      float64    `json:"cash_amount"`
+  Currency        string     `json:"currency"`
+  DividendType    string     `json:"dividend_type"`
+  Frequency       int        `json:"frequency"`
+  ExDividendDate  time.Time  `json:"ex_dividend_date"`
+  DeclarationDate *time.Time `json:"declaration_date"`
+  RecordDate      *time.Time `json:"record_date"`
+  PayDate         *time.Time `json:"pay_date"`
+  CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TickerFinancial is quarterly or annual financials of the ticker,
+// metrics not reported for the period are nil
+type TickerFinancial struct {
+  FinancialId       string     `json:"financial_id"`
+  TickerId          string     `json:"ticker_id"`
+  Timeframe         string     `json:"timeframe"`
+  FiscalPeriod      string     `json:"fiscal_period"`
+  FiscalYear        int        `json:"fiscal_year"`
+  StartDate         time.Time  `json:"start_date"`
+  EndDate           time.Time  `json:"end_date"`
+  FilingDate        *time.Time `json:"filing_date"`
+  Revenue           *float64   `json:"revenue"`
+  NetIncome         *float64   `json:"net_income"`
+  BasicEps          *float64   `json:"basic_eps"`
+  DilutedEps        *float64   `json:"diluted_eps"`
+  SharesOutstanding *float64   `json:"shares_outstanding"`
+  CreatedAt         time.Time  `json:"created_at"`
+  UpdatedAt         time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,111 @@
+package events
+
+import (
+  "context"
+  "encoding/json"
+  "fmt"
+
+  datafetcher "github.com/UshakovN/stock-predictor-service/contract/data-fetcher"
+  "github.com/UshakovN/stock-predictor-service/rabbitmq"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  ampq "github.com/rabbitmq/amqp091-go"
+  log "github.com/sirupsen/logrus"
+)
+
+const (
+  exchangeKind       = ampq.ExchangeTopic
+  exchangeDurable    = true
+  exchangeAutoDelete = false
+  exchangeInternal   = false
+  exchangeNoWait     = false
+
+  publishMandatory = false
+  publishImmediate = false
+
+  contentTypeJSON = "application/json"
+)
+
+type Publisher interface {
+  PublishStocksUpdated(message *datafetcher.StocksUpdatedMessage) error
+  Close() error
+}
+
+type publisher struct {
+  ctx context.Context
+  mq  rabbitmq.Client
+}
+
+// NewPublisher declare the events exchange, consumers bind their own queues to it
+func NewPublisher(ctx context.Context, config *rabbitmq.Config) (Publisher, error) {
+  mq, err := rabbitmq.NewClient(config)
+  if err != nil {
+    return nil, fmt.Errorf("cannot create new events client: %v", err)
+  }
+  var args ampq.Table // dummy args
+
+  if err = mq.ExchangeDeclare(
+    datafetcher.EventsExchange,
+    exchangeKind,
+    exchangeDurable,
+    exchangeAutoDelete,
+    exchangeInternal,
+    exchangeNoWait,
+    args,
+  ); err != nil {
+    return nil, fmt.Errorf("cannot declare events exchange: %v", err)
+  }
+  log.Infof("init events publisher to '%s' exchange", datafetcher.EventsExchange)
+
+  return &publisher{
+    ctx: ctx,
+    mq:  mq,
+  }, nil
+}
+
+func (p *publisher) PublishStocksUpdated(message *datafetcher.StocksUpdatedMessage) error {
+  if message == nil {
+    return fmt.Errorf("message is a nil")
+  }
+  body, err := json.Marshal(message)
+  if err != nil {
+    return fmt.Errorf("cannot marshal message: %v", err)
+  }
+  if err = p.mq.PublishWithContext(
+    p.ctx,
+    datafetcher.EventsExchange,
+    datafetcher.EventStocksUpdated,
+    publishMandatory,
+    publishImmediate,
+    ampq.Publishing{
+      ContentType:  contentTypeJSON,
+      DeliveryMode: ampq.Persistent,
+      Body:         body,
+      Timestamp:    utils.NotTimeUTC(),
+    },
+  ); err != nil {
+    return fmt.Errorf("cannot publish message: %v", err)
+  }
+  log.Infof("event '%s' for ticker '%s' with %d bars published",
+    datafetcher.EventStocksUpdated, message.TickerId, message.Count)
+
+  return nil
+}
+
+func (p *publisher) Close() error {
+  return p.mq.Close()
+}
+
+// nopPublisher is used when events publishing is disabled
+type nopPublisher struct{}
+
+func NewNopPublisher() Publisher {
+  return nopPublisher{}
+}
+
+func (nopPublisher) PublishStocksUpdated(*datafetcher.StocksUpdatedMessage) error {
+  return nil
+}
+
+func (nopPublisher) Close() error {
+  return nil
+}
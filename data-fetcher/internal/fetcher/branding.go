@@ -3,9 +3,18 @@ package fetcher
 import (
   "fmt"
   "main/internal/domain"
+  "main/internal/provider"
+  "main/internal/storage"
   "strings"
+  "time"
 
   "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+const (
+  brandingTypeIcon = "icon"
+  brandingTypeLogo = "logo"
 )
 
 func (f *fetcher) formMsgForBrandingImage(tickerId, imageURL, brandingType string) (*domain.PutMessage, error) {
@@ -14,9 +23,9 @@ func (f *fetcher) formMsgForBrandingImage(tickerId, imageURL, brandingType strin
     nameDashSep = "-"
     nameDotSep  = "."
   )
-  imageResp, err := f.client.GetFullResp(imageURL, nil)
+  imageContent, err := f.provider.GetBrandingImage(imageURL)
   if err != nil {
-    return nil, fmt.Errorf("cannot get image response for ticker '%s': %v", tickerId, err)
+    return nil, fmt.Errorf("cannot get image for ticker '%s': %v", tickerId, err)
   }
 
   imageExtension, err := utils.ExtractFileExtension(imageURL)
@@ -29,48 +38,125 @@ func (f *fetcher) formMsgForBrandingImage(tickerId, imageURL, brandingType strin
 
   return &domain.PutMessage{
     MetaInfo: &domain.PutMessageMetaInfo{
-      Name:      imageName,
-      Section:   sectionName,
-      Overwrite: false,
+      Name:    imageName,
+      Section: sectionName,
+      // outbox message is queued again only when image url changed
+      Overwrite: true,
       From:      fetcherName,
       Timestamp: utils.NowTimestampUTC(),
     },
-    Content: imageResp.Content,
+    Content: imageContent,
   }, nil
 }
 
-func (f *fetcher) sendMessagesToPutTickerBranding(tickerId string, branding *tickerDetailsBranding) error {
-  const (
-    brandingTypeIcon = "icon"
-    brandingTypeLogo = "logo"
-  )
+// createBrandingOutbox form outbox messages stored together with ticker details
+func createBrandingOutbox(tickerId string, branding *provider.TickerBranding) []*domain.BrandingOutbox {
   if tickerId == "" || branding == nil {
     return nil
   }
-  iconURL := strings.TrimSpace(branding.IconUrl)
+  var outbox []*domain.BrandingOutbox
 
-  if iconURL != "" {
-    // form and send message if icon url not empty
-    iconPutMsg, err := f.formMsgForBrandingImage(tickerId, iconURL, brandingTypeIcon)
-    if err != nil {
-      return err
-    }
-    if err = f.msQueue.PublishMessage(iconPutMsg); err != nil {
-      return err
+  for _, image := range []struct {
+    brandingType string
+    url          string
+  }{
+    {brandingTypeIcon, branding.IconUrl},
+    {brandingTypeLogo, branding.LogoUrl},
+  } {
+    imageURL := strings.TrimSpace(image.url)
+    if imageURL == "" {
+      continue
     }
+    now := utils.NotTimeUTC()
+
+    outbox = append(outbox, &domain.BrandingOutbox{
+      OutboxId:      fmt.Sprint(tickerId, "-", image.brandingType),
+      TickerId:      tickerId,
+      BrandingType:  image.brandingType,
+      ImageUrl:      imageURL,
+      Status:        storage.OutboxStatusPending,
+      NextAttemptAt: now,
+      CreatedAt:     now,
+      UpdatedAt:     now,
+    })
   }
+  return outbox
+}
 
-  logoURL := strings.TrimSpace(branding.LogoUrl)
-  if logoURL != "" {
-    // form and send message if logo url not empty
-    logoPutMsg, err := f.formMsgForBrandingImage(tickerId, logoURL, brandingTypeLogo)
-    if err != nil {
-      return err
+// ContinuouslyRelayOutbox publish pending branding messages to media service queue
+func (f *fetcher) ContinuouslyRelayOutbox() {
+  ticker := time.NewTicker(outboxRelayInterval)
+  defer ticker.Stop()
+
+  for {
+    if err := f.relayOutbox(); err != nil {
+      log.Errorf("branding outbox relay failed: %v", err)
     }
-    if err = f.msQueue.PublishMessage(logoPutMsg); err != nil {
-      return err
+    select {
+    case <-f.ctx.Done():
+      return
+    case <-ticker.C:
     }
   }
+}
 
+func (f *fetcher) relayOutbox() error {
+  outbox, err := f.storage.GetPendingBrandingOutbox(outboxRelayBatchSize)
+  if err != nil {
+    return fmt.Errorf("cannot get pending branding outbox: %v", err)
+  }
+  for _, message := range outbox {
+    if f.ctx.Err() != nil {
+      return nil
+    }
+    f.publishOutboxMessage(message)
+
+    if err = f.storage.PutBrandingOutboxAttempt(message); err != nil {
+      return fmt.Errorf("cannot put branding outbox attempt: %v", err)
+    }
+  }
   return nil
 }
+
+// publishOutboxMessage send branding image and set result of the attempt to the message
+func (f *fetcher) publishOutboxMessage(message *domain.BrandingOutbox) {
+  now := utils.NotTimeUTC()
+
+  message.Attempts++
+  message.UpdatedAt = now
+
+  putMsg, err := f.formMsgForBrandingImage(message.TickerId, message.ImageUrl, message.BrandingType)
+  if err == nil {
+    err = f.msQueue.PublishMessage(putMsg)
+  }
+  if err == nil {
+    message.Status = storage.OutboxStatusSent
+    message.LastError = ""
+    return
+  }
+  message.LastError = err.Error()
+  // message is never dropped, it is retried with the longest interval until media service is available
+  message.Status = storage.OutboxStatusPending
+  message.NextAttemptAt = now.Add(outboxBackoff(message.Attempts))
+
+  if message.Attempts >= outboxAlertAttempts {
+    log.Errorf("branding outbox '%s' attempt %d failed: %v. next attempt at %s",
+      message.OutboxId, message.Attempts, err, message.NextAttemptAt.Format(time.RFC3339))
+    return
+  }
+  log.Warnf("branding outbox '%s' attempt %d failed: %v. next attempt at %s",
+    message.OutboxId, message.Attempts, err, message.NextAttemptAt.Format(time.RFC3339))
+}
+
+// outboxBackoff double retry interval with each attempt up to the limit
+func outboxBackoff(attempts int) time.Duration {
+  backoff := outboxMinBackoff
+
+  for idx := 1; idx < attempts && backoff < outboxMaxBackoff; idx++ {
+    backoff *= 2
+  }
+  if backoff > outboxMaxBackoff {
+    backoff = outboxMaxBackoff
+  }
+  return backoff
+}
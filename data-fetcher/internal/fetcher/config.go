@@ -1,17 +1,61 @@
 package fetcher
 
 import (
+  "fmt"
+  "main/internal/provider"
   "main/internal/queue/rabbitmq"
+  "main/internal/stream"
 
   "github.com/UshakovN/stock-predictor-service/postgres"
 )
 
 type Config struct {
-  ModeTotalHours   int              `yaml:"total_mode_hours" required:"true"`
-  ModeCurrentHours int              `yaml:"current_mode_hours" required:"true"`
-  ApiToken         string           `yaml:"api_token" required:"true"`
-  StorageConfig    *postgres.Config `yaml:"storage_config" required:"true"`
-  QueueConfig      *rabbitmq.Config `yaml:"queue_config" required:"true"`
+  ModeTotalHours   int               `yaml:"total_mode_hours" required:"true"`
+  ModeCurrentHours int               `yaml:"current_mode_hours" required:"true"`
+  WorkersCount     int               `yaml:"workers_count"`
+  StocksBatchSize  int               `yaml:"stocks_batch_size"`
+  UpdateTickers    bool              `yaml:"update_tickers"`
+  Granularities    []*Granularity    `yaml:"granularities"`
+  GapsCheckHours   int               `yaml:"gaps_check_hours"`
+  ControlApiToken  string            `yaml:"control_api_token"`
+  PublishEvents    bool              `yaml:"publish_events"`
+  ValidationConfig *ValidationConfig `yaml:"validation_config"`
+  UniverseConfig   *UniverseConfig   `yaml:"universe_config"`
+  SchedulerConfig  *SchedulerConfig  `yaml:"scheduler_config"`
+  StreamConfig     *stream.Config    `yaml:"stream_config"`
+  ProviderConfig   *provider.Config  `yaml:"provider_config" required:"true"`
+  StorageConfig    *postgres.Config  `yaml:"storage_config" required:"true"`
+  QueueConfig      *rabbitmq.Config  `yaml:"queue_config" required:"true"`
+}
+
+type Granularity struct {
+  Multiplier int    `yaml:"multiplier" required:"true"`
+  Timespan   string `yaml:"timespan" required:"true"`
+}
+
+func (g *Granularity) String() string {
+  return fmt.Sprint(g.Multiplier, g.Timespan)
+}
+
+func (g *Granularity) Validate() error {
+  if g.Multiplier <= 0 {
+    return fmt.Errorf("granularity multiplier must be positive")
+  }
+  if !provider.ValidTimespan(g.Timespan) {
+    return fmt.Errorf("unknown granularity timespan '%s'", g.Timespan)
+  }
+  return nil
+}
+
+// isDefault report that granularity is daily bars,
+// which were the only fetched granularity before
+func (g *Granularity) isDefault() bool {
+  return g.Multiplier == defaultGranularity.Multiplier && g.Timespan == defaultGranularity.Timespan
+}
+
+var defaultGranularity = &Granularity{
+  Multiplier: 1,
+  Timespan:   provider.TimespanDay,
 }
 
 func NewConfig() *Config {
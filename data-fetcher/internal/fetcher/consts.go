@@ -8,34 +8,44 @@ const (
   fetcherModeTotal   = 0
   fetcherModeCurrent = 1
   fetcherRetryCount  = 10
+
+  defaultWorkersCount  = 1
+  recentErrorsCapacity = 100
 )
 
 const (
-  recentlyFetchedSleepInterval  = 1 * time.Hour
   encounteredErrorSleepInterval = 10 * time.Minute
   recentlyThresholdInterval     = 24 * time.Hour
 )
 
 const (
-  polygonReqsLimit   = 5
-  polygonReqPerDur   = 1 * time.Minute
-  polygonWaitDur     = 10 * time.Second
-  polygonDeadlineDur = 120 * time.Second
+  defaultHighPriorityInterval = 1 * time.Hour
+  defaultLowPriorityInterval  = 24 * time.Hour
+  defaultMinSubscribers       = 1
 )
 
 const (
-  respCursorKey = "cursor"
-  respStatusOK  = "OK"
+  // priority terms are normalized to [0, 1) and weighted, so neither of them outgrow the other
+  prioritySubscribersWeight = 0.6
+  priorityStalenessWeight   = 0.4
+  // subscriptions count and stale days which give the half of the term
+  prioritySubscribersScale = 10
+  priorityStalenessDays    = 7
 )
 
 const (
-  basePrefixApi = "https://api.polygon.io"
-
-  tickersApi       = "/v3/reference/tickers"
-  stocksApi        = "/v2/aggs/ticker/%s/range/%d/%s/%s/%s"
-  tickerDetailsApi = "/v3/reference/tickers/%s"
+  outboxRelayInterval  = 1 * time.Minute
+  outboxRelayBatchSize = 50
+  outboxAlertAttempts  = 10
+  outboxMinBackoff     = 1 * time.Minute
+  outboxMaxBackoff     = 1 * time.Hour
+)
 
-  apiTokenKey = "apiKey"
+const (
+  financialsRefreshInterval       = 24 * time.Hour
+  tickerDetailsRefreshInterval    = 7 * 24 * time.Hour
+  corporateActionsRefreshInterval = 24 * time.Hour
+  delistedTickersRefreshInterval  = 24 * time.Hour
 )
 
 const defaultStringValue = "N/A"
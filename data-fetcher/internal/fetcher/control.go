@@ -0,0 +1,280 @@
+package fetcher
+
+import (
+  "context"
+  "errors"
+  "fmt"
+  "main/internal/domain"
+  "main/internal/provider"
+  "sync"
+
+  datafetcher "github.com/UshakovN/stock-predictor-service/contract/data-fetcher"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+var ErrRunInProgress = errors.New("tickers run already in progress")
+
+// control hold operator commands received by the control API
+type control struct {
+  mu       sync.Mutex
+  paused   bool
+  resumeCh chan struct{}
+  running  bool
+  // requested runs are waited on shutdown before storage is closed
+  runs sync.WaitGroup
+}
+
+func newControl() *control {
+  resumeCh := make(chan struct{})
+  // fetching is not paused on start
+  close(resumeCh)
+
+  return &control{
+    resumeCh: resumeCh,
+  }
+}
+
+func (c *control) pause() bool {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  if c.paused {
+    return false
+  }
+  c.paused = true
+  c.resumeCh = make(chan struct{})
+
+  return true
+}
+
+func (c *control) resume() bool {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  if !c.paused {
+    return false
+  }
+  c.paused = false
+  close(c.resumeCh)
+
+  return true
+}
+
+// resumed return channel closed when fetching is not paused
+func (c *control) resumed() <-chan struct{} {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  return c.resumeCh
+}
+
+func (c *control) isPaused() bool {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  return c.paused
+}
+
+func (c *control) startRun() bool {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  if c.running {
+    return false
+  }
+  c.running = true
+  c.runs.Add(1)
+
+  return true
+}
+
+func (c *control) finishRun() {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  c.running = false
+  c.runs.Done()
+}
+
+func (c *control) isRunning() bool {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  return c.running
+}
+
+// errorsLog keep the last fetching errors in ring buffer
+type errorsLog struct {
+  mu     sync.Mutex
+  errors []*domain.FetchError
+  next   int
+}
+
+func newErrorsLog(capacity int) *errorsLog {
+  return &errorsLog{
+    errors: make([]*domain.FetchError, 0, capacity),
+  }
+}
+
+func (l *errorsLog) add(fetchErr *domain.FetchError) {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+
+  if len(l.errors) < cap(l.errors) {
+    l.errors = append(l.errors, fetchErr)
+    return
+  }
+  l.errors[l.next] = fetchErr
+  l.next = (l.next + 1) % len(l.errors)
+}
+
+// last return up to limit errors from the newest to the oldest
+func (l *errorsLog) last(limit int) []*domain.FetchError {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+
+  count := len(l.errors)
+  if limit <= 0 || limit > count {
+    limit = count
+  }
+  fetchErrors := make([]*domain.FetchError, 0, limit)
+
+  for idx := 0; idx < limit; idx++ {
+    // the newest error is placed before the next overwritten position
+    pos := (l.next - 1 - idx + 2*count) % count
+    fetchErrors = append(fetchErrors, l.errors[pos])
+  }
+  return fetchErrors
+}
+
+func (f *fetcher) recordError(tickerId string, err error) {
+  f.errors.add(&domain.FetchError{
+    TickerId:   tickerId,
+    Message:    err.Error(),
+    OccurredAt: utils.NotTimeUTC(),
+  })
+}
+
+func (f *fetcher) Pause() {
+  if f.control.pause() {
+    log.Infof("fetching paused")
+  }
+}
+
+func (f *fetcher) Resume() {
+  if f.control.resume() {
+    log.Infof("fetching resumed")
+  }
+}
+
+// waitResumed block while fetching is paused.
+// return false if fetcher context is done
+func (f *fetcher) waitResumed() bool {
+  select {
+  case <-f.control.resumed():
+    return true
+  case <-f.ctx.Done():
+    return false
+  }
+}
+
+func (f *fetcher) SetMode(mode string) error {
+  switch mode {
+  case datafetcher.ModeTotal:
+    f.state.SetModeCode(fetcherModeTotal)
+  case datafetcher.ModeCurrent:
+    f.state.SetModeCode(fetcherModeCurrent)
+  default:
+    return fmt.Errorf("unknown fetcher mode '%s'", mode)
+  }
+  return nil
+}
+
+func modeName(modeCode int) string {
+  if modeCode == fetcherModeCurrent {
+    return datafetcher.ModeCurrent
+  }
+  return datafetcher.ModeTotal
+}
+
+func (f *fetcher) GetProgress() *domain.FetcherProgress {
+  finished, updatedAt, modeCode := f.state.GetStatus()
+
+  return &domain.FetcherProgress{
+    Mode:         modeName(modeCode),
+    Paused:       f.control.isPaused(),
+    Running:      f.control.isRunning(),
+    Finished:     finished,
+    UpdatedAt:    updatedAt,
+    TickerCursor: f.state.ticker.getCursor(),
+    Counters:     f.storage.GetCounters(),
+  }
+}
+
+func (f *fetcher) GetRecentErrors(limit int) []*domain.FetchError {
+  return f.errors.last(limit)
+}
+
+// RunTickers start fetching of the specified tickers in background.
+// only one run may be in progress, the main fetching loop is not affected
+func (f *fetcher) RunTickers(tickerIds []string) error {
+  if !f.control.startRun() {
+    return ErrRunInProgress
+  }
+  go func() {
+    defer f.control.finishRun()
+
+    log.Infof("started run for %d requested tickers", len(tickerIds))
+    report := f.processTickers(tickerIds, f.fetchRequestedTicker)
+
+    logTickersReport("requested tickers", report)
+  }()
+
+  return nil
+}
+
+// WaitRuns block until the requested run is finished or context is done.
+// run stop with fetcher context, so it must be called after the context canceled
+func (f *fetcher) WaitRuns(ctx context.Context) error {
+  done := make(chan struct{})
+
+  go func() {
+    f.control.runs.Wait()
+    close(done)
+  }()
+  select {
+  case <-done:
+    return nil
+  case <-ctx.Done():
+    return fmt.Errorf("requested tickers run not finished: %v", ctx.Err())
+  }
+}
+
+func (f *fetcher) fetchRequestedTicker(tickerId string) error {
+  tickersPage, err := f.provider.ListTickers(&provider.ListTickersOption{
+    TickerId: tickerId,
+  })
+  if err != nil {
+    return fmt.Errorf("cannot list tickers: %v", err)
+  }
+  var providerTicker *provider.Ticker
+
+  for _, pageTicker := range tickersPage.Tickers {
+    if pageTicker != nil && pageTicker.Ticker == tickerId {
+      providerTicker = pageTicker
+      break
+    }
+  }
+  if providerTicker == nil {
+    return fmt.Errorf("ticker '%s' not found in %s provider", tickerId, f.provider.Name())
+  }
+  ticker, err := createTicker(providerTicker)
+  if err != nil {
+    return fmt.Errorf("cannot create ticker: %v", err)
+  }
+  if err = f.storage.PutTicker(ticker); err != nil {
+    return fmt.Errorf("cannot put ticker to storage: %v", err)
+  }
+  return f.fetchTickerDetailsAndStocks(tickerId)
+}
@@ -0,0 +1,183 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "main/internal/provider"
+  "main/internal/storage"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+const corporateActionDateFormat = "2006-01-02"
+
+// splitAdjuster calculate adjusted series of raw bars by splits executed after the bar
+type splitAdjuster struct {
+  splits []*domain.StockSplit
+}
+
+func newSplitAdjuster(splits []*domain.StockSplit) *splitAdjuster {
+  return &splitAdjuster{
+    splits: splits,
+  }
+}
+
+// priceRatio return multiplier for prices of the bar stocked at specified time.
+// for 4:1 split all prices before the execution date are divided by 4
+func (a *splitAdjuster) priceRatio(stockedAt time.Time) float64 {
+  ratio := 1.0
+
+  if a == nil {
+    return ratio
+  }
+  for _, split := range a.splits {
+    if stockedAt.Before(split.ExecutionDate) {
+      ratio *= split.SplitFrom / split.SplitTo
+    }
+  }
+  return ratio
+}
+
+func (a *splitAdjuster) adjust(stock *domain.Stock) {
+  ratio := a.priceRatio(stock.StockedAt)
+
+  stock.AdjOpenPrice = stock.OpenPrice * ratio
+  stock.AdjClosePrice = stock.ClosePrice * ratio
+  stock.AdjHighestPrice = stock.HighestPrice * ratio
+  stock.AdjLowestPrice = stock.LowestPrice * ratio
+  stock.AdjTradingVolume = stock.TradingVolume / ratio
+}
+
+// refreshCorporateActions return adjuster by splits of the ticker. corporate actions are requested
+// from provider once per interval, stored splits are used between refreshes and on provider errors
+func (f *fetcher) refreshCorporateActions(tickerId string) (*splitAdjuster, error) {
+  refreshedAt, found, err := f.storage.GetTickerRefreshedAt(tickerId, storage.RefreshCorporateActions)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get corporate actions refresh time from storage: %v", err)
+  }
+  if found && utils.NotTimeUTC().Sub(refreshedAt) < corporateActionsRefreshInterval {
+    return f.loadSplitAdjuster(tickerId)
+  }
+  adjuster, err := f.fetchCorporateActions(tickerId)
+  if err != nil {
+    log.Errorf("cannot fetch corporate actions for ticker '%s'. stored splits will be used: %v", tickerId, err)
+
+    return f.loadSplitAdjuster(tickerId)
+  }
+  if err = f.storage.PutTickerRefreshedAt(tickerId, storage.RefreshCorporateActions, utils.NotTimeUTC()); err != nil {
+    log.Errorf("cannot put corporate actions refresh time for ticker '%s': %v", tickerId, err)
+  }
+  return adjuster, nil
+}
+
+// fetchCorporateActions store splits and dividends of the ticker
+// and return adjuster by all known splits
+func (f *fetcher) fetchCorporateActions(tickerId string) (*splitAdjuster, error) {
+  providerSplits, err := f.provider.GetSplits(tickerId)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get splits: %v", err)
+  }
+  splits := make([]*domain.StockSplit, 0, len(providerSplits))
+
+  for _, providerSplit := range providerSplits {
+    split, err := createStockSplit(tickerId, providerSplit)
+    if err != nil {
+      log.Warnf("skip malformed split for ticker '%s': %v", tickerId, err)
+      continue
+    }
+    if split != nil {
+      splits = append(splits, split)
+    }
+  }
+  if err = f.storage.PutStockSplits(splits); err != nil {
+    return nil, fmt.Errorf("cannot put splits to storage: %v", err)
+  }
+
+  providerDividends, err := f.provider.GetDividends(tickerId)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get dividends: %v", err)
+  }
+  dividends := make([]*domain.StockDividend, 0, len(providerDividends))
+
+  for _, providerDividend := range providerDividends {
+    dividend, err := createStockDividend(tickerId, providerDividend)
+    if err != nil {
+      log.Warnf("skip malformed dividend for ticker '%s': %v", tickerId, err)
+      continue
+    }
+    if dividend != nil {
+      dividends = append(dividends, dividend)
+    }
+  }
+  if err = f.storage.PutStockDividends(dividends); err != nil {
+    return nil, fmt.Errorf("cannot put dividends to storage: %v", err)
+  }
+  return newSplitAdjuster(splits), nil
+}
+
+// loadSplitAdjuster return adjuster by splits already stored for the ticker
+func (f *fetcher) loadSplitAdjuster(tickerId string) (*splitAdjuster, error) {
+  splits, err := f.storage.GetStockSplits(tickerId)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get splits from storage: %v", err)
+  }
+  return newSplitAdjuster(splits), nil
+}
+
+func createStockSplit(tickerId string, split *provider.Split) (*domain.StockSplit, error) {
+  if split == nil {
+    return nil, nil
+  }
+  if split.SplitFrom <= 0 || split.SplitTo <= 0 {
+    return nil, fmt.Errorf("malformed split ratio %v:%v", split.SplitTo, split.SplitFrom)
+  }
+  executionDate, err := time.Parse(corporateActionDateFormat, split.ExecutionDate)
+  if err != nil {
+    return nil, fmt.Errorf("malformed split execution date '%s'", split.ExecutionDate)
+  }
+  return &domain.StockSplit{
+    SplitId:       fmt.Sprintf("%s-split-%s", tickerId, split.ExecutionDate),
+    TickerId:      tickerId,
+    ExecutionDate: executionDate,
+    SplitFrom:     split.SplitFrom,
+    SplitTo:       split.SplitTo,
+    CreatedAt:     utils.NotTimeUTC(),
+  }, nil
+}
+
+func createStockDividend(tickerId string, dividend *provider.Dividend) (*domain.StockDividend, error) {
+  if dividend == nil {
+    return nil, nil
+  }
+  exDividendDate, err := time.Parse(corporateActionDateFormat, dividend.ExDividendDate)
+  if err != nil {
+    return nil, fmt.Errorf("malformed ex-dividend date '%s'", dividend.ExDividendDate)
+  }
+  stockDividend := &domain.StockDividend{
+    DividendId:      fmt.Sprintf("%s-dividend-%s-%s", tickerId, dividend.ExDividendDate, dividend.DividendType),
+    TickerId:        tickerId,
+    CashAmount:      dividend.CashAmount,
+    Currency:        dividend.Currency,
+    DividendType:    dividend.DividendType,
+    Frequency:       dividend.Frequency,
+    ExDividendDate:  exDividendDate,
+    DeclarationDate: parseOptionalDate(dividend.DeclarationDate),
+    RecordDate:      parseOptionalDate(dividend.RecordDate),
+    PayDate:         parseOptionalDate(dividend.PayDate),
+    CreatedAt:       utils.NotTimeUTC(),
+  }
+  if err = utils.SetDefaultStringValues(stockDividend, defaultStringValue); err != nil {
+    return nil, err
+  }
+  return stockDividend, nil
+}
+
+func parseOptionalDate(value string) *time.Time {
+  date, err := time.Parse(corporateActionDateFormat, value)
+  if err != nil {
+    return nil
+  }
+  return &date
+}
@@ -0,0 +1,97 @@
+package fetcher
+
+import (
+  "main/internal/domain"
+  "time"
+
+  datafetcher "github.com/UshakovN/stock-predictor-service/contract/data-fetcher"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+// storedSeries collect range of the bars stored for one granularity
+type storedSeries struct {
+  granularity *Granularity
+  from        time.Time
+  to          time.Time
+  count       int
+}
+
+// add count stocks inserted by storage. stocks already stored are not inserted again,
+// so the page without inserted stocks does not change the series
+func (s *storedSeries) add(stocks []*domain.Stock, inserted int64) {
+  if inserted == 0 {
+    return
+  }
+  for _, stock := range stocks {
+    if s.from.IsZero() || stock.StockedAt.Before(s.from) {
+      s.from = stock.StockedAt
+    }
+    if s.to.IsZero() || stock.StockedAt.After(s.to) {
+      s.to = stock.StockedAt
+    }
+  }
+  s.count += int(inserted)
+}
+
+// stocksUpdate collect bars stored by the stocks fetching of the ticker
+type stocksUpdate struct {
+  tickerId string
+  series   []*storedSeries
+}
+
+func newStocksUpdate(tickerId string) *stocksUpdate {
+  return &stocksUpdate{
+    tickerId: tickerId,
+  }
+}
+
+func (u *stocksUpdate) newSeries(granularity *Granularity) *storedSeries {
+  series := &storedSeries{
+    granularity: granularity,
+  }
+  u.series = append(u.series, series)
+
+  return series
+}
+
+func (u *stocksUpdate) message() *datafetcher.StocksUpdatedMessage {
+  message := &datafetcher.StocksUpdatedMessage{
+    TickerId:    u.tickerId,
+    Series:      make([]*datafetcher.StocksSeries, 0, len(u.series)),
+    PublishedAt: utils.NotTimeUTC(),
+  }
+  for _, series := range u.series {
+    if series.count == 0 {
+      continue
+    }
+    if message.Count == 0 || series.from.Before(message.From) {
+      message.From = series.from
+    }
+    if message.Count == 0 || series.to.After(message.To) {
+      message.To = series.to
+    }
+    message.Count += series.count
+
+    message.Series = append(message.Series, &datafetcher.StocksSeries{
+      Timespan:   series.granularity.Timespan,
+      Multiplier: series.granularity.Multiplier,
+      From:       series.from,
+      To:         series.to,
+      Count:      series.count,
+    })
+  }
+  return message
+}
+
+// publishStocksUpdated notify consumers about stored bars of the ticker.
+// stocks are already stored, so publishing error does not fail the fetching
+func (f *fetcher) publishStocksUpdated(update *stocksUpdate) {
+  message := update.message()
+  if message.Count == 0 {
+    return
+  }
+  if err := f.publisher.PublishStocksUpdated(message); err != nil {
+    log.Errorf("cannot publish stocks updated event for ticker '%s': %v", update.tickerId, err)
+  }
+}
@@ -2,63 +2,187 @@ package fetcher
 
 import (
   "context"
+  "fmt"
+  "main/internal/domain"
+  "main/internal/events"
+  "main/internal/provider"
   "main/internal/queue"
   "main/internal/storage"
+  "main/internal/stream"
   "sync"
+  "time"
 
-  "github.com/UshakovN/stock-predictor-service/httpclient"
+  "github.com/UshakovN/stock-predictor-service/lifecycle"
+  mq "github.com/UshakovN/stock-predictor-service/rabbitmq"
+  log "github.com/sirupsen/logrus"
 )
 
 type Fetcher interface {
-  ContinuouslyFetch()
+  ContinuouslyFetch() error
+  ContinuouslyFillGaps()
+  ContinuouslyRelayOutbox()
+  ContinuouslyStream()
   SaveFetcherState()
   SetTickerId(tickerId string)
+  GetStockGaps(tickerId, status string) ([]*domain.StockGap, error)
+  RunTickers(tickerIds []string) error
+  WaitRuns(ctx context.Context) error
+  Pause()
+  Resume()
+  SetMode(mode string) error
+  GetProgress() *domain.FetcherProgress
+  GetRecentErrors(limit int) []*domain.FetchError
+  GetQuarantineCounts(tickerId string) ([]*domain.QuarantineCount, error)
+  GetSchedulePlan() ([]*domain.ScheduledTicker, error)
+  Close() error
 }
 
 type fetcher struct {
-  ctx      context.Context
-  client   httpclient.HttpClient
-  storage  storage.Storage
-  msQueue  queue.MediaServiceQueue
-  state    *state
-  once     *sync.Once
-  tickerId string
+  ctx           context.Context
+  provider      provider.MarketDataProvider
+  storage       storage.Storage
+  msQueue       queue.MediaServiceQueue
+  publisher     events.Publisher
+  state         *state
+  once          *sync.Once
+  tickerId      string
+  workersCount  int
+  granularities []*Granularity
+  control       *control
+  errors        *errorsLog
+  validator     *validator
+  universe      *universe
+  scheduler     *scheduler
+  stream        *stream.Client
+  streamTickers []string
+  // delisted tickers are listed separately, so they are updated only in tickers update mode
+  updateTickers     bool
+  delistedCheckedAt time.Time
+  // interval between gaps filling, zero interval disable it
+  gapsCheckInterval time.Duration
 }
 
 func NewFetcher(ctx context.Context, config *Config) (Fetcher, error) {
-  client := httpclient.NewClient(
-    httpclient.WithContext(ctx),
-    httpclient.WithQueryApiToken(
-      apiTokenKey,
-      config.ApiToken,
-    ),
-    httpclient.WithRequestsLimit(
-      polygonReqsLimit,
-      polygonReqPerDur,
-      polygonWaitDur,
-      polygonDeadlineDur,
-    ))
+  marketProvider, err := provider.NewProvider(ctx, config.ProviderConfig)
+  if err != nil {
+    return nil, fmt.Errorf("cannot create market data provider: %v", err)
+  }
+  log.Infof("market data provider: %s", marketProvider.Name())
 
   fetcherState := newFetcherState(
     config.ModeTotalHours,
     config.ModeCurrentHours,
   )
 
-  fetcherStorage, err := storage.NewStorage(ctx, config.StorageConfig)
+  workersCount := config.WorkersCount
+  if workersCount <= 0 {
+    workersCount = defaultWorkersCount
+  }
+  log.Infof("fetcher workers count: %d", workersCount)
+
+  granularities := config.Granularities
+  if len(granularities) == 0 {
+    granularities = []*Granularity{defaultGranularity}
+  }
+  for _, granularity := range granularities {
+    if err = granularity.Validate(); err != nil {
+      return nil, fmt.Errorf("invalid stocks granularity: %v", err)
+    }
+  }
+
+  stocksValidator, err := newValidator(config.ValidationConfig)
+  if err != nil {
+    return nil, fmt.Errorf("invalid stocks validation config: %v", err)
+  }
+
+  tickersUniverse, err := newUniverse(config.UniverseConfig)
+  if err != nil {
+    return nil, fmt.Errorf("invalid tickers universe config: %v", err)
+  }
+  log.Infof("tickers universe mode: %s", tickersUniverse.mode)
+
+  stocksScheduler, err := newScheduler(config.SchedulerConfig)
+  if err != nil {
+    return nil, fmt.Errorf("invalid scheduler config: %v", err)
+  }
+  log.Infof("refresh intervals: high priority %v, low priority %v",
+    stocksScheduler.highInterval, stocksScheduler.lowInterval)
+
+  var streamClient *stream.Client
+  var streamTickers []string
+
+  if config.StreamConfig != nil && config.StreamConfig.Enabled {
+    if streamClient, err = stream.NewClient(config.StreamConfig); err != nil {
+      return nil, fmt.Errorf("invalid stream config: %v", err)
+    }
+    streamTickers = config.StreamConfig.Tickers
+    log.Infof("stream ingestion enabled from: %s", config.StreamConfig.Url)
+  }
+
+  // storage and queue are not canceled with context to flush state on shutdown
+  clientsCtx := lifecycle.WithoutCancel(ctx)
+
+  fetcherStorage, err := storage.NewStorage(clientsCtx, config.StorageConfig,
+    storage.WithStocksBatchSize(config.StocksBatchSize),
+    storage.WithTickersUpdate(config.UpdateTickers),
+  )
   if err != nil {
     return nil, err
   }
-  msQueue, err := queue.NewMediaServiceQueue(ctx, config.QueueConfig)
+  backfilled, err := fetcherStorage.BackfillStocksGranularity()
+  if err != nil {
+    return nil, fmt.Errorf("cannot backfill stocks granularity: %v", err)
+  }
+  if backfilled > 0 {
+    log.Infof("backfilled granularity of %d legacy stocks", backfilled)
+  }
+  msQueue, err := queue.NewMediaServiceQueue(clientsCtx, config.QueueConfig)
   if err != nil {
     return nil, err
   }
+  publisher := events.NewNopPublisher()
+
+  if config.PublishEvents {
+    // events are published to the same broker as media service queue
+    brokerConfig := mq.Config(*config.QueueConfig)
+
+    if publisher, err = events.NewPublisher(clientsCtx, &brokerConfig); err != nil {
+      return nil, fmt.Errorf("cannot create events publisher: %v", err)
+    }
+  }
 
   return &fetcher{
-    ctx:     ctx,
-    client:  client,
-    storage: fetcherStorage,
-    msQueue: msQueue,
-    state:   fetcherState,
-    once:    &sync.Once{},
+    ctx:           ctx,
+    provider:      marketProvider,
+    storage:       fetcherStorage,
+    msQueue:       msQueue,
+    publisher:     publisher,
+    state:         fetcherState,
+    once:          &sync.Once{},
+    workersCount:  workersCount,
+    granularities: granularities,
+    control:       newControl(),
+    errors:        newErrorsLog(recentErrorsCapacity),
+    validator:     stocksValidator,
+    universe:      tickersUniverse,
+    scheduler:     stocksScheduler,
+    stream:        streamClient,
+    streamTickers: streamTickers,
+    updateTickers: config.UpdateTickers,
+
+    gapsCheckInterval: time.Duration(config.GapsCheckHours) * time.Hour,
   }, nil
 }
+
+// Close release storage and queue clients, must be called after fetching stopped
+func (f *fetcher) Close() error {
+  f.storage.Close()
+
+  if err := f.msQueue.Close(); err != nil {
+    return fmt.Errorf("cannot close media service queue: %v", err)
+  }
+  if err := f.publisher.Close(); err != nil {
+    return fmt.Errorf("cannot close events publisher: %v", err)
+  }
+  return nil
+}
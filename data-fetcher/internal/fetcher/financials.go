@@ -0,0 +1,110 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "main/internal/provider"
+  "main/internal/storage"
+  "strconv"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+const (
+  timeframeQuarterly = "quarterly"
+  timeframeAnnual    = "annual"
+)
+
+// fetchFinancials store quarterly and annual financials of the ticker.
+// financials change rarely, so they are refreshed once per interval
+func (f *fetcher) fetchFinancials(tickerId string) error {
+  refreshedAt, found, err := f.storage.GetTickerRefreshedAt(tickerId, storage.RefreshFinancials)
+  if err != nil {
+    return fmt.Errorf("cannot get financials refresh time from storage: %v", err)
+  }
+  if found && utils.NotTimeUTC().Sub(refreshedAt) < financialsRefreshInterval {
+    return nil
+  }
+  providerFinancials, err := f.provider.GetFinancials(tickerId)
+  if err != nil {
+    return fmt.Errorf("cannot get financials: %v", err)
+  }
+  financials := make([]*domain.TickerFinancial, 0, len(providerFinancials))
+
+  for _, providerFinancial := range providerFinancials {
+    financial, err := createTickerFinancial(tickerId, providerFinancial)
+    if err != nil {
+      log.Warnf("skip malformed financials for ticker '%s': %v", tickerId, err)
+      continue
+    }
+    if financial != nil {
+      financials = append(financials, financial)
+    }
+  }
+  if err = f.storage.PutTickerFinancials(financials); err != nil {
+    return fmt.Errorf("cannot put financials to storage: %v", err)
+  }
+  // ticker without financials is refreshed once per interval too
+  if err = f.storage.PutTickerRefreshedAt(tickerId, storage.RefreshFinancials, utils.NotTimeUTC()); err != nil {
+    return fmt.Errorf("cannot put financials refresh time to storage: %v", err)
+  }
+  return nil
+}
+
+// createTickerFinancial return nil for trailing twelve months and other not stored timeframes
+func createTickerFinancial(tickerId string, financial *provider.Financial) (*domain.TickerFinancial, error) {
+  if financial == nil {
+    return nil, nil
+  }
+  if financial.Timeframe != timeframeQuarterly && financial.Timeframe != timeframeAnnual {
+    return nil, nil
+  }
+  fiscalYear, err := strconv.Atoi(financial.FiscalYear)
+  if err != nil {
+    return nil, fmt.Errorf("malformed fiscal year '%s'", financial.FiscalYear)
+  }
+  startDate, err := time.Parse(corporateActionDateFormat, financial.StartDate)
+  if err != nil {
+    return nil, fmt.Errorf("malformed start date '%s'", financial.StartDate)
+  }
+  endDate, err := time.Parse(corporateActionDateFormat, financial.EndDate)
+  if err != nil {
+    return nil, fmt.Errorf("malformed end date '%s'", financial.EndDate)
+  }
+  now := utils.NotTimeUTC()
+
+  tickerFinancial := &domain.TickerFinancial{
+    FinancialId:  fmt.Sprintf("%s-%s-%d-%s", tickerId, financial.Timeframe, fiscalYear, financial.FiscalPeriod),
+    TickerId:     tickerId,
+    Timeframe:    financial.Timeframe,
+    FiscalPeriod: financial.FiscalPeriod,
+    FiscalYear:   fiscalYear,
+    StartDate:    startDate,
+    EndDate:      endDate,
+    FilingDate:   parseOptionalDate(financial.FilingDate),
+    CreatedAt:    now,
+    UpdatedAt:    now,
+  }
+  if financial.Financials == nil || financial.Financials.IncomeStatement == nil {
+    return tickerFinancial, nil
+  }
+  income := financial.Financials.IncomeStatement
+
+  tickerFinancial.Revenue = financialValue(income.Revenues)
+  tickerFinancial.NetIncome = financialValue(income.NetIncomeLoss)
+  tickerFinancial.BasicEps = financialValue(income.BasicEarningsPerShare)
+  tickerFinancial.DilutedEps = financialValue(income.DilutedEarningsPerShare)
+  // statements report weighted average shares outstanding for the period
+  tickerFinancial.SharesOutstanding = financialValue(income.BasicAverageShares)
+
+  return tickerFinancial, nil
+}
+
+func financialValue(value *provider.FinancialValue) *float64 {
+  if value == nil {
+    return nil
+  }
+  return &value.Value
+}
@@ -0,0 +1,332 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "main/internal/provider"
+  "main/internal/storage"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/calendar"
+  datafetcher "github.com/UshakovN/stock-predictor-service/contract/data-fetcher"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+// dateRange is the range of consecutive missing trading days
+type dateRange struct {
+  from time.Time
+  to   time.Time
+  days []time.Time
+}
+
+// ContinuouslyFillGaps periodically scan stored stocks for missing trading days
+// and request aggregates only for the found ranges
+func (f *fetcher) ContinuouslyFillGaps() {
+  if f.gapsCheckInterval <= 0 {
+    log.Infof("gaps check interval not specified. gaps filling disabled")
+    return
+  }
+  const (
+    startTimer = 0
+    timeFormat = "2006-01-02 15:04:05"
+  )
+  timer := time.NewTimer(startTimer)
+  defer timer.Stop()
+
+  for {
+    select {
+    case <-f.ctx.Done():
+      return
+
+    case timerTime := <-timer.C:
+      log.Infof("start scheduled gaps filling: %s", timerTime.UTC().Format(timeFormat))
+
+      if err := f.fillGaps(); err != nil {
+        log.Errorf("gaps filling failed: %v", err)
+      } else {
+        log.Infof("scheduled gaps filling finished: %s", utils.NotTimeUTC().Format(timeFormat))
+      }
+      timer.Reset(f.gapsCheckInterval)
+    }
+  }
+}
+
+func (f *fetcher) GetStockGaps(tickerId, status string) ([]*domain.StockGap, error) {
+  gaps, err := f.storage.GetStockGaps(&storage.GetStockGapsOption{
+    TickerId: tickerId,
+    Status:   status,
+  })
+  if err != nil {
+    return nil, fmt.Errorf("cannot get stock gaps from storage: %v", err)
+  }
+  return gaps, nil
+}
+
+func (f *fetcher) fillGaps() error {
+  tickers, err := f.storage.GetTickers()
+  if err != nil {
+    return fmt.Errorf("cannot get tickers from storage: %v", err)
+  }
+  tickerIds, err := f.universeTickerIds(tickers)
+  if err != nil {
+    return fmt.Errorf("cannot select tickers of the universe: %v", err)
+  }
+  report := f.processTickers(tickerIds, f.fillTickerGaps)
+  logTickersReport("gaps", report)
+
+  if report.allFailed() {
+    return fmt.Errorf("gaps filling failed for all tickers")
+  }
+  return nil
+}
+
+func (f *fetcher) fillTickerGaps(tickerId string) error {
+  adjuster, err := f.loadSplitAdjuster(tickerId)
+  if err != nil {
+    return fmt.Errorf("cannot load splits: %v", err)
+  }
+  for _, granularity := range f.granularities {
+    // trading days calendar is applicable only for daily bars
+    if !granularity.isDefault() {
+      continue
+    }
+    if err := f.fillTickerGranularityGaps(tickerId, granularity, adjuster); err != nil {
+      return fmt.Errorf("cannot fill gaps with granularity '%s': %v", granularity, err)
+    }
+  }
+  return nil
+}
+
+func (f *fetcher) fillTickerGranularityGaps(tickerId string, granularity *Granularity, adjuster *splitAdjuster) error {
+  dates, err := f.storage.GetStockDates(tickerId, granularity.Timespan, granularity.Multiplier)
+  if err != nil {
+    return fmt.Errorf("cannot get stock dates from storage: %v", err)
+  }
+  gapRanges := detectGaps(dates)
+
+  storedGaps, err := f.storage.GetStockGaps(&storage.GetStockGapsOption{
+    TickerId: tickerId,
+  })
+  if err != nil {
+    return fmt.Errorf("cannot get stock gaps from storage: %v", err)
+  }
+  // id of the gap is derived from its range, so range narrowed by the partial filling has new id
+  if err = f.storage.CloseStockGaps(staleGaps(storedGaps, tickerId, granularity, gapRanges)); err != nil {
+    return fmt.Errorf("cannot close stale stock gaps in storage: %v", err)
+  }
+  if len(gapRanges) == 0 {
+    return nil
+  }
+  skipGaps := make(map[string]struct{}, len(storedGaps))
+
+  for _, gap := range storedGaps {
+    if gap.Status == datafetcher.GapStatusUnfillable {
+      skipGaps[gap.GapId] = struct{}{}
+    }
+  }
+  log.Infof("found %d gaps in stocks with granularity '%s' for ticker '%s'",
+    len(gapRanges), granularity, tickerId)
+
+  for _, gapRange := range gapRanges {
+    gapId := createGapId(tickerId, granularity, gapRange)
+
+    if _, ok := skipGaps[gapId]; ok {
+      continue
+    }
+    filledDays, err := f.fillGapRange(tickerId, granularity, gapRange, adjuster)
+    if err != nil {
+      return fmt.Errorf("cannot fill gap '%s': %v", gapId, err)
+    }
+    if err = f.storage.PutStockGap(createStockGap(gapId, tickerId, granularity, gapRange, filledDays)); err != nil {
+      return fmt.Errorf("cannot put stock gap to storage: %v", err)
+    }
+  }
+  return nil
+}
+
+// fillGapRange fetch aggregates for gap range and return count of filled missing days
+func (f *fetcher) fillGapRange(
+  tickerId string,
+  granularity *Granularity,
+  gapRange *dateRange,
+  adjuster *splitAdjuster,
+) (int, error) {
+  missingDays := make(map[time.Time]struct{}, len(gapRange.days))
+
+  for _, day := range gapRange.days {
+    missingDays[day] = struct{}{}
+  }
+  aggregatesOption := &provider.AggregatesOption{
+    TickerId:   tickerId,
+    Multiplier: granularity.Multiplier,
+    Timespan:   granularity.Timespan,
+    From:       gapRange.from,
+    To:         gapRange.to,
+  }
+  filledDays := map[time.Time]struct{}{}
+
+  series, err := f.newSeries(tickerId, granularity, gapRange.from)
+  if err != nil {
+    return 0, fmt.Errorf("cannot start stocks validation: %v", err)
+  }
+  for {
+    aggregatesPage, err := f.provider.GetAggregates(aggregatesOption)
+    if err != nil {
+      return 0, fmt.Errorf("cannot get aggregates: %v", err)
+    }
+    stocks := make([]*domain.Stock, 0, len(aggregatesPage.Bars))
+
+    for _, bar := range aggregatesPage.Bars {
+      stock, err := createStock(tickerId, granularity, bar)
+      if err != nil {
+        return 0, fmt.Errorf("cannot create stock: %v", err)
+      }
+      if stock == nil {
+        continue
+      }
+      adjuster.adjust(stock)
+      stocks = append(stocks, stock)
+    }
+    if stocks, err = f.validateStocks(series, stocks); err != nil {
+      return 0, fmt.Errorf("cannot validate stocks: %v", err)
+    }
+    for _, stock := range stocks {
+      day := truncateDate(stock.StockedAt)
+      if _, ok := missingDays[day]; ok {
+        filledDays[day] = struct{}{}
+      }
+    }
+    if _, err = f.storage.PutStocks(stocks); err != nil {
+      return 0, fmt.Errorf("cannot put stocks to storage: %v", err)
+    }
+    if aggregatesPage.NextCursor == "" {
+      break
+    }
+    aggregatesOption.Cursor = aggregatesPage.NextCursor
+  }
+  return len(filledDays), nil
+}
+
+// detectGaps find ranges of missing trading days between the first and the last stored dates
+func detectGaps(dates []time.Time) []*dateRange {
+  const minDatesCount = 2
+
+  if len(dates) < minDatesCount {
+    return nil
+  }
+  storedDays := make(map[time.Time]struct{}, len(dates))
+
+  for _, date := range dates {
+    storedDays[truncateDate(date)] = struct{}{}
+  }
+  var (
+    gaps    []*dateRange
+    current *dateRange
+  )
+  first := truncateDate(dates[0])
+  last := truncateDate(dates[len(dates)-1])
+
+  for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+    if !calendar.IsTradingDay(day) {
+      continue
+    }
+    if _, ok := storedDays[day]; ok {
+      current = nil
+      continue
+    }
+    if current == nil {
+      current = &dateRange{
+        from: day,
+      }
+      gaps = append(gaps, current)
+    }
+    current.to = day
+    current.days = append(current.days, day)
+  }
+  return gaps
+}
+
+// staleGaps return not filled stored gaps of the granularity which are not detected anymore.
+// gap overlapped by the detected one is replaced by it, otherwise all its days are stored
+func staleGaps(
+  storedGaps []*domain.StockGap,
+  tickerId string,
+  granularity *Granularity,
+  gapRanges []*dateRange,
+) *storage.CloseStockGapsOption {
+  detectedIds := make(map[string]struct{}, len(gapRanges))
+
+  for _, gapRange := range gapRanges {
+    detectedIds[createGapId(tickerId, granularity, gapRange)] = struct{}{}
+  }
+  option := &storage.CloseStockGapsOption{}
+
+  for _, gap := range storedGaps {
+    if gap.Timespan != granularity.Timespan || gap.Multiplier != granularity.Multiplier {
+      continue
+    }
+    if gap.Status == datafetcher.GapStatusFilled {
+      continue
+    }
+    if _, ok := detectedIds[gap.GapId]; ok {
+      continue
+    }
+    replaced := false
+
+    for _, gapRange := range gapRanges {
+      if !gap.DateFrom.After(gapRange.to) && !gap.DateTo.Before(gapRange.from) {
+        replaced = true
+        break
+      }
+    }
+    if replaced {
+      option.ReplacedIds = append(option.ReplacedIds, gap.GapId)
+    } else {
+      option.FilledIds = append(option.FilledIds, gap.GapId)
+    }
+  }
+  return option
+}
+
+func createGapId(tickerId string, granularity *Granularity, gapRange *dateRange) string {
+  const (
+    sepId      = "-"
+    dateFormat = "20060102"
+  )
+  return fmt.Sprint(tickerId, sepId, granularity, sepId,
+    gapRange.from.Format(dateFormat), sepId, gapRange.to.Format(dateFormat))
+}
+
+func createStockGap(gapId, tickerId string, granularity *Granularity, gapRange *dateRange, filledDays int) *domain.StockGap {
+  missingDays := len(gapRange.days)
+
+  status := datafetcher.GapStatusPartiallyFilled
+  if filledDays == 0 {
+    status = datafetcher.GapStatusUnfillable
+  } else
+  if filledDays == missingDays {
+    status = datafetcher.GapStatusFilled
+  }
+  now := utils.NotTimeUTC()
+
+  return &domain.StockGap{
+    GapId:       gapId,
+    TickerId:    tickerId,
+    Timespan:    granularity.Timespan,
+    Multiplier:  granularity.Multiplier,
+    DateFrom:    gapRange.from,
+    DateTo:      gapRange.to,
+    MissingDays: missingDays,
+    FilledDays:  filledDays,
+    Status:      status,
+    Attempts:    1,
+    CreatedAt:   now,
+    UpdatedAt:   now,
+  }
+}
+
+func truncateDate(t time.Time) time.Time {
+  t = t.UTC()
+  return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,189 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "testing"
+  "time"
+
+  datafetcher "github.com/UshakovN/stock-predictor-service/contract/data-fetcher"
+)
+
+const testDateLayout = "2006-01-02"
+
+func parseTestDates(t *testing.T, values ...string) []time.Time {
+  t.Helper()
+
+  dates := make([]time.Time, 0, len(values))
+
+  for _, value := range values {
+    layout := testDateLayout
+    if len(value) > len(testDateLayout) {
+      layout = time.RFC3339
+    }
+    date, err := time.Parse(layout, value)
+    if err != nil {
+      t.Fatalf("cannot parse date '%s': %v", value, err)
+    }
+    dates = append(dates, date)
+  }
+  return dates
+}
+
+func formatGaps(gaps []*dateRange) string {
+  formatted := make([]string, 0, len(gaps))
+
+  for _, gap := range gaps {
+    days := make([]string, 0, len(gap.days))
+
+    for _, day := range gap.days {
+      days = append(days, day.Format(testDateLayout))
+    }
+    formatted = append(formatted, fmt.Sprintf("%s..%s%v",
+      gap.from.Format(testDateLayout), gap.to.Format(testDateLayout), days))
+  }
+  return fmt.Sprint(formatted)
+}
+
+func TestDetectGaps(t *testing.T) {
+  testCases := []struct {
+    name  string
+    dates []string
+    gaps  []string
+  }{
+    {
+      name:  "single date",
+      dates: []string{"2023-03-06"},
+    },
+    {
+      name:  "weekend is not gap",
+      dates: []string{"2023-03-02", "2023-03-03", "2023-03-06", "2023-03-07"},
+    },
+    {
+      name:  "missing day",
+      dates: []string{"2023-03-06", "2023-03-08"},
+      gaps:  []string{"2023-03-07..2023-03-07[2023-03-07]"},
+    },
+    {
+      name:  "gap over weekend",
+      dates: []string{"2023-03-02", "2023-03-07"},
+      gaps:  []string{"2023-03-03..2023-03-06[2023-03-03 2023-03-06]"},
+    },
+    {
+      name:  "good friday is not gap",
+      dates: []string{"2023-04-06", "2023-04-10"},
+    },
+    {
+      name:  "observed new year is not gap",
+      dates: []string{"2022-12-30", "2023-01-03"},
+    },
+    {
+      name:  "holiday do not split gap",
+      dates: []string{"2023-06-15", "2023-06-21"},
+      gaps:  []string{"2023-06-16..2023-06-20[2023-06-16 2023-06-20]"},
+    },
+    {
+      name:  "days before the first stored date are not gap",
+      dates: []string{"2023-03-08", "2023-03-09"},
+    },
+    {
+      name:  "gap after the first stored date",
+      dates: []string{"2023-03-06", "2023-03-09", "2023-03-10"},
+      gaps:  []string{"2023-03-07..2023-03-08[2023-03-07 2023-03-08]"},
+    },
+    {
+      name:  "separate gaps",
+      dates: []string{"2023-03-06", "2023-03-08", "2023-03-10"},
+      gaps: []string{
+        "2023-03-07..2023-03-07[2023-03-07]",
+        "2023-03-09..2023-03-09[2023-03-09]",
+      },
+    },
+    {
+      name:  "time of the dates is truncated",
+      dates: []string{"2023-03-06T14:30:00Z", "2023-03-07T20:00:00Z", "2023-03-09T14:30:00Z"},
+      gaps:  []string{"2023-03-08..2023-03-08[2023-03-08]"},
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      gaps := detectGaps(parseTestDates(t, testCase.dates...))
+
+      if formatted := formatGaps(gaps); formatted != fmt.Sprint(testCase.gaps) {
+        t.Errorf("expected gaps %v, got %s", testCase.gaps, formatted)
+      }
+    })
+  }
+}
+
+func TestStaleGaps(t *testing.T) {
+  const tickerId = "AAPL"
+
+  dates := parseTestDates(t, "2023-03-06", "2023-03-09", "2023-03-10")
+  gapRanges := detectGaps(dates)
+
+  storedGap := func(gapId, status, from, to string) *domain.StockGap {
+    borders := parseTestDates(t, from, to)
+
+    return &domain.StockGap{
+      GapId:      gapId,
+      TickerId:   tickerId,
+      Timespan:   defaultGranularity.Timespan,
+      Multiplier: defaultGranularity.Multiplier,
+      DateFrom:   borders[0],
+      DateTo:     borders[1],
+      Status:     status,
+    }
+  }
+  detected := storedGap(createGapId(tickerId, defaultGranularity, gapRanges[0]),
+    datafetcher.GapStatusPartiallyFilled, "2023-03-07", "2023-03-08")
+
+  // range was narrowed from 03-07..03-10 by the partial filling
+  narrowed := storedGap("narrowed", datafetcher.GapStatusPartiallyFilled, "2023-03-07", "2023-03-10")
+  filledLater := storedGap("filled-later", datafetcher.GapStatusPartiallyFilled, "2023-02-01", "2023-02-03")
+  unfillable := storedGap("unfillable", datafetcher.GapStatusUnfillable, "2023-02-13", "2023-02-14")
+  filled := storedGap("filled", datafetcher.GapStatusFilled, "2023-02-20", "2023-02-21")
+
+  otherGranularity := storedGap("other-granularity", datafetcher.GapStatusPartiallyFilled, "2023-03-07", "2023-03-08")
+  otherGranularity.Multiplier = 2
+
+  option := staleGaps(
+    []*domain.StockGap{detected, narrowed, filledLater, unfillable, filled, otherGranularity},
+    tickerId,
+    defaultGranularity,
+    gapRanges,
+  )
+  if fmt.Sprint(option.ReplacedIds) != "[narrowed]" {
+    t.Errorf("expected replaced gaps [narrowed], got %v", option.ReplacedIds)
+  }
+  if fmt.Sprint(option.FilledIds) != "[filled-later unfillable]" {
+    t.Errorf("expected filled gaps [filled-later unfillable], got %v", option.FilledIds)
+  }
+  option = staleGaps([]*domain.StockGap{narrowed}, tickerId, defaultGranularity, nil)
+
+  if fmt.Sprint(option.FilledIds) != "[narrowed]" || len(option.ReplacedIds) != 0 {
+    t.Errorf("expected gap filled when no gaps detected, got %+v", option)
+  }
+}
+
+func TestCreateStockGap(t *testing.T) {
+  gapRange := detectGaps(parseTestDates(t, "2023-03-06", "2023-03-09"))[0]
+
+  for filledDays, status := range map[int]string{
+    0: datafetcher.GapStatusUnfillable,
+    1: datafetcher.GapStatusPartiallyFilled,
+    2: datafetcher.GapStatusFilled,
+  } {
+    gap := createStockGap("gap", "AAPL", defaultGranularity, gapRange, filledDays)
+
+    if gap.Status != status {
+      t.Errorf("expected status '%s' of %d filled days, got '%s'", status, filledDays, gap.Status)
+    }
+    if gap.MissingDays != 2 {
+      t.Errorf("expected 2 missing days, got %d", gap.MissingDays)
+    }
+  }
+  if gapId := createGapId("AAPL", defaultGranularity, gapRange); gapId != "AAPL-1day-20230307-20230308" {
+    t.Errorf("unexpected gap id '%s'", gapId)
+  }
+}
@@ -0,0 +1,108 @@
+package fetcher
+
+import (
+  "fmt"
+  "sync"
+
+  log "github.com/sirupsen/logrus"
+)
+
+type tickerTask func(tickerId string) error
+
+// tickersReport collect results of the tickers processed by the workers pool
+type tickersReport struct {
+  mu        sync.Mutex
+  processed int
+  failed    map[string]error
+}
+
+func newTickersReport() *tickersReport {
+  return &tickersReport{
+    failed: map[string]error{},
+  }
+}
+
+func (r *tickersReport) add(tickerId string, err error) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  r.processed++
+  if err != nil {
+    r.failed[tickerId] = err
+  }
+}
+
+func (r *tickersReport) merge(other *tickersReport) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  r.processed += other.processed
+  for tickerId, err := range other.failed {
+    r.failed[tickerId] = err
+  }
+}
+
+// allFailed report that no one ticker was processed successfully,
+// in this case the problem is not related with specific ticker
+func (r *tickersReport) allFailed() bool {
+  return r.processed > 0 && len(r.failed) == r.processed
+}
+
+func (r *tickersReport) String() string {
+  return fmt.Sprintf("processed %d tickers, failed %d tickers", r.processed, len(r.failed))
+}
+
+// processTickers run task for each ticker in bounded pool of workers.
+// task errors are isolated per ticker and do not stop the other tickers.
+// all workers share the same provider, so they share its rate limiter
+func (f *fetcher) processTickers(tickerIds []string, task tickerTask) *tickersReport {
+  report := newTickersReport()
+
+  tickersCh := make(chan string)
+  wg := sync.WaitGroup{}
+
+  for workerIdx := 0; workerIdx < f.workersCount; workerIdx++ {
+    wg.Add(1)
+
+    go func() {
+      defer wg.Done()
+
+      for tickerId := range tickersCh {
+        err := runTickerTask(tickerId, task)
+        if err != nil {
+          log.Errorf("ticker '%s' processing failed: %v", tickerId, err)
+          f.recordError(tickerId, err)
+        }
+        report.add(tickerId, err)
+      }
+    }()
+  }
+
+dispatch:
+  for _, tickerId := range tickerIds {
+    // paused processing stop dispatch until resume
+    if !f.waitResumed() {
+      log.Warnf("tickers processing interrupted while paused: %v", f.ctx.Err())
+      break dispatch
+    }
+    select {
+    case <-f.ctx.Done():
+      log.Warnf("tickers processing interrupted: %v", f.ctx.Err())
+      break dispatch
+    case tickersCh <- tickerId:
+    }
+  }
+  close(tickersCh)
+  wg.Wait()
+
+  return report
+}
+
+func runTickerTask(tickerId string, task tickerTask) (err error) {
+  defer func() {
+    if r := recover(); r != nil {
+      err = fmt.Errorf("recovered from panic: %v", r)
+    }
+  }()
+  return task(tickerId)
+}
@@ -0,0 +1,165 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "sort"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/utils"
+)
+
+const (
+  priorityTierHigh = "high"
+  priorityTierLow  = "low"
+)
+
+type SchedulerConfig struct {
+  HighPriorityMinutes int `yaml:"high_priority_minutes"`
+  LowPriorityHours    int `yaml:"low_priority_hours"`
+  // active subscriptions count required for the high priority tier
+  MinSubscribers int `yaml:"min_subscribers"`
+}
+
+// scheduler decide when stocks of stored tickers are refreshed.
+// subscribed tickers are refreshed on short interval, the long tail on long one
+type scheduler struct {
+  highInterval   time.Duration
+  lowInterval    time.Duration
+  minSubscribers int
+}
+
+func newScheduler(config *SchedulerConfig) (*scheduler, error) {
+  if config == nil {
+    config = &SchedulerConfig{}
+  }
+  if config.HighPriorityMinutes < 0 || config.LowPriorityHours < 0 || config.MinSubscribers < 0 {
+    return nil, fmt.Errorf("scheduler intervals and min subscribers must not be negative")
+  }
+  s := &scheduler{
+    highInterval:   time.Duration(config.HighPriorityMinutes) * time.Minute,
+    lowInterval:    time.Duration(config.LowPriorityHours) * time.Hour,
+    minSubscribers: config.MinSubscribers,
+  }
+  if s.highInterval == 0 {
+    s.highInterval = defaultHighPriorityInterval
+  }
+  if s.lowInterval == 0 {
+    s.lowInterval = defaultLowPriorityInterval
+  }
+  if s.minSubscribers == 0 {
+    s.minSubscribers = defaultMinSubscribers
+  }
+  if s.lowInterval < s.highInterval {
+    return nil, fmt.Errorf("low priority interval must not be less than high priority one")
+  }
+  return s, nil
+}
+
+// priority grow with subscriptions and with days passed since the last stored bar.
+// both terms saturate, so many stale days not outweigh subscribed ticker and vice versa
+func priority(subscribers int, lastBarAt *time.Time, now time.Time) float64 {
+  value := prioritySubscribersWeight * saturate(float64(subscribers), prioritySubscribersScale)
+
+  if lastBarAt != nil && !lastBarAt.IsZero() {
+    staleDays := now.Sub(*lastBarAt).Hours() / 24
+    value += priorityStalenessWeight * saturate(staleDays, priorityStalenessDays)
+  }
+  return value
+}
+
+// saturate map not negative value to [0, 1), the half is reached on scale
+func saturate(value, scale float64) float64 {
+  if value <= 0 {
+    return 0
+  }
+  return value / (value + scale)
+}
+
+func (s *scheduler) planTicker(
+  tickerId string,
+  subscribers int,
+  activity *domain.TickerActivity,
+  now time.Time,
+) *domain.ScheduledTicker {
+  planned := &domain.ScheduledTicker{
+    TickerId:    tickerId,
+    Subscribers: subscribers,
+    Tier:        priorityTierLow,
+  }
+  interval := s.lowInterval
+
+  if subscribers >= s.minSubscribers {
+    planned.Tier = priorityTierHigh
+    interval = s.highInterval
+  }
+  if activity != nil {
+    planned.LastBarAt = activity.LastBarAt
+    planned.RefreshedAt = activity.RefreshedAt
+  }
+  planned.Priority = priority(subscribers, planned.LastBarAt, now)
+
+  // never refreshed ticker is due immediately
+  planned.NextRefreshAt = now
+  if planned.RefreshedAt != nil {
+    planned.NextRefreshAt = planned.RefreshedAt.Add(interval)
+  }
+  planned.Due = !planned.NextRefreshAt.After(now)
+
+  return planned
+}
+
+// buildSchedulePlan return plan for the tickers ordered by tier and priority
+func (f *fetcher) buildSchedulePlan(tickerIds []string) ([]*domain.ScheduledTicker, error) {
+  subscriptions, err := f.storage.GetSubscriptionCounts()
+  if err != nil {
+    return nil, fmt.Errorf("cannot get subscription counts from storage: %v", err)
+  }
+  activities, err := f.storage.GetTickersActivity()
+  if err != nil {
+    return nil, fmt.Errorf("cannot get tickers activity from storage: %v", err)
+  }
+  now := utils.NotTimeUTC()
+  plan := make([]*domain.ScheduledTicker, 0, len(tickerIds))
+
+  for _, tickerId := range tickerIds {
+    plan = append(plan, f.scheduler.planTicker(tickerId, subscriptions[tickerId], activities[tickerId], now))
+  }
+  sortSchedulePlan(plan)
+
+  return plan, nil
+}
+
+// sortSchedulePlan order high tier tickers first, then by priority descending
+func sortSchedulePlan(plan []*domain.ScheduledTicker) {
+  sort.SliceStable(plan, func(i, j int) bool {
+    if plan[i].Tier != plan[j].Tier {
+      return plan[i].Tier == priorityTierHigh
+    }
+    return plan[i].Priority > plan[j].Priority
+  })
+}
+
+// dueTickerIds return tickers of the plan which must be refreshed now in the plan order
+func dueTickerIds(plan []*domain.ScheduledTicker) []string {
+  tickerIds := make([]string, 0, len(plan))
+
+  for _, planned := range plan {
+    if planned.Due {
+      tickerIds = append(tickerIds, planned.TickerId)
+    }
+  }
+  return tickerIds
+}
+
+func (f *fetcher) GetSchedulePlan() ([]*domain.ScheduledTicker, error) {
+  tickers, err := f.storage.GetTickers()
+  if err != nil {
+    return nil, fmt.Errorf("cannot get tickers from storage: %v", err)
+  }
+  tickerIds, err := f.universeTickerIds(tickers)
+  if err != nil {
+    return nil, fmt.Errorf("cannot select tickers of the universe: %v", err)
+  }
+  return f.buildSchedulePlan(tickerIds)
+}
@@ -0,0 +1,177 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "testing"
+  "time"
+)
+
+func newTestScheduler(t *testing.T) *scheduler {
+  t.Helper()
+
+  s, err := newScheduler(&SchedulerConfig{
+    HighPriorityMinutes: 60,
+    LowPriorityHours:    24,
+    MinSubscribers:      2,
+  })
+  if err != nil {
+    t.Fatalf("cannot create scheduler: %v", err)
+  }
+  return s
+}
+
+func TestPlanTicker(t *testing.T) {
+  now := time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+
+  ago := func(interval time.Duration) *time.Time {
+    at := now.Add(-interval)
+    return &at
+  }
+  testCases := []struct {
+    name          string
+    subscribers   int
+    activity      *domain.TickerActivity
+    tier          string
+    due           bool
+    nextRefreshAt time.Time
+  }{
+    {
+      name:          "never refreshed ticker is due",
+      tier:          priorityTierLow,
+      due:           true,
+      nextRefreshAt: now,
+    },
+    {
+      name:          "subscribed ticker is refreshed on high interval",
+      subscribers:   2,
+      activity:      &domain.TickerActivity{RefreshedAt: ago(30 * time.Minute)},
+      tier:          priorityTierHigh,
+      nextRefreshAt: now.Add(30 * time.Minute),
+    },
+    {
+      name:          "subscribed ticker is due after high interval",
+      subscribers:   5,
+      activity:      &domain.TickerActivity{RefreshedAt: ago(time.Hour)},
+      tier:          priorityTierHigh,
+      due:           true,
+      nextRefreshAt: now,
+    },
+    {
+      name:          "less subscriptions than required",
+      subscribers:   1,
+      activity:      &domain.TickerActivity{RefreshedAt: ago(2 * time.Hour)},
+      tier:          priorityTierLow,
+      nextRefreshAt: now.Add(22 * time.Hour),
+    },
+    {
+      name:          "long tail ticker is due after low interval",
+      activity:      &domain.TickerActivity{RefreshedAt: ago(25 * time.Hour), LastBarAt: ago(72 * time.Hour)},
+      tier:          priorityTierLow,
+      due:           true,
+      nextRefreshAt: now.Add(-time.Hour),
+    },
+  }
+  s := newTestScheduler(t)
+
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      planned := s.planTicker("AAPL", testCase.subscribers, testCase.activity, now)
+
+      if planned.TickerId != "AAPL" || planned.Subscribers != testCase.subscribers {
+        t.Errorf("unexpected planned ticker %+v", planned)
+      }
+      if planned.Tier != testCase.tier {
+        t.Errorf("expected tier '%s', got '%s'", testCase.tier, planned.Tier)
+      }
+      if planned.Due != testCase.due {
+        t.Errorf("expected due %t, got %t", testCase.due, planned.Due)
+      }
+      if !planned.NextRefreshAt.Equal(testCase.nextRefreshAt) {
+        t.Errorf("expected next refresh at %v, got %v", testCase.nextRefreshAt, planned.NextRefreshAt)
+      }
+    })
+  }
+}
+
+func TestPriority(t *testing.T) {
+  now := time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+
+  ago := func(days int) *time.Time {
+    at := now.AddDate(0, 0, -days)
+    return &at
+  }
+  testCases := []struct {
+    name   string
+    higher func() float64
+    lower  func() float64
+  }{
+    {
+      name:   "more subscriptions",
+      higher: func() float64 { return priority(3, ago(1), now) },
+      lower:  func() float64 { return priority(2, ago(1), now) },
+    },
+    {
+      name:   "more stale days",
+      higher: func() float64 { return priority(2, ago(3), now) },
+      lower:  func() float64 { return priority(2, ago(1), now) },
+    },
+    {
+      name:   "stale year not outweigh popular ticker",
+      higher: func() float64 { return priority(100, ago(0), now) },
+      lower:  func() float64 { return priority(1, ago(365), now) },
+    },
+    {
+      name:   "stale month outweigh one more subscription",
+      higher: func() float64 { return priority(2, ago(30), now) },
+      lower:  func() float64 { return priority(3, ago(0), now) },
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      if higher, lower := testCase.higher(), testCase.lower(); higher <= lower {
+        t.Errorf("expected priority %f greater than %f", higher, lower)
+      }
+    })
+  }
+  if value := priority(0, nil, now); value != 0 {
+    t.Errorf("expected zero priority without subscriptions and bars, got %f", value)
+  }
+  if value := priority(1_000_000, ago(1_000_000), now); value >= prioritySubscribersWeight+priorityStalenessWeight {
+    t.Errorf("expected priority less than sum of the weights, got %f", value)
+  }
+}
+
+func TestSortSchedulePlan(t *testing.T) {
+  now := time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+
+  ago := func(days int) *domain.TickerActivity {
+    at := now.AddDate(0, 0, -days)
+    return &domain.TickerActivity{LastBarAt: &at}
+  }
+  s := newTestScheduler(t)
+
+  plan := []*domain.ScheduledTicker{
+    s.planTicker("STALE", 0, ago(365), now),
+    s.planTicker("FRESH", 0, ago(1), now),
+    s.planTicker("POPULAR", 100, ago(0), now),
+    s.planTicker("SUBSCRIBED", 2, ago(1), now),
+    s.planTicker("STALE-SUBSCRIBED", 2, ago(30), now),
+    s.planTicker("NEW", 0, nil, now),
+  }
+  sortSchedulePlan(plan)
+
+  tickerIds := make([]string, 0, len(plan))
+
+  for _, planned := range plan {
+    tickerIds = append(tickerIds, planned.TickerId)
+  }
+  expected := "[POPULAR STALE-SUBSCRIBED SUBSCRIBED STALE FRESH NEW]"
+
+  if fmt.Sprint(tickerIds) != expected {
+    t.Errorf("expected plan order %s, got %v", expected, tickerIds)
+  }
+  if dueIds := dueTickerIds(plan); len(dueIds) != len(plan) {
+    t.Errorf("expected never refreshed tickers are due, got %v", dueIds)
+  }
+}
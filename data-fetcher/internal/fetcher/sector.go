@@ -0,0 +1,102 @@
+package fetcher
+
+import "strconv"
+
+// sectors of the taxonomy derived from SIC codes, close to GICS sectors
+const (
+  sectorTechnology            = "technology"
+  sectorHealthCare            = "health_care"
+  sectorFinancials            = "financials"
+  sectorRealEstate            = "real_estate"
+  sectorEnergy                = "energy"
+  sectorUtilities             = "utilities"
+  sectorCommunicationServices = "communication_services"
+  sectorConsumerDiscretionary = "consumer_discretionary"
+  sectorConsumerStaples       = "consumer_staples"
+  sectorMaterials             = "materials"
+  sectorIndustrials           = "industrials"
+  sectorUnclassified          = "unclassified"
+)
+
+type sicRange struct {
+  from   int
+  to     int
+  sector string
+}
+
+// sicSectors in order of matching, narrow ranges precede the wide ones they are part of
+var sicSectors = []*sicRange{
+  // technology
+  {3570, 3579, sectorTechnology}, // computer and office equipment
+  {3661, 3669, sectorTechnology}, // communications equipment
+  {3670, 3679, sectorTechnology}, // electronic components and semiconductors
+  {3820, 3829, sectorTechnology}, // measuring instruments
+  {7370, 7379, sectorTechnology}, // software and computer services
+  // health care
+  {2833, 2836, sectorHealthCare}, // pharmaceuticals and biological products
+  {3840, 3851, sectorHealthCare}, // medical instruments and supplies
+  {5047, 5047, sectorHealthCare}, // medical equipment wholesale
+  {5122, 5122, sectorHealthCare}, // drugs wholesale
+  {8000, 8099, sectorHealthCare}, // health services
+  {8731, 8731, sectorHealthCare}, // commercial physical and biological research
+  // real estate before financials
+  {6500, 6553, sectorRealEstate},
+  {6798, 6798, sectorRealEstate}, // real estate investment trusts
+  {6000, 6799, sectorFinancials},
+  // energy
+  {1300, 1399, sectorEnergy}, // oil and gas extraction
+  {2900, 2999, sectorEnergy}, // petroleum refining
+  {4610, 4619, sectorEnergy}, // pipelines
+  {5171, 5172, sectorEnergy}, // petroleum products wholesale
+  // utilities
+  {4900, 4999, sectorUtilities},
+  // communication services
+  {2710, 2799, sectorCommunicationServices}, // publishing and printing
+  {4800, 4899, sectorCommunicationServices}, // communications
+  {7810, 7819, sectorCommunicationServices}, // motion pictures
+  // consumer staples
+  {2000, 2199, sectorConsumerStaples}, // food, beverages and tobacco
+  {2840, 2844, sectorConsumerStaples}, // soap, detergents and cosmetics
+  {5140, 5159, sectorConsumerStaples}, // groceries and farm products wholesale
+  {5400, 5499, sectorConsumerStaples}, // food stores
+  {5912, 5912, sectorConsumerStaples}, // drug stores
+  // consumer discretionary
+  {2300, 2399, sectorConsumerDiscretionary}, // apparel
+  {2510, 2599, sectorConsumerDiscretionary}, // furniture
+  {3630, 3639, sectorConsumerDiscretionary}, // household appliances
+  {3711, 3716, sectorConsumerDiscretionary}, // motor vehicles
+  {3940, 3949, sectorConsumerDiscretionary}, // toys and sporting goods
+  {5200, 5999, sectorConsumerDiscretionary}, // retail trade
+  {7000, 7099, sectorConsumerDiscretionary}, // hotels
+  {7200, 7299, sectorConsumerDiscretionary}, // personal services
+  {7900, 7999, sectorConsumerDiscretionary}, // amusement and recreation
+  {8200, 8299, sectorConsumerDiscretionary}, // educational services
+  // materials
+  {1000, 1299, sectorMaterials}, // metal and coal mining
+  {1400, 1499, sectorMaterials}, // nonmetallic minerals
+  {2400, 2499, sectorMaterials}, // lumber and wood
+  {2600, 2699, sectorMaterials}, // paper
+  {2800, 2899, sectorMaterials}, // chemicals
+  {3200, 3399, sectorMaterials}, // stone, glass and primary metals
+  // industrials
+  {1500, 1799, sectorIndustrials}, // construction
+  {3400, 3999, sectorIndustrials}, // machinery, equipment and other manufacturing
+  {4000, 4799, sectorIndustrials}, // transportation
+  {5000, 5199, sectorIndustrials}, // wholesale trade
+  {7300, 7399, sectorIndustrials}, // business services
+  {8700, 8799, sectorIndustrials}, // engineering and management services
+}
+
+// sectorBySic return sector of the SIC code, unknown and malformed codes are unclassified
+func sectorBySic(sicCode string) string {
+  code, err := strconv.Atoi(sicCode)
+  if err != nil {
+    return sectorUnclassified
+  }
+  for _, sicSector := range sicSectors {
+    if code >= sicSector.from && code <= sicSector.to {
+      return sicSector.sector
+    }
+  }
+  return sectorUnclassified
+}
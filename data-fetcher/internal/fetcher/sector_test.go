@@ -0,0 +1,37 @@
+package fetcher
+
+import "testing"
+
+func TestSectorBySic(t *testing.T) {
+  testCases := []struct {
+    sicCode string
+    sector  string
+  }{
+    // narrow ranges overlapped by the wide ones
+    {"2834", sectorHealthCare},
+    {"2844", sectorConsumerStaples},
+    {"3674", sectorTechnology},
+    {"3841", sectorHealthCare},
+    {"5122", sectorHealthCare},
+    {"6798", sectorRealEstate},
+    {"7372", sectorTechnology},
+    {"8731", sectorHealthCare},
+    // wide ranges
+    {"2851", sectorMaterials},
+    {"3560", sectorIndustrials},
+    {"5110", sectorIndustrials},
+    {"6021", sectorFinancials},
+    {"7389", sectorIndustrials},
+    {"8711", sectorIndustrials},
+    // unknown and malformed codes
+    {"0100", sectorUnclassified},
+    {"9995", sectorUnclassified},
+    {"", sectorUnclassified},
+    {"73A2", sectorUnclassified},
+  }
+  for _, testCase := range testCases {
+    if sector := sectorBySic(testCase.sicCode); sector != testCase.sector {
+      t.Errorf("expected sector '%s' of sic code '%s', got '%s'", testCase.sector, testCase.sicCode, sector)
+    }
+  }
+}
@@ -3,6 +3,7 @@ package fetcher
 import (
   "fmt"
   "main/internal/domain"
+  "sync"
   "time"
 
   "github.com/UshakovN/stock-predictor-service/utils"
@@ -10,19 +11,48 @@ import (
 )
 
 type state struct {
+  // guard fields changed by the control API
+  mu               sync.RWMutex
   finished         bool
   updatedAt        *time.Time
   modeCode         int
   modeTotalHours   int
   modeCurrentHours int
   ticker           *stateRequest
-  tickerDetails    *stateRequest
-  stocks           *stateRequest
 }
 
+// stateRequest hold provider cursor of the last requested page
 type stateRequest struct {
-  requestURL string
-  used       bool
+  mu     sync.Mutex
+  cursor string
+  used   bool
+}
+
+// takeCursor return stored cursor once, so the first request after
+// the state loading continue from the last requested page
+func (r *stateRequest) takeCursor() string {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  if r.used || r.cursor == "" {
+    return ""
+  }
+  r.used = true
+  return r.cursor
+}
+
+func (r *stateRequest) setCursor(cursor string) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  r.cursor = cursor
+}
+
+func (r *stateRequest) getCursor() string {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  return r.cursor
 }
 
 func newFetcherState(modeTotalHours, modeCurrentHours int) *state {
@@ -31,30 +61,55 @@ func newFetcherState(modeTotalHours, modeCurrentHours int) *state {
     modeTotalHours:   modeTotalHours,
     modeCurrentHours: modeCurrentHours,
     ticker:           &stateRequest{},
-    tickerDetails:    &stateRequest{},
-    stocks:           &stateRequest{},
   }
 }
 
 func (s *state) SetFinished() {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
   s.finished = true
 }
 
 func (s *state) ResetFinished() {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
   s.finished = false
 }
 
 func (s *state) SetUpdatedTime(t time.Time) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
   s.updatedAt = &t
 }
 
+func (s *state) GetModeCode() int {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+
+  return s.modeCode
+}
+
+// GetStatus return consistent snapshot of the fields changed concurrently
+func (s *state) GetStatus() (finished bool, updatedAt *time.Time, modeCode int) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+
+  return s.finished, s.updatedAt, s.modeCode
+}
+
 func (s *state) SetModeCode(mode int) {
   if mode != fetcherModeTotal && mode != fetcherModeCurrent {
     log.Warnf("invalid fetcher mode code: %d. mode code do not set. possible: %d - total, %d - current",
       mode, fetcherModeTotal, fetcherModeCurrent)
     return
   }
+  s.mu.Lock()
   s.modeCode = mode
+  s.mu.Unlock()
+
   log.Infof("current fetcher mode: %d. possible: %d - total, %d - current",
     mode, fetcherModeTotal, fetcherModeCurrent)
 }
@@ -68,15 +123,18 @@ func (f *fetcher) SetTickerId(tickerId string) {
 }
 
 func (f *fetcher) hasRecentlyFetched() bool {
-  if !f.state.finished || f.state.updatedAt == nil {
+  // state is changed by the control API concurrently
+  finished, updatedAt, _ := f.state.GetStatus()
+
+  if !finished || updatedAt == nil {
     return false
   }
-  updatedAt := *f.state.updatedAt
   thresholdTime := updatedAt.Add(recentlyThresholdInterval)
   return thresholdTime.After(time.Now())
 }
 
-func (f *fetcher) ContinuouslyFetch() {
+// ContinuouslyFetch fetch until the fetcher context is done and return error when retries exhausted
+func (f *fetcher) ContinuouslyFetch() error {
   if f.tickerId == "" {
     // if not specified ticker id
     if err := f.loadFetcherState(); err != nil {
@@ -88,19 +146,31 @@ func (f *fetcher) ContinuouslyFetch() {
   tryLeft := fetcherRetryCount
   // fetch with retries
   for tryLeft >= 0 {
+    if !f.waitResumed() {
+      log.Warnf("fetching stopped: %v", f.ctx.Err())
+      return nil
+    }
     if f.hasRecentlyFetched() {
+      // the shortest refresh interval, not due tickers are skipped by the schedule plan
       log.Printf("recently fetched. wait %v before the next fetch",
-        recentlyFetchedSleepInterval)
+        f.scheduler.highInterval)
 
-      time.Sleep(recentlyFetchedSleepInterval)
+      if !f.sleep(f.scheduler.highInterval) {
+        log.Warnf("fetching stopped: %v", f.ctx.Err())
+        return nil
+      }
       f.state.ResetFinished() // reset finished field
       continue
     }
     if err := f.FetchInfo(); err != nil {
+      f.recordError(f.tickerId, err)
       log.Errorf("fetching error: %v. wait %v before the next fetch",
         err, encounteredErrorSleepInterval)
 
-      time.Sleep(encounteredErrorSleepInterval)
+      if !f.sleep(encounteredErrorSleepInterval) {
+        log.Warnf("fetching stopped: %v", f.ctx.Err())
+        return nil
+      }
       tryLeft--
       continue
     }
@@ -112,19 +182,41 @@ func (f *fetcher) ContinuouslyFetch() {
     log.Println("successfully fetching finished")
 
     if f.tickerId != "" {
-      return
+      return nil
     }
     f.once.Do(func() {
       f.state.SetModeCode(fetcherModeCurrent)
     })
   }
-  log.Fatalf("fetching failed and stopped")
+  return fmt.Errorf("fetching failed after %d retries", fetcherRetryCount)
+}
+
+// sleep wait interval and return false if fetcher context is done earlier
+func (f *fetcher) sleep(interval time.Duration) bool {
+  timer := time.NewTimer(interval)
+  defer timer.Stop()
+
+  select {
+  case <-timer.C:
+    return true
+  case <-f.ctx.Done():
+    return false
+  }
 }
 
 func (f *fetcher) SaveFetcherState() {
+  if err := f.saveFetcherState(); err != nil {
+    log.Errorf("cannot save fetcher state: %v", err)
+  }
+}
+
+// saveFetcherState overwrite stored fetcher state with the current one.
+// stocks progress is stored separately in per ticker checkpoints
+func (f *fetcher) saveFetcherState() error {
   if err := f.storage.PutFetcherState(createFetcherState(f.state)); err != nil {
-    log.Fatalf("cannot put fetcher state to storage: %v", err)
+    return fmt.Errorf("cannot put fetcher state to storage: %v", err)
   }
+  return nil
 }
 
 func (f *fetcher) loadFetcherState() error {
@@ -136,11 +228,14 @@ func (f *fetcher) loadFetcherState() error {
     return nil
   }
   // set fields from storage state
-  f.state.ticker.requestURL = utils.StripString(state.TickerReqUrl)
-  f.state.tickerDetails.requestURL = utils.StripString(state.TickerDetailsReqUrl)
-  f.state.stocks.requestURL = utils.StripString(state.StockReqUrl)
-  f.state.updatedAt = &state.CreatedAt
-  f.state.finished = state.Finished
+  f.state.ticker.setCursor(utils.StripString(state.TickerReqUrl))
+  f.state.SetUpdatedTime(state.CreatedAt)
+
+  if state.Finished {
+    f.state.SetFinished()
+  } else {
+    f.state.ResetFinished()
+  }
 
   return nil
 }
@@ -149,10 +244,17 @@ func createFetcherState(state *state) *domain.FetcherState {
   if state == nil {
     return nil
   }
+  finished, updatedAt, _ := state.GetStatus()
+  createdAt := utils.NotTimeUTC()
+
+  // keep time of the finished fetching for recently fetched check
+  if finished && updatedAt != nil {
+    createdAt = *updatedAt
+  }
+  // ticker details are fetched by concurrent workers, so their progress has no single cursor
   return &domain.FetcherState{
-    TickerReqUrl:        utils.StripString(state.ticker.requestURL),
-    TickerDetailsReqUrl: utils.StripString(state.tickerDetails.requestURL),
-    StockReqUrl:         utils.StripString(state.stocks.requestURL),
-    CreatedAt:           utils.NotTimeUTC(),
+    TickerReqUrl: utils.StripString(state.ticker.getCursor()),
+    CreatedAt:    createdAt,
+    Finished:     finished,
   }
 }
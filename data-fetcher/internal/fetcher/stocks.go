@@ -3,121 +3,187 @@ package fetcher
 import (
   "fmt"
   "main/internal/domain"
-  "net/url"
+  "main/internal/provider"
   "time"
 
+  "github.com/UshakovN/stock-predictor-service/calendar"
   "github.com/UshakovN/stock-predictor-service/utils"
   log "github.com/sirupsen/logrus"
 )
 
-func (f *fetcher) getTickersResponse(query string) (*tickersResponse, error) {
-  reqURL := buildRequestURL(f.state.ticker, func() string {
-    return fmt.Sprint(basePrefixApi, tickersApi, "?", query)
-  })
-
-  resp, err := f.client.Get(reqURL, nil)
+func (f *fetcher) fetchTickerDetails(tickerId string) (*domain.TickerDetails, []*domain.BrandingOutbox, error) {
+  tickerDetails, err := f.provider.GetTickerDetails(tickerId)
   if err != nil {
-    return nil, fmt.Errorf("cannot get response: %v", err)
+    return nil, nil, fmt.Errorf("cannot get ticker details: %v", err)
   }
-  tickersResp := &tickersResponse{}
-
-  if err = f.client.ParseResponse(resp, tickersResp); err != nil {
-    return nil, fmt.Errorf("cannot parse response: %v", err)
+  details, err := createTickerDetails(tickerDetails)
+  if err != nil {
+    return nil, nil, fmt.Errorf("cannot create ticker details: %v", err)
   }
-  return tickersResp, nil
-}
+  // branding images are sent to media service by the outbox relay
+  outbox := createBrandingOutbox(tickerId, tickerDetails.Branding)
 
-func buildTickersQuery(tickerId string) url.Values {
-  query := url.Values{}
-
-  query.Add("active", "true")
-  query.Add("order", "asc")
-
-  if tickerId != "" {
-    query.Add("ticker", tickerId)
-  }
-  return query
+  return details, outbox, nil
 }
 
-func (f *fetcher) fetchTickerDetails(tickerId string) (*domain.TickerDetails, error) {
-  resp, err := f.getTickerDetailsResponse(tickerId)
+func (f *fetcher) fetchTickerDetailsAndStocks(tickerId string) error {
+  tickerDetails, outbox, err := f.fetchTickerDetails(tickerId)
   if err != nil {
-    return nil, fmt.Errorf("cannot get ticker details response: %v", err)
+    return fmt.Errorf("cannot fetch ticker details for ticker '%s': %v", tickerId, err)
   }
-  if resp.Status != respStatusOK {
-    return nil, fmt.Errorf("bad response status: %s", resp.Status)
+  if err = f.storage.PutTickerDetails(tickerDetails, outbox); err != nil {
+    return fmt.Errorf("cannot put ticker details for ticker '%s' to storage: %v", tickerId, err)
   }
-  if resp.Results == nil {
-    return nil, fmt.Errorf("ticker details results not found")
+  if err = f.fetchStocks(&fetchStocksOption{
+    TickerId: tickerId,
+  }); err != nil {
+    return fmt.Errorf("cannot fetch stocks for ticker '%s': %v", tickerId, err)
   }
+  return nil
+}
 
-  if err = f.sendMessagesToPutTickerBranding(tickerId, resp.Results.Branding); err != nil {
-    log.Errorf("cannot send messages to put branding for ticker '%s': %v", tickerId, err)
+// refreshTickerDetails fetch details of the stored ticker again once per interval
+func (f *fetcher) refreshTickerDetails(tickerId string) error {
+  updatedAt, found, err := f.storage.GetTickerDetailsUpdatedAt(tickerId)
+  if err != nil {
+    return fmt.Errorf("cannot get ticker details update time from storage: %v", err)
   }
-  details, err := createTickerDetails(resp.Results)
+  if found && utils.NotTimeUTC().Sub(updatedAt) < tickerDetailsRefreshInterval {
+    return nil
+  }
+  tickerDetails, outbox, err := f.fetchTickerDetails(tickerId)
   if err != nil {
-    return nil, fmt.Errorf("cannot create ticker details: %v", err)
+    return err
   }
-
-  return details, nil
+  if err = f.storage.RefreshTickerDetails(tickerDetails, outbox); err != nil {
+    return fmt.Errorf("cannot refresh ticker details in storage: %v", err)
+  }
+  return nil
 }
 
-func (f *fetcher) getTickerDetailsResponse(tickerId string) (*tickerDetailsResponse, error) {
-  reqURL := buildRequestURL(f.state.tickerDetails, func() string {
-    tickerDetailsQuery := fmt.Sprintf(tickerDetailsApi, tickerId)
-    return fmt.Sprint(basePrefixApi, tickerDetailsQuery)
-  })
-
-  resp, err := f.client.Get(reqURL, nil)
+func (f *fetcher) FetchInfo() error { // fetch tickers with details and their stocks
+  tickers, err := f.storage.GetTickers()
   if err != nil {
-    return nil, fmt.Errorf("cannot get response: %v", err)
+    return fmt.Errorf("cannot get tickers from storage: %v", err)
   }
-  tickerDetailsResp := &tickerDetailsResponse{}
+  // first we must fetch stocks for stored tickers
+  storedReport, err := f.fetchStocksForStoredTickers(tickers)
+  if err != nil {
+    return fmt.Errorf("cannot fetch stocks for stored tickers: %v", err)
+  }
+  logTickersReport("stored tickers stocks", storedReport)
 
-  if err = f.client.ParseResponse(resp, tickerDetailsResp); err != nil {
-    return nil, fmt.Errorf("cannot parse response: %v", err)
+  if storedReport.allFailed() {
+    return fmt.Errorf("stocks fetching failed for all stored tickers")
   }
-  return tickerDetailsResp, nil
-}
+  storedTickerIds := make(map[string]struct{}, len(tickers))
 
-func (f *fetcher) fetchTickerDetailsAndStocks(tickerId string) error {
-  tickerDetails, err := f.fetchTickerDetails(tickerId)
+  for _, ticker := range tickers {
+    storedTickerIds[ticker.TickerId] = struct{}{}
+  }
+  tickersReport, err := f.discoverTickers(storedTickerIds)
   if err != nil {
-    return fmt.Errorf("cannot fetch ticker details for ticker '%s': %v", tickerId, err)
+    return fmt.Errorf("cannot fetch new tickers: %v", err)
   }
-  if err = f.storage.PutTickerDetails(tickerDetails); err != nil {
-    return fmt.Errorf("cannot put ticker details for ticker '%s' to storage: %v", tickerId, err)
+  logTickersReport("new tickers", tickersReport)
+
+  if tickersReport.allFailed() {
+    return fmt.Errorf("fetching failed for all new tickers")
   }
-  if err = f.fetchStocks(&fetchStocksOption{
-    TickerId: tickerId,
-  }); err != nil {
-    return fmt.Errorf("cannot fetch stocks for ticker '%s': %v", tickerId, err)
+  if f.updateTickers {
+    // stale active flag do not affect stocks fetching
+    if err = f.refreshDelistedTickers(storedTickerIds); err != nil {
+      log.Errorf("cannot refresh delisted tickers: %v", err)
+    }
   }
   return nil
 }
 
-func (f *fetcher) FetchInfo() error { // fetch tickers with details and their stocks
-  var err error
-  // first we must fetch stocks for stored tickers
-  if err = f.fetchStocksForStoredTickers(); err != nil {
-    return fmt.Errorf("cannot fetch stocks for stored tickers: %v", err)
+// refreshDelistedTickers update stored tickers which are delisted in provider once per interval,
+// because active tickers listing do not contain them
+func (f *fetcher) refreshDelistedTickers(storedTickerIds map[string]struct{}) error {
+  if !f.delistedCheckedAt.IsZero() && utils.NotTimeUTC().Sub(f.delistedCheckedAt) < delistedTickersRefreshInterval {
+    return nil
   }
-  //
-  // TODO: remove this
-  log.Infof("finished fecth stocks for stored tickers")
-  return nil
-  // TODO: remove this
-  //
-  if err = f.fetchTickers(&fetchTickersOption{
-    TickerId: f.tickerId, // if ticker id not specified will be fetched all tickers
-  }); err != nil {
-    return fmt.Errorf("cannot fetch new tickers: %v", err)
+  var (
+    cursor  string
+    updated int
+  )
+  for {
+    tickersPage, err := f.provider.ListTickers(&provider.ListTickersOption{
+      TickerId: f.tickerId,
+      Inactive: true,
+      Cursor:   cursor,
+    })
+    if err != nil {
+      return fmt.Errorf("cannot list delisted tickers: %v", err)
+    }
+    for _, providerTicker := range tickersPage.Tickers {
+      if providerTicker == nil {
+        continue
+      }
+      // delisted tickers are not discovered
+      if _, ok := storedTickerIds[providerTicker.Ticker]; !ok {
+        continue
+      }
+      ticker, err := createTicker(providerTicker)
+      if err != nil {
+        return fmt.Errorf("cannot create ticker: %v", err)
+      }
+      if err = f.storage.PutTicker(ticker); err != nil {
+        return fmt.Errorf("cannot put ticker to storage: %v", err)
+      }
+      updated++
+    }
+    if tickersPage.NextCursor == "" {
+      break
+    }
+    cursor = tickersPage.NextCursor
   }
+  f.delistedCheckedAt = utils.NotTimeUTC()
+  log.Infof("delisted tickers refreshed: %d stored tickers delisted in provider", updated)
+
   return nil
 }
 
+func logTickersReport(name string, report *tickersReport) {
+  log.Infof("%s fetching finished: %s", name, report)
+
+  for tickerId, err := range report.failed {
+    log.Warnf("%s fetching failed for ticker '%s': %v", name, tickerId, err)
+  }
+}
+
+// discoverTickers fetch details and stocks of the tickers not stored yet.
+// in watched mode only subscribed tickers are requested instead of listing all provider tickers
+func (f *fetcher) discoverTickers(storedTickerIds map[string]struct{}) (*tickersReport, error) {
+  if f.tickerId != "" || !f.universe.watched() {
+    return f.fetchTickers(&fetchTickersOption{
+      TickerId:        f.tickerId, // if ticker id not specified will be fetched all tickers
+      StoredTickerIds: storedTickerIds,
+    })
+  }
+  watchedIds, err := f.storage.GetWatchedTickerIds()
+  if err != nil {
+    return nil, fmt.Errorf("cannot get watched tickers from storage: %v", err)
+  }
+  tickerIds := make([]string, 0, len(watchedIds))
+
+  for _, tickerId := range watchedIds {
+    if _, ok := storedTickerIds[tickerId]; ok {
+      continue
+    }
+    if f.universe.containsTickerId(tickerId) {
+      tickerIds = append(tickerIds, tickerId)
+    }
+  }
+  return f.processTickers(tickerIds, f.fetchRequestedTicker), nil
+}
+
 type fetchTickersOption struct {
   TickerId string
+  // stocks of stored tickers are already fetched, so they are only updated
+  StoredTickerIds map[string]struct{}
 }
 
 func (o *fetchTickersOption) Validate() error {
@@ -127,110 +193,128 @@ func (o *fetchTickersOption) Validate() error {
   return nil
 }
 
-func (f *fetcher) fetchTickers(options *fetchTickersOption) error {
+func (f *fetcher) fetchTickers(options *fetchTickersOption) (*tickersReport, error) {
   if err := options.Validate(); err != nil {
-    return fmt.Errorf("fetch ticker option validation failed: %v", err)
+    return nil, fmt.Errorf("fetch ticker option validation failed: %v", err)
   }
-  query := buildTickersQuery(options.TickerId)
+  report := newTickersReport()
+  cursor := f.state.ticker.takeCursor()
 
   for {
-    queryStr := query.Encode()
+    f.state.ticker.setCursor(cursor)
 
-    tickersResp, err := f.getTickersResponse(queryStr)
+    tickersPage, err := f.provider.ListTickers(&provider.ListTickersOption{
+      TickerId: options.TickerId,
+      Cursor:   cursor,
+    })
     if err != nil {
-      return fmt.Errorf("cannot get tickers response: %v", err)
+      return nil, fmt.Errorf("cannot list tickers: %v", err)
     }
-    respStatus := tickersResp.Status
-    cursorURL := tickersResp.NextUrl
+    tickerIds := make([]string, 0, len(tickersPage.Tickers))
 
-    if respStatus != respStatusOK {
-      return fmt.Errorf("bad response status: %s", respStatus)
-    }
-    if tickersResp.Count == 0 {
-      break
-    }
-    for _, tickerRespResult := range tickersResp.Results {
-      if tickerRespResult == nil {
+    for _, providerTicker := range tickersPage.Tickers {
+      if providerTicker == nil {
         continue
       }
-      ticker, err := createTicker(tickerRespResult)
+      // explicitly specified ticker is fetched regardless of the universe
+      if options.TickerId == "" && !f.universe.containsProviderTicker(providerTicker) {
+        continue
+      }
+      ticker, err := createTicker(providerTicker)
       if err != nil {
-        return fmt.Errorf("cannot create ticker: %v", err)
+        return nil, fmt.Errorf("cannot create ticker: %v", err)
       }
       if err = f.storage.PutTicker(ticker); err != nil {
-        return fmt.Errorf("cannot put ticker to storage: %v", err)
+        return nil, fmt.Errorf("cannot put ticker to storage: %v", err)
       }
-      if err = f.fetchTickerDetailsAndStocks(ticker.TickerId); err != nil {
-        return fmt.Errorf("cannot fetch full ticker info: %v", err)
+      if _, ok := options.StoredTickerIds[ticker.TickerId]; ok {
+        continue
       }
+      tickerIds = append(tickerIds, ticker.TickerId)
     }
-    if cursorURL == "" {
-      break
-    }
-    cursor, err := url.Parse(cursorURL)
-    if err != nil {
-      return fmt.Errorf("cannot parse cursor URL: %s", cursorURL)
+    // wait the whole page, so the ticker cursor in state stay consistent
+    report.merge(f.processTickers(tickerIds, f.fetchTickerDetailsAndStocks))
+
+    if err = f.saveFetcherState(); err != nil {
+      log.Errorf("cannot save fetcher state after tickers page: %v", err)
     }
-    cursorValue := cursor.Query().Get(respCursorKey)
-    if cursorValue == "" {
+
+    if tickersPage.NextCursor == "" {
       break
     }
-    query.Set(respCursorKey, cursorValue)
+    cursor = tickersPage.NextCursor
   }
-  return nil
+  // listing finished, so the next pass start from the first page
+  f.state.ticker.setCursor("")
+
+  if err := f.saveFetcherState(); err != nil {
+    log.Errorf("cannot save fetcher state after tickers listing: %v", err)
+  }
+  return report, nil
 }
 
-func (f *fetcher) fetchStocksForStoredTickers() error {
-  tickers, err := f.storage.GetTickers()
+func (f *fetcher) fetchStocksForStoredTickers(tickers []*domain.Ticker) (*tickersReport, error) {
+  tickerIds, err := f.universeTickerIds(tickers)
+  if err != nil {
+    return nil, fmt.Errorf("cannot select tickers of the universe: %v", err)
+  }
+  plan, err := f.buildSchedulePlan(tickerIds)
   if err != nil {
-    return fmt.Errorf("cannot get ticker from storage: %v", err)
+    return nil, fmt.Errorf("cannot build schedule plan: %v", err)
   }
-  mustPopularTickers := map[string]struct{}{ // TODO: remove this
-    "AMZN": {},
-    "AAPL": {},
-    "MSFT": {},
-    "IBM":  {},
+  // explicitly specified ticker is refreshed regardless of the schedule
+  if f.tickerId == "" {
+    tickerIds = dueTickerIds(plan)
   }
-  for _, ticker := range tickers {
-    if _, ok := mustPopularTickers[ticker.TickerId]; !ok { // TODO: remove this
-      continue
+  log.Infof("stored tickers due to refresh: %d of %d in universe, %d stored",
+    len(tickerIds), len(plan), len(tickers))
+
+  report := f.processTickers(tickerIds, func(tickerId string) error {
+    // details are not required for stocks, so stale details do not fail the ticker
+    if err := f.refreshTickerDetails(tickerId); err != nil {
+      log.Errorf("cannot refresh ticker details for ticker '%s': %v", tickerId, err)
     }
-    if err = f.fetchStocks(&fetchStocksOption{
-      TickerId:           ticker.TickerId,
-      NotUseRequestState: true,
+    if err := f.fetchStocks(&fetchStocksOption{
+      TickerId: tickerId,
     }); err != nil {
-      return fmt.Errorf("cannot fetch stocks for stored ticker '%s': %v", ticker.TickerId, err)
+      return fmt.Errorf("cannot fetch stocks for stored ticker '%s': %v", tickerId, err)
     }
-  }
-  log.Infof("stocks successfully fetched for '%d' stored tickers", len(tickers))
+    return nil
+  })
 
-  return nil
+  return report, nil
 }
 
-func (f *fetcher) buildStocksReqURL(tickerId string) string {
-  nowT := time.Now()
-  fromT := nowT
-  toT := nowT
+func (f *fetcher) buildAggregatesOption(tickerId string, granularity *Granularity) *provider.AggregatesOption {
+  // range ends at the last exchange trading day
+  to := calendar.Today()
+  if !calendar.IsTradingDay(to) {
+    to = calendar.PrevTradingDay(to)
+  }
   sub := f.state.modeCurrentHours
 
-  if f.state.modeCode == fetcherModeTotal {
+  if f.state.GetModeCode() == fetcherModeTotal {
     sub = f.state.modeTotalHours
   }
   dur := time.Duration(sub) * time.Hour
-  from := fromT.Add(-dur).Format("2006-01-02")
-  to := toT.Format("2006-01-02")
+  from := to.Add(-dur)
 
-  multiplier := 1
-  timespan := "day"
+  // extend range to the previous trading day, so short range is not empty after weekends and holidays
+  if !calendar.IsTradingDay(from) {
+    from = calendar.PrevTradingDay(from)
+  }
 
-  rangeQuery := fmt.Sprintf(stocksApi, tickerId, multiplier, timespan, from, to)
-  reqURL := fmt.Sprint(basePrefixApi, rangeQuery)
-  return reqURL
+  return &provider.AggregatesOption{
+    TickerId:   tickerId,
+    Multiplier: granularity.Multiplier,
+    Timespan:   granularity.Timespan,
+    From:       from,
+    To:         to,
+  }
 }
 
 type fetchStocksOption struct {
-  TickerId           string
-  NotUseRequestState bool
+  TickerId string
 }
 
 func (o *fetchStocksOption) Validate() error {
@@ -244,51 +328,138 @@ func (f *fetcher) fetchStocks(option *fetchStocksOption) error {
   if err := option.Validate(); err != nil {
     return fmt.Errorf("fetch stocks option validation failed: %v", err)
   }
-  var reqURL string
+  adjuster, err := f.refreshCorporateActions(option.TickerId)
+  if err != nil {
+    return fmt.Errorf("cannot refresh corporate actions: %v", err)
+  }
+  // stocks do not depend on financials, so they are fetched anyway
+  if err = f.fetchFinancials(option.TickerId); err != nil {
+    log.Errorf("cannot fetch financials for ticker '%s': %v", option.TickerId, err)
+  }
+  update := newStocksUpdate(option.TickerId)
 
-  if option.NotUseRequestState {
-    reqURL = f.buildStocksReqURL(option.TickerId)
-  } else {
-    reqURL = buildRequestURL(f.state.stocks, func() string {
-      return f.buildStocksReqURL(option.TickerId)
-    })
+  // stored stocks are not fetched again, so they are announced even if the next granularity failed
+  defer f.publishStocksUpdated(update)
+
+  for _, granularity := range f.granularities {
+    if err := f.fetchStocksWithGranularity(option, granularity, adjuster, update.newSeries(granularity)); err != nil {
+      return fmt.Errorf("cannot fetch stocks with granularity '%s': %v", granularity, err)
+    }
   }
+  return nil
+}
 
-  for {
-    resp, err := f.client.Get(reqURL, nil)
-    if err != nil {
-      return fmt.Errorf("cannot get response")
+func (f *fetcher) fetchStocksWithGranularity(
+  option *fetchStocksOption,
+  granularity *Granularity,
+  adjuster *splitAdjuster,
+  stored *storedSeries,
+) error {
+  checkpoint, found, err := f.storage.GetFetcherCheckpoint(option.TickerId, granularity.Timespan, granularity.Multiplier)
+  if err != nil {
+    return fmt.Errorf("cannot get fetcher checkpoint: %v", err)
+  }
+  if !found {
+    checkpoint = &domain.FetcherCheckpoint{
+      TickerId:   option.TickerId,
+      Timespan:   granularity.Timespan,
+      Multiplier: granularity.Multiplier,
     }
-    stockResp := &stocksResponse{}
+  }
+  aggregatesOption := f.buildAggregatesOption(option.TickerId, granularity)
+  resumeFromCheckpoint(aggregatesOption, checkpoint)
 
-    if err := f.client.ParseResponse(resp, stockResp); err != nil {
-      return fmt.Errorf("cannot parse reponse: %v", err)
+  if err = f.fetchStocksPages(aggregatesOption, granularity, adjuster, checkpoint, stored); err != nil {
+    checkpoint.LastError = err.Error()
+    checkpoint.UpdatedAt = utils.NotTimeUTC()
+
+    if err := f.storage.PutFetcherCheckpoint(checkpoint); err != nil {
+      log.Errorf("cannot put fetcher checkpoint for ticker '%s': %v", option.TickerId, err)
     }
+    return err
+  }
+  return nil
+}
+
+// resumeFromCheckpoint continue interrupted pagination by stored cursor,
+// otherwise start from the last fetched bar instead of the range start
+func resumeFromCheckpoint(option *provider.AggregatesOption, checkpoint *domain.FetcherCheckpoint) {
+  if checkpoint.Cursor != "" {
+    option.Cursor = checkpoint.Cursor
+    return
+  }
+  if lastBarDate := truncateDate(checkpoint.LastBarAt); lastBarDate.After(option.From) {
+    option.From = lastBarDate
+  }
+}
+
+func (f *fetcher) fetchStocksPages(
+  aggregatesOption *provider.AggregatesOption,
+  granularity *Granularity,
+  adjuster *splitAdjuster,
+  checkpoint *domain.FetcherCheckpoint,
+  stored *storedSeries,
+) error {
+  tickerId := aggregatesOption.TickerId
 
-    if stockResp.QueryCount == 0 && stockResp.Count == 0 {
-      log.Warnf("stock prices not found for ticker: %s", option.TickerId)
-      return nil
+  series, err := f.newSeries(tickerId, granularity, aggregatesOption.From)
+  if err != nil {
+    return fmt.Errorf("cannot start stocks validation: %v", err)
+  }
+  for {
+    aggregatesPage, err := f.provider.GetAggregates(aggregatesOption)
+    if err != nil {
+      return fmt.Errorf("cannot get aggregates: %v", err)
     }
+    stocks := make([]*domain.Stock, 0, len(aggregatesPage.Bars))
 
-    for _, stockRes := range stockResp.StockResults {
-      stock, err := createStock(option.TickerId, stockRes)
+    for _, bar := range aggregatesPage.Bars {
+      stock, err := createStock(tickerId, granularity, bar)
       if err != nil {
         return fmt.Errorf("cannot create stock: %v", err)
       }
-      if err = f.storage.PutStock(stock); err != nil {
-        return fmt.Errorf("cannot put stock to storage: %v", err)
+      if stock == nil {
+        continue
+      }
+      adjuster.adjust(stock)
+      stocks = append(stocks, stock)
+    }
+    // quarantined bars move the checkpoint too, so they are not fetched again on every run.
+    // bars from the future are not trusted
+    now := utils.NotTimeUTC()
+
+    for _, stock := range stocks {
+      if stock.StockedAt.After(checkpoint.LastBarAt) && !stock.StockedAt.After(now) {
+        checkpoint.LastBarAt = stock.StockedAt
       }
     }
-    if stockResp.NextURL == "" {
+    if stocks, err = f.validateStocks(series, stocks); err != nil {
+      return fmt.Errorf("cannot validate stocks: %v", err)
+    }
+    if len(stocks) == 0 && aggregatesPage.NextCursor == "" && aggregatesOption.Cursor == "" {
+      log.Warnf("stock prices with granularity '%s' not found for ticker: %s", granularity, tickerId)
+    }
+    // checkpoint point to the next page, empty cursor mean the range is fetched
+    checkpoint.Cursor = aggregatesPage.NextCursor
+    checkpoint.LastError = ""
+    checkpoint.UpdatedAt = utils.NotTimeUTC()
+
+    inserted, err := f.storage.PutStocksWithCheckpoint(stocks, checkpoint)
+    if err != nil {
+      return fmt.Errorf("cannot put stocks to storage: %v", err)
+    }
+    stored.add(stocks, inserted)
+
+    if aggregatesPage.NextCursor == "" {
       break
     }
-    reqURL = stockResp.NextURL
+    aggregatesOption.Cursor = aggregatesPage.NextCursor
   }
 
   return nil
 }
 
-func createTicker(res *tickerResult) (*domain.Ticker, error) {
+func createTicker(res *provider.Ticker) (*domain.Ticker, error) {
   if res == nil {
     return nil, nil
   }
@@ -308,23 +479,32 @@ func createTicker(res *tickerResult) (*domain.Ticker, error) {
   return ticker, nil
 }
 
-func createTickerDetails(res *tickerDetailsResults) (*domain.TickerDetails, error) {
+func createTickerDetails(res *provider.TickerDetails) (*domain.TickerDetails, error) {
   if res == nil {
     return nil, nil
   }
+  now := utils.NotTimeUTC()
+
   details := &domain.TickerDetails{
     TickerId:           res.Ticker,
     CompanyDescription: res.Description,
     HomepageUrl:        res.HomepageUrl,
     PhoneNumber:        res.PhoneNumber,
     TotalEmployees:     res.TotalEmployees,
+    SicCode:            res.SicCode,
+    SicDescription:     res.SicDescription,
+    Sector:             sectorBySic(res.SicCode),
+    PrimaryExchange:    res.PrimaryExchange,
+    Market:             res.Market,
+    ListDate:           parseOptionalDate(res.ListDate),
+    UpdatedAt:          now,
   }
   if res.Address != nil {
     details.CompanyState = res.Address.State
     details.CompanyCity = utils.TitleString(res.Address.City)
     details.CompanyAddress = utils.TitleString(res.Address.Address1)
     details.CompanyPostalCode = res.Address.PostalCode
-    details.CreatedAt = utils.NotTimeUTC()
+    details.CreatedAt = now
   }
   if err := utils.SetDefaultStringValues(details, defaultStringValue); err != nil {
     return nil, err
@@ -332,21 +512,28 @@ func createTickerDetails(res *tickerDetailsResults) (*domain.TickerDetails, erro
   return details, nil
 }
 
-func createStock(tickerId string, res *stockResult) (*domain.Stock, error) {
+func createStock(tickerId string, granularity *Granularity, res *provider.Bar) (*domain.Stock, error) {
   if res == nil {
     return nil, nil
   }
-  const sepId = "-"
   stock := &domain.Stock{
-    StockId:       fmt.Sprint(tickerId, sepId, res.Timestamp),
+    StockId:       createStockId(tickerId, granularity, res.Timestamp),
     TickerId:      tickerId,
     OpenPrice:     res.Open,
     ClosePrice:    res.Close,
     HighestPrice:  res.Highest,
     LowestPrice:   res.Lowest,
     TradingVolume: res.Volume,
+    Timespan:      granularity.Timespan,
+    Multiplier:    granularity.Multiplier,
     StockedAt:     utils.TimestampToTimeUTC(res.Timestamp),
     CreatedAt:     utils.NotTimeUTC(),
+    // adjusted series match the raw one until splits applied
+    AdjOpenPrice:     res.Open,
+    AdjClosePrice:    res.Close,
+    AdjHighestPrice:  res.Highest,
+    AdjLowestPrice:   res.Lowest,
+    AdjTradingVolume: res.Volume,
   }
   if err := utils.SetDefaultStringValues(stock, defaultStringValue); err != nil {
     return nil, err
@@ -354,18 +541,13 @@ func createStock(tickerId string, res *stockResult) (*domain.Stock, error) {
   return stock, nil
 }
 
-func buildRequestURL(stateRequest *stateRequest, builder func() string) string {
-  var reqURL string
-  // if request URL not used and set in state
-  if !stateRequest.used && stateRequest.requestURL != "" {
-    reqURL = stateRequest.requestURL
-    // use it once
-    stateRequest.used = true
-  } else {
-    // else form new request URL
-    reqURL = builder()
-    // save it in state
-    stateRequest.requestURL = reqURL
-  }
-  return reqURL
+// createStockId form 'ticker-timestamp' for daily bars to keep stored ids
+// and 'ticker-granularity-timestamp' for the others, e.g. 'AAPL-5minute-1672531200000'
+func createStockId(tickerId string, granularity *Granularity, timestamp int64) string {
+  const sepId = "-"
+
+  if granularity.isDefault() {
+    return fmt.Sprint(tickerId, sepId, timestamp)
+  }
+  return fmt.Sprint(tickerId, sepId, granularity, sepId, timestamp)
 }
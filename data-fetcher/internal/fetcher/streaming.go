@@ -0,0 +1,125 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "main/internal/provider"
+  "main/internal/stream"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/calendar"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+// streamGranularity of bars received from the stream
+var streamGranularity = &Granularity{
+  Multiplier: 1,
+  Timespan:   provider.TimespanMinute,
+}
+
+// streamSeries keep validation series of the stream tickers for one exchange day,
+// so seen bars of the past days are not kept for the whole process
+type streamSeries struct {
+  day    time.Time
+  from   time.Time
+  series map[string]*seriesValidation
+}
+
+// resetOnDay start new series on the next exchange day
+func (s *streamSeries) resetOnDay(today time.Time) {
+  if s.day.Equal(today) {
+    return
+  }
+  s.day = today
+  // bars of the minute in progress are not stale
+  s.from = utils.NotTimeUTC().Truncate(time.Minute).Add(-time.Minute)
+  s.series = map[string]*seriesValidation{}
+}
+
+// ContinuouslyStream persist real-time minute bars of the stream until context is done
+func (f *fetcher) ContinuouslyStream() {
+  if f.stream == nil {
+    log.Infof("stream ingestion disabled")
+    return
+  }
+  tickerIds, err := f.streamTickerIds()
+  if err != nil {
+    log.Errorf("cannot get stream tickers: %v", err)
+    return
+  }
+  if len(tickerIds) == 0 {
+    log.Warnf("stream ingestion stopped: no tickers to stream")
+    return
+  }
+  series := &streamSeries{}
+
+  f.stream.Run(f.ctx, tickerIds, func(bars []*stream.Bar) error {
+    series.resetOnDay(calendar.Today())
+
+    return f.putStreamBars(series, bars)
+  })
+}
+
+func (f *fetcher) streamTickerIds() ([]string, error) {
+  if len(f.streamTickers) != 0 {
+    return f.streamTickers, nil
+  }
+  tickerIds, err := f.storage.GetWatchedTickerIds()
+  if err != nil {
+    return nil, fmt.Errorf("cannot get watched tickers from storage: %v", err)
+  }
+  return tickerIds, nil
+}
+
+// putStreamBars validate bars with series kept per ticker and put the valid ones to storage.
+// series are updated only after bars are stored, so failed bars are validated again on retry
+func (f *fetcher) putStreamBars(series *streamSeries, bars []*stream.Bar) error {
+  tickersStocks := map[string][]*domain.Stock{}
+
+  for _, bar := range bars {
+    stock, err := createStock(bar.TickerId, streamGranularity, &provider.Bar{
+      Open:      bar.Open,
+      Close:     bar.Close,
+      Highest:   bar.Highest,
+      Lowest:    bar.Lowest,
+      Volume:    bar.Volume,
+      Timestamp: bar.Start,
+    })
+    if err != nil {
+      return fmt.Errorf("cannot create stock from stream bar: %v", err)
+    }
+    tickersStocks[bar.TickerId] = append(tickersStocks[bar.TickerId], stock)
+  }
+  var stocks []*domain.Stock
+  validated := make(map[string]*seriesValidation, len(tickersStocks))
+
+  for tickerId, tickerStocks := range tickersStocks {
+    tickerSeries, ok := series.series[tickerId]
+    if !ok {
+      var err error
+      if tickerSeries, err = f.newSeries(tickerId, streamGranularity, series.from); err != nil {
+        return fmt.Errorf("cannot start validation for ticker '%s': %v", tickerId, err)
+      }
+    } else {
+      tickerSeries = tickerSeries.clone()
+    }
+    valid, err := f.validateStocks(tickerSeries, tickerStocks)
+    if err != nil {
+      return fmt.Errorf("cannot validate stream stocks for ticker '%s': %v", tickerId, err)
+    }
+    validated[tickerId] = tickerSeries
+    stocks = append(stocks, valid...)
+  }
+  inserted, err := f.storage.PutStocks(stocks)
+  if err != nil {
+    return fmt.Errorf("cannot put stream stocks to storage: %v", err)
+  }
+  for tickerId, tickerSeries := range validated {
+    series.series[tickerId] = tickerSeries
+  }
+  if inserted > 0 {
+    log.Infof("%d stream bars of %d tickers stored", inserted, len(tickersStocks))
+  }
+  return nil
+}
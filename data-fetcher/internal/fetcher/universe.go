@@ -0,0 +1,130 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "main/internal/provider"
+  "strings"
+)
+
+const (
+  universeModeAll     = "all"
+  universeModeWatched = "watched"
+)
+
+type UniverseConfig struct {
+  // mode 'all' discover all provider tickers, mode 'watched' only tickers with active subscriptions
+  Mode    string   `yaml:"mode"`
+  Include []string `yaml:"include"`
+  Exclude []string `yaml:"exclude"`
+  // market and type are known only for provider tickers, so they filter discovered tickers
+  Markets []string `yaml:"markets"`
+  Locales []string `yaml:"locales"`
+  Types   []string `yaml:"types"`
+}
+
+// universe decide which tickers are fetched
+type universe struct {
+  mode    string
+  include map[string]struct{}
+  exclude map[string]struct{}
+  markets map[string]struct{}
+  locales map[string]struct{}
+  types   map[string]struct{}
+}
+
+func newUniverse(config *UniverseConfig) (*universe, error) {
+  if config == nil {
+    config = &UniverseConfig{}
+  }
+  mode := config.Mode
+  if mode == "" {
+    mode = universeModeAll
+  }
+  if mode != universeModeAll && mode != universeModeWatched {
+    return nil, fmt.Errorf("unknown universe mode '%s'. possible: %s, %s",
+      mode, universeModeAll, universeModeWatched)
+  }
+  return &universe{
+    mode:    mode,
+    include: newValuesSet(config.Include, strings.ToUpper),
+    exclude: newValuesSet(config.Exclude, strings.ToUpper),
+    markets: newValuesSet(config.Markets, strings.ToLower),
+    locales: newValuesSet(config.Locales, strings.ToLower),
+    types:   newValuesSet(config.Types, strings.ToUpper),
+  }, nil
+}
+
+func newValuesSet(values []string, normalize func(string) string) map[string]struct{} {
+  set := make(map[string]struct{}, len(values))
+
+  for _, value := range values {
+    if value = strings.TrimSpace(value); value != "" {
+      set[normalize(value)] = struct{}{}
+    }
+  }
+  return set
+}
+
+// matchSet report that value is in set, empty set match any value
+func matchSet(set map[string]struct{}, value string) bool {
+  if len(set) == 0 {
+    return true
+  }
+  _, ok := set[value]
+  return ok
+}
+
+func (u *universe) watched() bool {
+  return u.mode == universeModeWatched
+}
+
+// containsTickerId check include and exclude lists
+func (u *universe) containsTickerId(tickerId string) bool {
+  tickerId = strings.ToUpper(tickerId)
+
+  if _, ok := u.exclude[tickerId]; ok {
+    return false
+  }
+  return matchSet(u.include, tickerId)
+}
+
+func (u *universe) containsTicker(ticker *domain.Ticker) bool {
+  return u.containsTickerId(ticker.TickerId) &&
+    matchSet(u.locales, strings.ToLower(ticker.CompanyLocale))
+}
+
+func (u *universe) containsProviderTicker(ticker *provider.Ticker) bool {
+  return u.containsTickerId(ticker.Ticker) &&
+    matchSet(u.markets, strings.ToLower(ticker.Market)) &&
+    matchSet(u.locales, strings.ToLower(ticker.Locale)) &&
+    matchSet(u.types, strings.ToUpper(ticker.Type))
+}
+
+// universeTickerIds return stored tickers included in the universe.
+// in watched mode only tickers with active subscriptions are returned
+func (f *fetcher) universeTickerIds(tickers []*domain.Ticker) ([]string, error) {
+  var watched map[string]struct{}
+
+  if f.universe.watched() {
+    watchedIds, err := f.storage.GetWatchedTickerIds()
+    if err != nil {
+      return nil, fmt.Errorf("cannot get watched tickers from storage: %v", err)
+    }
+    watched = newValuesSet(watchedIds, strings.ToUpper)
+  }
+  tickerIds := make([]string, 0, len(tickers))
+
+  for _, ticker := range tickers {
+    if !f.universe.containsTicker(ticker) {
+      continue
+    }
+    if f.universe.watched() {
+      if _, ok := watched[strings.ToUpper(ticker.TickerId)]; !ok {
+        continue
+      }
+    }
+    tickerIds = append(tickerIds, ticker.TickerId)
+  }
+  return tickerIds, nil
+}
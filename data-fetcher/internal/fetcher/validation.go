@@ -0,0 +1,245 @@
+package fetcher
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "math"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+const (
+  ruleNonPositivePrice   = "non_positive_price"
+  ruleNegativeVolume     = "negative_volume"
+  ruleOhlcConsistency    = "ohlc_consistency"
+  ruleDuplicateTimestamp = "duplicate_timestamp"
+  ruleStaleTimestamp     = "stale_timestamp"
+  ruleOutlierJump        = "outlier_jump"
+)
+
+// validationRules in order of checking, the first violated rule is the quarantine reason
+var validationRules = []string{
+  ruleNonPositivePrice,
+  ruleNegativeVolume,
+  ruleOhlcConsistency,
+  ruleDuplicateTimestamp,
+  ruleStaleTimestamp,
+  ruleOutlierJump,
+}
+
+const defaultMaxJumpPercent = 50
+
+type ValidationConfig struct {
+  // enabled rules, all rules are enabled if not specified
+  Rules          []string `yaml:"rules"`
+  MaxJumpPercent float64  `yaml:"max_jump_percent"`
+}
+
+// validator check bars before they are persisted
+type validator struct {
+  rules        map[string]struct{}
+  maxJumpRatio float64
+}
+
+func newValidator(config *ValidationConfig) (*validator, error) {
+  if config == nil {
+    config = &ValidationConfig{}
+  }
+  rules := config.Rules
+  if len(rules) == 0 {
+    rules = validationRules
+  }
+  enabled := make(map[string]struct{}, len(rules))
+
+  for _, rule := range rules {
+    if !validRule(rule) {
+      return nil, fmt.Errorf("unknown validation rule '%s'", rule)
+    }
+    enabled[rule] = struct{}{}
+  }
+  maxJumpPercent := config.MaxJumpPercent
+  if maxJumpPercent < 0 {
+    return nil, fmt.Errorf("max jump percent must not be negative")
+  }
+  if maxJumpPercent == 0 {
+    maxJumpPercent = defaultMaxJumpPercent
+  }
+
+  return &validator{
+    rules:        enabled,
+    maxJumpRatio: maxJumpPercent / 100,
+  }, nil
+}
+
+func validRule(rule string) bool {
+  for _, known := range validationRules {
+    if rule == known {
+      return true
+    }
+  }
+  return false
+}
+
+func (v *validator) enabled(rule string) bool {
+  _, ok := v.rules[rule]
+  return ok
+}
+
+// seriesValidation keep state of one fetched series between the pages
+type seriesValidation struct {
+  validator *validator
+  from      time.Time
+  prevClose float64
+  seen      map[string]struct{}
+}
+
+// newSeries start validation of bars fetched from the time.
+// prior close is taken from the last stored bar, so the first bar is checked for jump too
+func (f *fetcher) newSeries(tickerId string, granularity *Granularity, from time.Time) (*seriesValidation, error) {
+  series := &seriesValidation{
+    validator: f.validator,
+    from:      from,
+    seen:      map[string]struct{}{},
+  }
+  if !f.validator.enabled(ruleOutlierJump) {
+    return series, nil
+  }
+  lastStock, found, err := f.storage.GetLastStock(tickerId, granularity.Timespan, granularity.Multiplier, from)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get last stock from storage: %v", err)
+  }
+  if found {
+    series.prevClose = lastStock.AdjClosePrice
+  }
+  return series, nil
+}
+
+// clone return copy of the series, so validation of the batch is discarded if it is not stored
+func (s *seriesValidation) clone() *seriesValidation {
+  seen := make(map[string]struct{}, len(s.seen))
+
+  for stockId := range s.seen {
+    seen[stockId] = struct{}{}
+  }
+  return &seriesValidation{
+    validator: s.validator,
+    from:      s.from,
+    prevClose: s.prevClose,
+    seen:      seen,
+  }
+}
+
+// check return violated rule with reason, empty rule mean the stock is valid
+func (s *seriesValidation) check(stock *domain.Stock) (string, string) {
+  v := s.validator
+
+  if v.enabled(ruleNonPositivePrice) {
+    if stock.OpenPrice <= 0 || stock.ClosePrice <= 0 || stock.HighestPrice <= 0 || stock.LowestPrice <= 0 {
+      return ruleNonPositivePrice, fmt.Sprintf("prices must be positive, got open %v, close %v, high %v, low %v",
+        stock.OpenPrice, stock.ClosePrice, stock.HighestPrice, stock.LowestPrice)
+    }
+  }
+  if v.enabled(ruleNegativeVolume) {
+    if stock.TradingVolume < 0 {
+      return ruleNegativeVolume, fmt.Sprintf("trading volume %v is negative", stock.TradingVolume)
+    }
+  }
+  if v.enabled(ruleOhlcConsistency) {
+    if stock.HighestPrice < stock.LowestPrice {
+      return ruleOhlcConsistency, fmt.Sprintf("high %v is less than low %v", stock.HighestPrice, stock.LowestPrice)
+    }
+    for _, price := range []float64{stock.OpenPrice, stock.ClosePrice} {
+      if price < stock.LowestPrice || price > stock.HighestPrice {
+        return ruleOhlcConsistency, fmt.Sprintf("open %v or close %v is out of low %v and high %v",
+          stock.OpenPrice, stock.ClosePrice, stock.LowestPrice, stock.HighestPrice)
+      }
+    }
+  }
+  if v.enabled(ruleDuplicateTimestamp) {
+    if _, ok := s.seen[stock.StockId]; ok {
+      return ruleDuplicateTimestamp, fmt.Sprintf("timestamp %s already fetched in series",
+        stock.StockedAt.Format(time.RFC3339))
+    }
+  }
+  if v.enabled(ruleStaleTimestamp) {
+    if stock.StockedAt.Before(s.from) {
+      return ruleStaleTimestamp, fmt.Sprintf("timestamp %s is before requested range start %s",
+        stock.StockedAt.Format(time.RFC3339), s.from.Format(time.RFC3339))
+    }
+    if stock.StockedAt.After(utils.NotTimeUTC()) {
+      return ruleStaleTimestamp, fmt.Sprintf("timestamp %s is in the future",
+        stock.StockedAt.Format(time.RFC3339))
+    }
+  }
+  if v.enabled(ruleOutlierJump) && s.prevClose > 0 {
+    // adjusted close is compared, so split is not taken as jump
+    jump := math.Abs(stock.AdjClosePrice-s.prevClose) / s.prevClose
+
+    if jump > v.maxJumpRatio {
+      return ruleOutlierJump, fmt.Sprintf("close %v jumped by %.2f%% from prior close %v",
+        stock.AdjClosePrice, jump*100, s.prevClose)
+    }
+  }
+  return "", ""
+}
+
+func (s *seriesValidation) accept(stock *domain.Stock) {
+  s.seen[stock.StockId] = struct{}{}
+  s.prevClose = stock.AdjClosePrice
+}
+
+// reject keep the series going after the quarantined stock. the jumped close become the prior one,
+// otherwise the real price level change quarantine all the next bars of the ticker
+func (s *seriesValidation) reject(stock *domain.Stock, rule string) {
+  if rule == ruleOutlierJump {
+    s.prevClose = stock.AdjClosePrice
+  }
+}
+
+// validateStocks store invalid stocks to quarantine and return the valid ones
+func (f *fetcher) validateStocks(series *seriesValidation, stocks []*domain.Stock) ([]*domain.Stock, error) {
+  valid := make([]*domain.Stock, 0, len(stocks))
+  var quarantined []*domain.StockQuarantine
+
+  for _, stock := range stocks {
+    rule, reason := series.check(stock)
+    if rule == "" {
+      series.accept(stock)
+      valid = append(valid, stock)
+      continue
+    }
+    series.reject(stock, rule)
+    log.Warnf("stock '%s' quarantined by rule '%s': %s", stock.StockId, rule, reason)
+
+    quarantined = append(quarantined, createStockQuarantine(stock, rule, reason))
+  }
+  if err := f.storage.PutStockQuarantine(quarantined); err != nil {
+    return nil, fmt.Errorf("cannot put quarantined stocks to storage: %v", err)
+  }
+  return valid, nil
+}
+
+func createStockQuarantine(stock *domain.Stock, rule, reason string) *domain.StockQuarantine {
+  return &domain.StockQuarantine{
+    QuarantineId:  fmt.Sprint(stock.StockId, "-", rule),
+    StockId:       stock.StockId,
+    TickerId:      stock.TickerId,
+    Timespan:      stock.Timespan,
+    Multiplier:    stock.Multiplier,
+    OpenPrice:     stock.OpenPrice,
+    ClosePrice:    stock.ClosePrice,
+    HighestPrice:  stock.HighestPrice,
+    LowestPrice:   stock.LowestPrice,
+    TradingVolume: stock.TradingVolume,
+    StockedAt:     stock.StockedAt,
+    Rule:          rule,
+    Reason:        reason,
+    CreatedAt:     utils.NotTimeUTC(),
+  }
+}
+
+func (f *fetcher) GetQuarantineCounts(tickerId string) ([]*domain.QuarantineCount, error) {
+  return f.storage.GetQuarantineCounts(tickerId)
+}
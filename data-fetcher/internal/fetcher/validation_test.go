@@ -0,0 +1,194 @@
+package fetcher
+
+import (
+  "main/internal/domain"
+  "testing"
+  "time"
+)
+
+func newTestSeries(t *testing.T, from time.Time, prevClose float64) *seriesValidation {
+  t.Helper()
+
+  v, err := newValidator(nil)
+  if err != nil {
+    t.Fatalf("cannot create validator: %v", err)
+  }
+  return &seriesValidation{
+    validator: v,
+    from:      from,
+    prevClose: prevClose,
+    seen:      map[string]struct{}{},
+  }
+}
+
+func newTestStock(stockId string, stockedAt time.Time, open, high, low, close float64) *domain.Stock {
+  return &domain.Stock{
+    StockId:       stockId,
+    TickerId:      "AAPL",
+    OpenPrice:     open,
+    ClosePrice:    close,
+    HighestPrice:  high,
+    LowestPrice:   low,
+    TradingVolume: 1000,
+    AdjClosePrice: close,
+    StockedAt:     stockedAt,
+  }
+}
+
+func TestNewValidator(t *testing.T) {
+  if _, err := newValidator(&ValidationConfig{Rules: []string{"unknown"}}); err == nil {
+    t.Errorf("expected error on unknown rule")
+  }
+  if _, err := newValidator(&ValidationConfig{MaxJumpPercent: -1}); err == nil {
+    t.Errorf("expected error on negative max jump percent")
+  }
+  v, err := newValidator(&ValidationConfig{Rules: []string{ruleOutlierJump}, MaxJumpPercent: 10})
+  if err != nil {
+    t.Fatalf("cannot create validator: %v", err)
+  }
+  if v.enabled(ruleNonPositivePrice) || !v.enabled(ruleOutlierJump) {
+    t.Errorf("expected only '%s' rule enabled, got %v", ruleOutlierJump, v.rules)
+  }
+  if v.maxJumpRatio != 0.1 {
+    t.Errorf("expected max jump ratio 0.1, got %v", v.maxJumpRatio)
+  }
+}
+
+func TestSeriesValidationCheck(t *testing.T) {
+  from := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+  stockedAt := from.AddDate(0, 0, 1)
+
+  testCases := []struct {
+    name      string
+    prevClose float64
+    seen      string
+    stock     *domain.Stock
+    rule      string
+  }{
+    {
+      name:  "valid",
+      stock: newTestStock("s1", stockedAt, 10, 12, 9, 11),
+    },
+    {
+      name:  "non positive price",
+      stock: newTestStock("s1", stockedAt, 10, 12, 0, 11),
+      rule:  ruleNonPositivePrice,
+    },
+    {
+      name: "negative volume",
+      stock: func() *domain.Stock {
+        stock := newTestStock("s1", stockedAt, 10, 12, 9, 11)
+        stock.TradingVolume = -1
+        return stock
+      }(),
+      rule: ruleNegativeVolume,
+    },
+    {
+      name:  "high less than low",
+      stock: newTestStock("s1", stockedAt, 10, 9, 12, 11),
+      rule:  ruleOhlcConsistency,
+    },
+    {
+      name:  "close out of range",
+      stock: newTestStock("s1", stockedAt, 10, 12, 9, 13),
+      rule:  ruleOhlcConsistency,
+    },
+    {
+      name:  "duplicate timestamp",
+      seen:  "s1",
+      stock: newTestStock("s1", stockedAt, 10, 12, 9, 11),
+      rule:  ruleDuplicateTimestamp,
+    },
+    {
+      name:  "before range start",
+      stock: newTestStock("s1", from.AddDate(0, 0, -1), 10, 12, 9, 11),
+      rule:  ruleStaleTimestamp,
+    },
+    {
+      name:  "in the future",
+      stock: newTestStock("s1", time.Now().Add(time.Hour), 10, 12, 9, 11),
+      rule:  ruleStaleTimestamp,
+    },
+    {
+      name:      "jump within limit",
+      prevClose: 8,
+      stock:     newTestStock("s1", stockedAt, 10, 12, 9, 11),
+    },
+    {
+      name:      "jump over limit",
+      prevClose: 5,
+      stock:     newTestStock("s1", stockedAt, 10, 12, 9, 11),
+      rule:      ruleOutlierJump,
+    },
+    {
+      name: "adjusted close is compared",
+      // raw close of the bar before 4:1 split
+      prevClose: 11,
+      stock: func() *domain.Stock {
+        stock := newTestStock("s1", stockedAt, 40, 48, 36, 44)
+        stock.AdjClosePrice = 11
+        return stock
+      }(),
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      series := newTestSeries(t, from, testCase.prevClose)
+      if testCase.seen != "" {
+        series.seen[testCase.seen] = struct{}{}
+      }
+      rule, reason := series.check(testCase.stock)
+      if rule != testCase.rule {
+        t.Errorf("expected rule '%s', got '%s' (%s)", testCase.rule, rule, reason)
+      }
+      if rule != "" && reason == "" {
+        t.Errorf("expected reason of the violated rule '%s'", rule)
+      }
+    })
+  }
+}
+
+func TestSeriesValidationAcceptReject(t *testing.T) {
+  from := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+  series := newTestSeries(t, from, 10)
+
+  accepted := newTestStock("s1", from, 10, 12, 9, 11)
+  series.accept(accepted)
+
+  if series.prevClose != 11 {
+    t.Errorf("expected prior close 11 after accept, got %v", series.prevClose)
+  }
+  if rule, _ := series.check(accepted); rule != ruleDuplicateTimestamp {
+    t.Errorf("expected accepted stock to be duplicate, got rule '%s'", rule)
+  }
+  inconsistent := newTestStock("s2", from.AddDate(0, 0, 1), 10, 9, 12, 11)
+  series.reject(inconsistent, ruleOhlcConsistency)
+
+  if series.prevClose != 11 {
+    t.Errorf("expected prior close kept after '%s' reject, got %v", ruleOhlcConsistency, series.prevClose)
+  }
+  // price level changed, so the next bars are compared with the jumped close
+  jumped := newTestStock("s3", from.AddDate(0, 0, 2), 30, 33, 29, 32)
+  if rule, _ := series.check(jumped); rule != ruleOutlierJump {
+    t.Fatalf("expected rule '%s', got '%s'", ruleOutlierJump, rule)
+  }
+  series.reject(jumped, ruleOutlierJump)
+
+  if series.prevClose != 32 {
+    t.Errorf("expected prior close 32 after jump reject, got %v", series.prevClose)
+  }
+  next := newTestStock("s4", from.AddDate(0, 0, 3), 32, 34, 31, 33)
+  if rule, reason := series.check(next); rule != "" {
+    t.Errorf("expected bar after jump to be valid, got rule '%s' (%s)", rule, reason)
+  }
+  // rejected stocks are not seen, so they can be fetched again
+  if _, ok := series.seen[jumped.StockId]; ok {
+    t.Errorf("expected rejected stock not to be seen")
+  }
+  clone := series.clone()
+  clone.accept(next)
+
+  if _, ok := series.seen[next.StockId]; ok || series.prevClose != 32 {
+    t.Errorf("expected clone not to change the series")
+  }
+}
@@ -0,0 +1,373 @@
+package handler
+
+import (
+  "context"
+  "crypto/subtle"
+  "fmt"
+  "main/internal/fetcher"
+  "net/http"
+
+  "github.com/UshakovN/stock-predictor-service/contract/common"
+  datafetcher "github.com/UshakovN/stock-predictor-service/contract/data-fetcher"
+  "github.com/UshakovN/stock-predictor-service/errs"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  log "github.com/sirupsen/logrus"
+)
+
+type Handler struct {
+  ctx             context.Context
+  fetcher         fetcher.Fetcher
+  controlApiToken string
+}
+
+func NewHandler(ctx context.Context, f fetcher.Fetcher, controlApiToken string) *Handler {
+  return &Handler{
+    ctx:             ctx,
+    fetcher:         f,
+    controlApiToken: controlApiToken,
+  }
+}
+
+func (h *Handler) BindRouter() {
+  http.Handle("/health", errs.MiddlewareErr(h.HandleHealth))
+  http.Handle("/gaps", errs.MiddlewareErr(h.HandleGaps))
+  http.Handle("/quarantine", errs.MiddlewareErr(h.HandleQuarantine))
+
+  if h.controlApiToken == "" {
+    log.Warnf("control api token not specified. control api disabled")
+    return
+  }
+  http.Handle("/control/run", errs.MiddlewareErr(h.controlAuth(h.HandleControlRun)))
+  http.Handle("/control/pause", errs.MiddlewareErr(h.controlAuth(h.HandleControlPause)))
+  http.Handle("/control/resume", errs.MiddlewareErr(h.controlAuth(h.HandleControlResume)))
+  http.Handle("/control/mode", errs.MiddlewareErr(h.controlAuth(h.HandleControlMode)))
+  http.Handle("/control/progress", errs.MiddlewareErr(h.controlAuth(h.HandleControlProgress)))
+  http.Handle("/control/errors", errs.MiddlewareErr(h.controlAuth(h.HandleControlErrors)))
+  http.Handle("/control/schedule", errs.MiddlewareErr(h.controlAuth(h.HandleControlSchedule)))
+}
+
+func (h *Handler) controlAuth(handler errs.HandlerErr) errs.HandlerErr {
+  return func(w http.ResponseWriter, r *http.Request) error {
+    apiToken := r.Header.Get(datafetcher.ApiTokenHeader)
+    if apiToken == "" {
+      return errs.NewError(errs.ErrTypeNotFoundToken, nil)
+    }
+    if subtle.ConstantTimeCompare([]byte(apiToken), []byte(h.controlApiToken)) != 1 {
+      return errs.NewError(errs.ErrTypeForbidden, nil)
+    }
+    return handler(w, r)
+  }
+}
+
+// HandleHealth
+//
+// @Summary Health check method
+// @Description Health method check http server health
+// @Tags Health
+// @Produce application/json
+// @Success 200 {object} common.HealthResponse
+// @Router /health [get]
+//
+func (h *Handler) HandleHealth(w http.ResponseWriter, _ *http.Request) error {
+  if err := utils.WriteResponse(w, &common.HealthResponse{
+    Success: true,
+  }, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+// HandleGaps
+//
+// @Summary Stock gaps method
+// @Description Gaps method provide report of filled and unfillable gaps found in stored stocks
+// @Tags Gaps
+// @Produce application/json
+// @Param request query datafetcher.GapsRequest true "Request"
+// @Success 200 {object} datafetcher.GapsResponse
+// @Failure 400,500 {object} errs.Error
+// @Router /gaps [get]
+//
+func (h *Handler) HandleGaps(w http.ResponseWriter, r *http.Request) error {
+  req := &datafetcher.GapsRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := req.Validate(); err != nil {
+    return err
+  }
+  gaps, err := h.fetcher.GetStockGaps(req.TickerId, req.Status)
+  if err != nil {
+    return fmt.Errorf("cannot get stock gaps: %v", err)
+  }
+  resp := &datafetcher.GapsResponse{
+    Success: true,
+    Count:   len(gaps),
+    Gaps:    []*datafetcher.Gap{},
+  }
+  if err = utils.FillFrom(gaps, &resp.Gaps); err != nil {
+    return err
+  }
+  if err = utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+// HandleQuarantine
+//
+// @Summary Stock quarantine method
+// @Description Quarantine method provide counts of bars rejected by validation rules before persisting
+// @Tags Quarantine
+// @Produce application/json
+// @Param request query datafetcher.QuarantineRequest true "Request"
+// @Success 200 {object} datafetcher.QuarantineResponse
+// @Failure 400,500 {object} errs.Error
+// @Router /quarantine [get]
+//
+func (h *Handler) HandleQuarantine(w http.ResponseWriter, r *http.Request) error {
+  req := &datafetcher.QuarantineRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  counts, err := h.fetcher.GetQuarantineCounts(req.TickerId)
+  if err != nil {
+    return fmt.Errorf("cannot get quarantine counts: %v", err)
+  }
+  resp := &datafetcher.QuarantineResponse{
+    Success: true,
+    Rules:   []*datafetcher.QuarantineRule{},
+  }
+  if err = utils.FillFrom(counts, &resp.Rules); err != nil {
+    return err
+  }
+  for _, count := range resp.Rules {
+    resp.Total += count.Count
+  }
+  if err = utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+// HandleControlRun
+//
+// @Summary Control run method
+// @Description Run method start fetching of tickers with details and stocks in background
+// @Tags Control
+// @Accept application/json
+// @Produce application/json
+// @Param request body datafetcher.RunRequest true "Request"
+// @Success 200 {object} datafetcher.ControlResponse
+// @Failure 400,401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /control/run [post]
+//
+func (h *Handler) HandleControlRun(w http.ResponseWriter, r *http.Request) error {
+  if r.Method != http.MethodPost {
+    return errs.NewError(errs.ErrTypeMethodNotSupported, nil)
+  }
+  req := &datafetcher.RunRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := req.Validate(); err != nil {
+    return err
+  }
+  if err := h.fetcher.RunTickers(req.TickerIds); err != nil {
+    if errs.ErrIs(err, fetcher.ErrRunInProgress) {
+      return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest, err.Error(), nil)
+    }
+    return fmt.Errorf("cannot run tickers fetching: %v", err)
+  }
+  return writeControlResponse(w)
+}
+
+// HandleControlPause
+//
+// @Summary Control pause method
+// @Description Pause method suspend fetching before the next ticker
+// @Tags Control
+// @Produce application/json
+// @Success 200 {object} datafetcher.ControlResponse
+// @Failure 401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /control/pause [post]
+//
+func (h *Handler) HandleControlPause(w http.ResponseWriter, r *http.Request) error {
+  if r.Method != http.MethodPost {
+    return errs.NewError(errs.ErrTypeMethodNotSupported, nil)
+  }
+  h.fetcher.Pause()
+
+  return writeControlResponse(w)
+}
+
+// HandleControlResume
+//
+// @Summary Control resume method
+// @Description Resume method continue paused fetching
+// @Tags Control
+// @Produce application/json
+// @Success 200 {object} datafetcher.ControlResponse
+// @Failure 401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /control/resume [post]
+//
+func (h *Handler) HandleControlResume(w http.ResponseWriter, r *http.Request) error {
+  if r.Method != http.MethodPost {
+    return errs.NewError(errs.ErrTypeMethodNotSupported, nil)
+  }
+  h.fetcher.Resume()
+
+  return writeControlResponse(w)
+}
+
+// HandleControlMode
+//
+// @Summary Control mode method
+// @Description Mode method switch fetching range between total and current mode
+// @Tags Control
+// @Accept application/json
+// @Produce application/json
+// @Param request body datafetcher.ModeRequest true "Request"
+// @Success 200 {object} datafetcher.ControlResponse
+// @Failure 400,401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /control/mode [post]
+//
+func (h *Handler) HandleControlMode(w http.ResponseWriter, r *http.Request) error {
+  if r.Method != http.MethodPost {
+    return errs.NewError(errs.ErrTypeMethodNotSupported, nil)
+  }
+  req := &datafetcher.ModeRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := req.Validate(); err != nil {
+    return err
+  }
+  if err := h.fetcher.SetMode(req.Mode); err != nil {
+    return fmt.Errorf("cannot set fetcher mode: %v", err)
+  }
+  return writeControlResponse(w)
+}
+
+// HandleControlProgress
+//
+// @Summary Control progress method
+// @Description Progress method provide fetcher mode, state and counters of stored entities
+// @Tags Control
+// @Produce application/json
+// @Success 200 {object} datafetcher.ProgressResponse
+// @Failure 401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /control/progress [get]
+//
+func (h *Handler) HandleControlProgress(w http.ResponseWriter, r *http.Request) error {
+  if r.Method != http.MethodGet {
+    return errs.NewError(errs.ErrTypeMethodNotSupported, nil)
+  }
+  progress := h.fetcher.GetProgress()
+
+  resp := &datafetcher.ProgressResponse{
+    Success:  true,
+    Counters: &datafetcher.Counters{},
+  }
+  if err := utils.FillFrom(progress, resp); err != nil {
+    return err
+  }
+  if err := utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+// HandleControlErrors
+//
+// @Summary Control errors method
+// @Description Errors method provide the last fetching errors from the newest
+// @Tags Control
+// @Produce application/json
+// @Param request query datafetcher.ErrorsRequest true "Request"
+// @Success 200 {object} datafetcher.ErrorsResponse
+// @Failure 400,401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /control/errors [get]
+//
+func (h *Handler) HandleControlErrors(w http.ResponseWriter, r *http.Request) error {
+  req := &datafetcher.ErrorsRequest{}
+
+  if err := utils.ReadRequest(r, req); err != nil {
+    return err
+  }
+  if err := req.Validate(); err != nil {
+    return err
+  }
+  fetchErrors := h.fetcher.GetRecentErrors(req.Limit)
+
+  resp := &datafetcher.ErrorsResponse{
+    Success: true,
+    Count:   len(fetchErrors),
+    Errors:  []*datafetcher.FetchError{},
+  }
+  if err := utils.FillFrom(fetchErrors, &resp.Errors); err != nil {
+    return err
+  }
+  if err := utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+// HandleControlSchedule
+//
+// @Summary Control schedule method
+// @Description Schedule method provide refresh plan of stored tickers ordered by priority tier and priority.
+// @Description Tickers with active subscriptions are refreshed on short interval, the others on long one
+// @Tags Control
+// @Produce application/json
+// @Success 200 {object} datafetcher.ScheduleResponse
+// @Failure 401,403,500 {object} errs.Error
+// @Security ApiKeyAuth
+// @Router /control/schedule [get]
+//
+func (h *Handler) HandleControlSchedule(w http.ResponseWriter, r *http.Request) error {
+  if r.Method != http.MethodGet {
+    return errs.NewError(errs.ErrTypeMethodNotSupported, nil)
+  }
+  plan, err := h.fetcher.GetSchedulePlan()
+  if err != nil {
+    return fmt.Errorf("cannot get schedule plan: %v", err)
+  }
+  resp := &datafetcher.ScheduleResponse{
+    Success: true,
+    Count:   len(plan),
+    Plan:    []*datafetcher.ScheduledTicker{},
+  }
+  if err = utils.FillFrom(plan, &resp.Plan); err != nil {
+    return err
+  }
+  for _, planned := range resp.Plan {
+    if planned.Due {
+      resp.Due++
+    }
+  }
+  if err = utils.WriteResponse(w, resp, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
+
+func writeControlResponse(w http.ResponseWriter) error {
+  if err := utils.WriteResponse(w, &datafetcher.ControlResponse{
+    Success: true,
+  }, http.StatusOK); err != nil {
+    return err
+  }
+  return nil
+}
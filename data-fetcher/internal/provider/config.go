@@ -0,0 +1,10 @@
+package provider
+
+type Config struct {
+  Name     string `yaml:"name" required:"true"`
+  ApiToken string `yaml:"api_token"`
+  DumpPath string `yaml:"dump_path"`
+  // http mode of the polygon client: live, record or replay
+  HttpMode    string `yaml:"http_mode"`
+  RecordsPath string `yaml:"records_path"`
+}
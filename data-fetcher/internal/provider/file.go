@@ -0,0 +1,294 @@
+package provider
+
+import (
+  "encoding/csv"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "io"
+  "net/url"
+  "os"
+  "path"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/utils"
+)
+
+const (
+  fileTickers       = "tickers.json"
+  dirTickerDetails  = "details"
+  dirAggregates     = "aggregates"
+  dirBranding       = "branding"
+  dirSplits         = "splits"
+  dirDividends      = "dividends"
+  dirFinancials     = "financials"
+  fileTickersPageSz = 100
+)
+
+// fileProvider serve market data from vendor dump directory:
+//
+//  tickers.json            json array of tickers
+//  details/<ticker>.json   ticker details
+//  aggregates/<ticker>.csv raw daily bars with header timestamp,open,high,low,close,volume
+//  aggregates/<multiplier><timespan>/<ticker>.csv raw bars of other granularity, e.g. aggregates/5minute/AAPL.csv
+//  branding/<image>        branding images by name from image URL
+//  splits/<ticker>.json    optional json array of splits
+//  dividends/<ticker>.json optional json array of dividends
+//  financials/<ticker>.json optional json array of financials
+//
+// cursor of the tickers page is the offset in tickers list. dump may lack the active flag,
+// so all tickers are listed as active and only the delisted ones as inactive
+type fileProvider struct {
+  dumpPath string
+}
+
+func newFileProvider(config *Config) (MarketDataProvider, error) {
+  if config.DumpPath == "" {
+    return nil, fmt.Errorf("dump path must be specified for %s provider", NameFile)
+  }
+  dumpStat, err := os.Stat(config.DumpPath)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get stat about dump path '%s': %v", config.DumpPath, err)
+  }
+  if !dumpStat.IsDir() {
+    return nil, fmt.Errorf("dump path '%s' is not a directory", config.DumpPath)
+  }
+  return &fileProvider{
+    dumpPath: config.DumpPath,
+  }, nil
+}
+
+func (p *fileProvider) Name() string {
+  return NameFile
+}
+
+func (p *fileProvider) ListTickers(option *ListTickersOption) (*TickersPage, error) {
+  var tickers []*Ticker
+
+  if err := p.readJSON(fileTickers, &tickers); err != nil {
+    return nil, err
+  }
+  if option.TickerId != "" || option.Inactive {
+    filtered := make([]*Ticker, 0, len(tickers))
+
+    for _, ticker := range tickers {
+      if ticker == nil || option.TickerId != "" && ticker.Ticker != option.TickerId {
+        continue
+      }
+      if option.Inactive && ticker.Active {
+        continue
+      }
+      filtered = append(filtered, ticker)
+    }
+    tickers = filtered
+  }
+  var offset int
+
+  if option.Cursor != "" {
+    var err error
+    if offset, err = strconv.Atoi(option.Cursor); err != nil || offset < 0 {
+      return nil, fmt.Errorf("malformed tickers cursor '%s'", option.Cursor)
+    }
+  }
+  if offset >= len(tickers) {
+    return &TickersPage{}, nil
+  }
+  limit := offset + fileTickersPageSz
+
+  page := &TickersPage{}
+  if limit < len(tickers) {
+    page.NextCursor = strconv.Itoa(limit)
+  } else {
+    limit = len(tickers)
+  }
+  page.Tickers = tickers[offset:limit]
+
+  return page, nil
+}
+
+func (p *fileProvider) GetTickerDetails(tickerId string) (*TickerDetails, error) {
+  details := &TickerDetails{}
+
+  if err := p.readJSON(filepath.Join(dirTickerDetails, tickerFileName(tickerId, ".json")), details); err != nil {
+    return nil, err
+  }
+  if details.Ticker == "" {
+    details.Ticker = tickerId
+  }
+  return details, nil
+}
+
+func (p *fileProvider) GetAggregates(option *AggregatesOption) (*AggregatesPage, error) {
+  file, err := os.Open(filepath.Join(p.dumpPath, aggregatesFileName(option)))
+  if err != nil {
+    if errors.Is(err, os.ErrNotExist) {
+      return &AggregatesPage{}, nil
+    }
+    return nil, fmt.Errorf("cannot open aggregates file: %v", err)
+  }
+  defer file.Close()
+
+  bars, err := readBarsCSV(file)
+  if err != nil {
+    return nil, fmt.Errorf("cannot read aggregates for ticker '%s': %v", option.TickerId, err)
+  }
+  // range borders are dates, so include whole day of the right border
+  from := option.From.Truncate(24 * time.Hour)
+  to := option.To.Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+  page := &AggregatesPage{}
+
+  for _, bar := range bars {
+    barTime := utils.TimestampToTimeUTC(bar.Timestamp)
+
+    if barTime.Before(from) || !barTime.Before(to) {
+      continue
+    }
+    page.Bars = append(page.Bars, bar)
+  }
+  return page, nil
+}
+
+func (p *fileProvider) GetBrandingImage(imageURL string) ([]byte, error) {
+  parsed, err := url.Parse(imageURL)
+  if err != nil {
+    return nil, fmt.Errorf("cannot parse image url '%s': %v", imageURL, err)
+  }
+  imageName := path.Base(parsed.Path)
+
+  content, err := os.ReadFile(filepath.Join(p.dumpPath, dirBranding, imageName))
+  if err != nil {
+    return nil, fmt.Errorf("cannot read branding image '%s': %v", imageName, err)
+  }
+  return content, nil
+}
+
+func (p *fileProvider) GetSplits(tickerId string) ([]*Split, error) {
+  var splits []*Split
+
+  if err := p.readOptionalJSON(filepath.Join(dirSplits, tickerFileName(tickerId, ".json")), &splits); err != nil {
+    return nil, err
+  }
+  return splits, nil
+}
+
+func (p *fileProvider) GetDividends(tickerId string) ([]*Dividend, error) {
+  var dividends []*Dividend
+
+  if err := p.readOptionalJSON(filepath.Join(dirDividends, tickerFileName(tickerId, ".json")), &dividends); err != nil {
+    return nil, err
+  }
+  return dividends, nil
+}
+
+func (p *fileProvider) GetFinancials(tickerId string) ([]*Financial, error) {
+  var financials []*Financial
+
+  if err := p.readOptionalJSON(filepath.Join(dirFinancials, tickerFileName(tickerId, ".json")), &financials); err != nil {
+    return nil, err
+  }
+  return financials, nil
+}
+
+// readOptionalJSON do not fail if dump file does not exist
+func (p *fileProvider) readOptionalJSON(name string, dest any) error {
+  if _, err := os.Stat(filepath.Join(p.dumpPath, name)); errors.Is(err, os.ErrNotExist) {
+    return nil
+  }
+  return p.readJSON(name, dest)
+}
+
+func (p *fileProvider) readJSON(name string, dest any) error {
+  content, err := os.ReadFile(filepath.Join(p.dumpPath, name))
+  if err != nil {
+    return fmt.Errorf("cannot read dump file '%s': %v", name, err)
+  }
+  if err = json.Unmarshal(content, dest); err != nil {
+    return fmt.Errorf("cannot unmarshal dump file '%s': %v", name, err)
+  }
+  return nil
+}
+
+// aggregatesFileName return dump file of the bars with requested granularity.
+// granularity without file has no bars, so daily bars are not served as intraday
+func aggregatesFileName(option *AggregatesOption) string {
+  fileName := tickerFileName(option.TickerId, ".csv")
+
+  if option.Timespan == TimespanDay && option.Multiplier == 1 {
+    return filepath.Join(dirAggregates, fileName)
+  }
+  return filepath.Join(dirAggregates, fmt.Sprint(option.Multiplier, option.Timespan), fileName)
+}
+
+func tickerFileName(tickerId, extension string) string {
+  // tickers like 'BRK.A' are safe, but path separators are not
+  return fmt.Sprint(strings.ReplaceAll(tickerId, "/", "_"), extension)
+}
+
+func readBarsCSV(reader io.Reader) ([]*Bar, error) {
+  const (
+    colTimestamp = iota
+    colOpen
+    colHigh
+    colLow
+    colClose
+    colVolume
+    colsCount
+  )
+  csvReader := csv.NewReader(reader)
+  csvReader.FieldsPerRecord = colsCount
+
+  records, err := csvReader.ReadAll()
+  if err != nil {
+    return nil, fmt.Errorf("cannot read csv: %v", err)
+  }
+  const headerRows = 1
+
+  if len(records) < headerRows {
+    return nil, nil
+  }
+  bars := make([]*Bar, 0, len(records)-headerRows)
+
+  for rowIdx, record := range records[headerRows:] {
+    timestamp, err := parseBarTimestamp(record[colTimestamp])
+    if err != nil {
+      return nil, fmt.Errorf("row %d: %v", rowIdx+headerRows, err)
+    }
+    values := make([]float64, 0, colsCount-colOpen)
+
+    for _, field := range record[colOpen:] {
+      value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+      if err != nil {
+        return nil, fmt.Errorf("row %d: malformed value '%s'", rowIdx+headerRows, field)
+      }
+      values = append(values, value)
+    }
+    bars = append(bars, &Bar{
+      Timestamp: timestamp,
+      Open:      values[colOpen-colOpen],
+      Highest:   values[colHigh-colOpen],
+      Lowest:    values[colLow-colOpen],
+      Close:     values[colClose-colOpen],
+      Volume:    values[colVolume-colOpen],
+    })
+  }
+  return bars, nil
+}
+
+// parseBarTimestamp accept epoch milliseconds, RFC3339 time or date
+func parseBarTimestamp(value string) (int64, error) {
+  value = strings.TrimSpace(value)
+
+  if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+    return timestamp, nil
+  }
+  for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+    if t, err := time.Parse(layout, value); err == nil {
+      return t.UTC().UnixMilli(), nil
+    }
+  }
+  return 0, fmt.Errorf("malformed timestamp '%s'", value)
+}
@@ -0,0 +1,307 @@
+package provider
+
+import (
+  "fmt"
+  "testing"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/utils"
+)
+
+// testdata/dump contain 205 tickers T000-T204, each 50th of them is delisted
+const fileDumpPath = "testdata/dump"
+
+func newTestFileProvider(t *testing.T) MarketDataProvider {
+  t.Helper()
+
+  provider, err := newFileProvider(&Config{
+    Name:     NameFile,
+    DumpPath: fileDumpPath,
+  })
+  if err != nil {
+    t.Fatalf("cannot create file provider: %v", err)
+  }
+  return provider
+}
+
+func TestFileProviderListTickers(t *testing.T) {
+  provider := newTestFileProvider(t)
+
+  testCases := []struct {
+    name       string
+    option     *ListTickersOption
+    wantErr    bool
+    wantFirst  string
+    wantCount  int
+    wantCursor string
+  }{
+    {
+      name:       "first page",
+      option:     &ListTickersOption{},
+      wantFirst:  "T000",
+      wantCount:  fileTickersPageSz,
+      wantCursor: "100",
+    },
+    {
+      name:       "second page",
+      option:     &ListTickersOption{Cursor: "100"},
+      wantFirst:  "T100",
+      wantCount:  fileTickersPageSz,
+      wantCursor: "200",
+    },
+    {
+      name:      "last page",
+      option:    &ListTickersOption{Cursor: "200"},
+      wantFirst: "T200",
+      wantCount: 5,
+    },
+    {
+      name:   "cursor out of tickers",
+      option: &ListTickersOption{Cursor: "300"},
+    },
+    {
+      name:      "ticker id",
+      option:    &ListTickersOption{TickerId: "T150"},
+      wantFirst: "T150",
+      wantCount: 1,
+    },
+    {
+      name:   "unknown ticker id",
+      option: &ListTickersOption{TickerId: "UNKNOWN"},
+    },
+    {
+      name:      "delisted tickers",
+      option:    &ListTickersOption{Inactive: true},
+      wantFirst: "T049",
+      wantCount: 4,
+    },
+    {
+      name:   "active ticker id is not delisted",
+      option: &ListTickersOption{TickerId: "T150", Inactive: true},
+    },
+    {
+      name:    "malformed cursor",
+      option:  &ListTickersOption{Cursor: "abc"},
+      wantErr: true,
+    },
+    {
+      name:    "negative cursor",
+      option:  &ListTickersOption{Cursor: "-1"},
+      wantErr: true,
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      page, err := provider.ListTickers(testCase.option)
+      if testCase.wantErr {
+        if err == nil {
+          t.Fatalf("expected error, got page with %d tickers", len(page.Tickers))
+        }
+        return
+      }
+      if err != nil {
+        t.Fatalf("cannot list tickers: %v", err)
+      }
+      if len(page.Tickers) != testCase.wantCount {
+        t.Fatalf("expected %d tickers, got %d", testCase.wantCount, len(page.Tickers))
+      }
+      if testCase.wantCount != 0 && page.Tickers[0].Ticker != testCase.wantFirst {
+        t.Errorf("expected first ticker '%s', got '%s'", testCase.wantFirst, page.Tickers[0].Ticker)
+      }
+      if page.NextCursor != testCase.wantCursor {
+        t.Errorf("expected next cursor '%s', got '%s'", testCase.wantCursor, page.NextCursor)
+      }
+    })
+  }
+}
+
+func TestFileProviderListAllTickers(t *testing.T) {
+  provider := newTestFileProvider(t)
+
+  var (
+    cursor string
+    listed []string
+  )
+  for {
+    page, err := provider.ListTickers(&ListTickersOption{Cursor: cursor})
+    if err != nil {
+      t.Fatalf("cannot list tickers: %v", err)
+    }
+    for _, ticker := range page.Tickers {
+      listed = append(listed, ticker.Ticker)
+    }
+    if page.NextCursor == "" {
+      break
+    }
+    cursor = page.NextCursor
+  }
+  if len(listed) != 205 {
+    t.Fatalf("expected 205 tickers, got %d", len(listed))
+  }
+  for idx, tickerId := range listed {
+    if expected := fmt.Sprintf("T%03d", idx); tickerId != expected {
+      t.Fatalf("expected ticker '%s' at %d, got '%s'", expected, idx, tickerId)
+    }
+  }
+}
+
+func TestFileProviderGetAggregates(t *testing.T) {
+  provider := newTestFileProvider(t)
+
+  date := func(day int) time.Time {
+    return time.Date(2023, 3, day, 0, 0, 0, 0, time.UTC)
+  }
+  testCases := []struct {
+    name     string
+    option   *AggregatesOption
+    wantErr  bool
+    wantDays []string
+  }{
+    {
+      name: "whole file",
+      option: &AggregatesOption{
+        TickerId: "AAPL", Timespan: TimespanDay, Multiplier: 1,
+        From: date(1).AddDate(0, 0, -7), To: date(10),
+      },
+      wantDays: []string{"2023-02-28", "2023-03-01", "2023-03-02", "2023-03-03", "2023-03-06"},
+    },
+    {
+      name: "range borders are included",
+      option: &AggregatesOption{
+        TickerId: "AAPL", Timespan: TimespanDay, Multiplier: 1,
+        From: date(1), To: date(3),
+      },
+      wantDays: []string{"2023-03-01", "2023-03-02", "2023-03-03"},
+    },
+    {
+      name: "time of the range borders is ignored",
+      option: &AggregatesOption{
+        TickerId: "AAPL", Timespan: TimespanDay, Multiplier: 1,
+        From: date(2).Add(15 * time.Hour), To: date(2).Add(time.Hour),
+      },
+      wantDays: []string{"2023-03-02"},
+    },
+    {
+      name: "range without bars",
+      option: &AggregatesOption{
+        TickerId: "AAPL", Timespan: TimespanDay, Multiplier: 1,
+        From: date(4), To: date(5),
+      },
+    },
+    {
+      name: "granularity directory",
+      option: &AggregatesOption{
+        TickerId: "AAPL", Timespan: TimespanMinute, Multiplier: 5,
+        From: date(1), To: date(1),
+      },
+      wantDays: []string{"2023-03-01", "2023-03-01"},
+    },
+    {
+      name: "granularity without file do not fall back to daily bars",
+      option: &AggregatesOption{
+        TickerId: "AAPL", Timespan: TimespanHour, Multiplier: 1,
+        From: date(1), To: date(10),
+      },
+    },
+    {
+      name: "multiplier of the daily bars is not default",
+      option: &AggregatesOption{
+        TickerId: "AAPL", Timespan: TimespanDay, Multiplier: 2,
+        From: date(1), To: date(10),
+      },
+    },
+    {
+      name: "ticker without file",
+      option: &AggregatesOption{
+        TickerId: "MSFT", Timespan: TimespanDay, Multiplier: 1,
+        From: date(1), To: date(10),
+      },
+    },
+    {
+      name: "malformed value",
+      option: &AggregatesOption{
+        TickerId: "BADVALUE", Timespan: TimespanDay, Multiplier: 1,
+        From: date(1), To: date(10),
+      },
+      wantErr: true,
+    },
+    {
+      name: "malformed timestamp",
+      option: &AggregatesOption{
+        TickerId: "BADTIME", Timespan: TimespanDay, Multiplier: 1,
+        From: date(1), To: date(10),
+      },
+      wantErr: true,
+    },
+    {
+      name: "missing field",
+      option: &AggregatesOption{
+        TickerId: "BADFIELDS", Timespan: TimespanDay, Multiplier: 1,
+        From: date(1), To: date(10),
+      },
+      wantErr: true,
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      page, err := provider.GetAggregates(testCase.option)
+      if testCase.wantErr {
+        if err == nil {
+          t.Fatalf("expected error, got page with %d bars", len(page.Bars))
+        }
+        return
+      }
+      if err != nil {
+        t.Fatalf("cannot get aggregates: %v", err)
+      }
+      if page.NextCursor != "" {
+        t.Errorf("expected single page, got next cursor '%s'", page.NextCursor)
+      }
+      days := make([]string, 0, len(page.Bars))
+
+      for _, bar := range page.Bars {
+        days = append(days, utils.TimestampToTimeUTC(bar.Timestamp).Format("2006-01-02"))
+      }
+      if fmt.Sprint(days) != fmt.Sprint(testCase.wantDays) {
+        t.Errorf("expected bars of %v, got %v", testCase.wantDays, days)
+      }
+    })
+  }
+}
+
+func TestFileProviderGetAggregatesValues(t *testing.T) {
+  provider := newTestFileProvider(t)
+
+  page, err := provider.GetAggregates(&AggregatesOption{
+    TickerId:   "AAPL",
+    Timespan:   TimespanDay,
+    Multiplier: 1,
+    From:       time.Date(2023, 3, 3, 0, 0, 0, 0, time.UTC),
+    To:         time.Date(2023, 3, 3, 0, 0, 0, 0, time.UTC),
+  })
+  if err != nil {
+    t.Fatalf("cannot get aggregates: %v", err)
+  }
+  if len(page.Bars) != 1 {
+    t.Fatalf("expected 1 bar, got %d", len(page.Bars))
+  }
+  expected := Bar{
+    Open:      148.04,
+    Close:     151.03,
+    Highest:   151.11,
+    Lowest:    147.33,
+    Timestamp: 1677801600000,
+    Volume:    70732300,
+  }
+  if *page.Bars[0] != expected {
+    t.Errorf("expected bar %+v, got %+v", expected, *page.Bars[0])
+  }
+}
+
+func TestNewFileProvider(t *testing.T) {
+  for _, dumpPath := range []string{"", "testdata/not-found", "testdata/dump/tickers.json"} {
+    if _, err := newFileProvider(&Config{Name: NameFile, DumpPath: dumpPath}); err == nil {
+      t.Errorf("expected error on dump path '%s'", dumpPath)
+    }
+  }
+}
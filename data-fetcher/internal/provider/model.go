@@ -0,0 +1,152 @@
+package provider
+
+import "time"
+
+const (
+  TimespanMinute  = "minute"
+  TimespanHour    = "hour"
+  TimespanDay     = "day"
+  TimespanWeek    = "week"
+  TimespanMonth   = "month"
+  TimespanQuarter = "quarter"
+  TimespanYear    = "year"
+)
+
+func ValidTimespan(timespan string) bool {
+  switch timespan {
+  case TimespanMinute, TimespanHour, TimespanDay, TimespanWeek, TimespanMonth, TimespanQuarter, TimespanYear:
+    return true
+  default:
+    return false
+  }
+}
+
+// models have the same json shape as polygon results,
+// so polygon dumps can be served by the file provider as is
+
+type Ticker struct {
+  Ticker         string    `json:"ticker"`
+  Name           string    `json:"name"`
+  Market         string    `json:"market"`
+  Locale         string    `json:"locale"`
+  Type           string    `json:"type"`
+  Cik            string    `json:"cik"`
+  Active         bool      `json:"active"`
+  CurrencyName   string    `json:"currency_name"`
+  LastUpdatedUtc time.Time `json:"last_updated_utc"`
+}
+
+type TickerDetails struct {
+  Active          bool            `json:"active"`
+  Address         *TickerAddress  `json:"address"`
+  Branding        *TickerBranding `json:"branding"`
+  Cik             string          `json:"cik"`
+  CurrencyName    string          `json:"currency_name"`
+  Description     string          `json:"description"`
+  HomepageUrl     string          `json:"homepage_url"`
+  ListDate        string          `json:"list_date"`
+  Locale          string          `json:"locale"`
+  Market          string          `json:"market"`
+  Name            string          `json:"name"`
+  PhoneNumber     string          `json:"phone_number"`
+  PrimaryExchange string          `json:"primary_exchange"`
+  SicCode         string          `json:"sic_code"`
+  SicDescription  string          `json:"sic_description"`
+  Ticker          string          `json:"ticker"`
+  TickerRoot      string          `json:"ticker_root"`
+  TotalEmployees  int             `json:"total_employees"`
+}
+
+type TickerAddress struct {
+  Address1   string `json:"address1"`
+  City       string `json:"city"`
+  PostalCode string `json:"postal_code"`
+  State      string `json:"state"`
+}
+
+type TickerBranding struct {
+  IconUrl string `json:"icon_url"`
+  LogoUrl string `json:"logo_url"`
+}
+
+type Bar struct {
+  Open      float64 `json:"o"`
+  Close     float64 `json:"c"`
+  Highest   float64 `json:"h"`
+  Lowest    float64 `json:"l"`
+  Timestamp int64   `json:"t"`
+  Volume    float64 `json:"v"`
+}
+
+// Split of the ticker shares, split_to shares replace split_from shares
+type Split struct {
+  ExecutionDate string  `json:"execution_date"`
+  SplitFrom     float64 `json:"split_from"`
+  SplitTo       float64 `json:"split_to"`
+  Ticker        string  `json:"ticker"`
+}
+
+type Dividend struct {
+  CashAmount      float64 `json:"cash_amount"`
+  Currency        string  `json:"currency"`
+  DeclarationDate string  `json:"declaration_date"`
+  DividendType    string  `json:"dividend_type"`
+  ExDividendDate  string  `json:"ex_dividend_date"`
+  Frequency       int     `json:"frequency"`
+  PayDate         string  `json:"pay_date"`
+  RecordDate      string  `json:"record_date"`
+  Ticker          string  `json:"ticker"`
+}
+
+// Financial is financial statements of the ticker for the fiscal period
+type Financial struct {
+  StartDate    string               `json:"start_date"`
+  EndDate      string               `json:"end_date"`
+  FilingDate   string               `json:"filing_date"`
+  FiscalPeriod string               `json:"fiscal_period"`
+  FiscalYear   string               `json:"fiscal_year"`
+  Timeframe    string               `json:"timeframe"`
+  Financials   *FinancialStatements `json:"financials"`
+}
+
+type FinancialStatements struct {
+  IncomeStatement *IncomeStatement `json:"income_statement"`
+}
+
+type IncomeStatement struct {
+  Revenues                *FinancialValue `json:"revenues"`
+  NetIncomeLoss           *FinancialValue `json:"net_income_loss"`
+  BasicEarningsPerShare   *FinancialValue `json:"basic_earnings_per_share"`
+  DilutedEarningsPerShare *FinancialValue `json:"diluted_earnings_per_share"`
+  BasicAverageShares      *FinancialValue `json:"basic_average_shares"`
+}
+
+type FinancialValue struct {
+  Value float64 `json:"value"`
+  Unit  string  `json:"unit"`
+}
+
+type ListTickersOption struct {
+  TickerId string
+  Inactive bool   // list delisted tickers instead of the active ones
+  Cursor   string // opaque provider cursor of the requested page
+}
+
+type TickersPage struct {
+  Tickers    []*Ticker
+  NextCursor string
+}
+
+type AggregatesOption struct {
+  TickerId   string
+  Multiplier int
+  Timespan   string
+  From       time.Time
+  To         time.Time
+  Cursor     string // opaque provider cursor of the requested page
+}
+
+type AggregatesPage struct {
+  Bars       []*Bar
+  NextCursor string
+}
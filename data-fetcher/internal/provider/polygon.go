@@ -0,0 +1,303 @@
+package provider
+
+import (
+  "context"
+  "fmt"
+  "net/url"
+  "strconv"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/httpclient"
+)
+
+const (
+  polygonReqsLimit   = 5
+  polygonReqPerDur   = 1 * time.Minute
+  polygonWaitDur     = 10 * time.Second
+  polygonDeadlineDur = 120 * time.Second
+)
+
+const (
+  respCursorKey = "cursor"
+  respStatusOK  = "OK"
+)
+
+const (
+  basePrefixApi = "https://api.polygon.io"
+
+  tickersApi       = "/v3/reference/tickers"
+  stocksApi        = "/v2/aggs/ticker/%s/range/%d/%s/%s/%s"
+  tickerDetailsApi = "/v3/reference/tickers/%s"
+  splitsApi        = "/v3/reference/splits"
+  dividendsApi     = "/v3/reference/dividends"
+  financialsApi    = "/vX/reference/financials"
+
+  apiTokenKey = "apiKey"
+)
+
+// polygonProvider use full request URL of the page as cursor
+type polygonProvider struct {
+  client httpclient.HttpClient
+}
+
+func newPolygonProvider(ctx context.Context, config *Config) (MarketDataProvider, error) {
+  replay := config.HttpMode == httpclient.ModeReplay
+
+  // replayed responses do not require api token
+  if config.ApiToken == "" && !replay {
+    return nil, fmt.Errorf("api token must be specified for %s provider", NamePolygon)
+  }
+  if config.HttpMode != "" && config.HttpMode != httpclient.ModeLive && config.RecordsPath == "" {
+    return nil, fmt.Errorf("records path must be specified for '%s' http mode", config.HttpMode)
+  }
+  // api token is not written to records
+  transport, err := httpclient.NewModeTransport(config.HttpMode, config.RecordsPath, apiTokenKey)
+  if err != nil {
+    return nil, fmt.Errorf("cannot create http transport: %v", err)
+  }
+  options := []httpclient.Options{
+    httpclient.WithContext(ctx),
+    httpclient.WithQueryApiToken(
+      apiTokenKey,
+      config.ApiToken,
+    ),
+    httpclient.WithTransport(transport),
+  }
+  // replay does not send requests, so it is not limited
+  if !replay {
+    options = append(options, httpclient.WithRequestsLimit(
+      polygonReqsLimit,
+      polygonReqPerDur,
+      polygonWaitDur,
+      polygonDeadlineDur,
+    ))
+  }
+  client := httpclient.NewClient(options...)
+
+  return &polygonProvider{
+    client: client,
+  }, nil
+}
+
+func (p *polygonProvider) Name() string {
+  return NamePolygon
+}
+
+func (p *polygonProvider) ListTickers(option *ListTickersOption) (*TickersPage, error) {
+  query := buildTickersQuery(option.TickerId, option.Inactive)
+  reqURL := option.Cursor
+
+  if reqURL == "" {
+    reqURL = buildTickersReqURL(query)
+  }
+  resp, err := p.client.Get(reqURL, nil)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get response: %v", err)
+  }
+  tickersResp := &polygonTickersResponse{}
+
+  if err = p.client.ParseResponse(resp, tickersResp); err != nil {
+    return nil, fmt.Errorf("cannot parse response: %v", err)
+  }
+  if tickersResp.Status != respStatusOK {
+    return nil, fmt.Errorf("bad response status: %s", tickersResp.Status)
+  }
+  page := &TickersPage{
+    Tickers: tickersResp.Results,
+  }
+  if tickersResp.Count == 0 || tickersResp.NextUrl == "" {
+    return page, nil
+  }
+  cursor, err := url.Parse(tickersResp.NextUrl)
+  if err != nil {
+    return nil, fmt.Errorf("cannot parse cursor URL: %s", tickersResp.NextUrl)
+  }
+  cursorValue := cursor.Query().Get(respCursorKey)
+  if cursorValue == "" {
+    return page, nil
+  }
+  query.Set(respCursorKey, cursorValue)
+  page.NextCursor = buildTickersReqURL(query)
+
+  return page, nil
+}
+
+// buildTickersQuery return query of the active or the delisted tickers, polygon does not list both at once
+func buildTickersQuery(tickerId string, inactive bool) url.Values {
+  query := url.Values{}
+
+  query.Add("active", strconv.FormatBool(!inactive))
+  query.Add("order", "asc")
+
+  if tickerId != "" {
+    query.Add("ticker", tickerId)
+  }
+  return query
+}
+
+func buildTickersReqURL(query url.Values) string {
+  return fmt.Sprint(basePrefixApi, tickersApi, "?", query.Encode())
+}
+
+func (p *polygonProvider) GetTickerDetails(tickerId string) (*TickerDetails, error) {
+  tickerDetailsQuery := fmt.Sprintf(tickerDetailsApi, tickerId)
+  reqURL := fmt.Sprint(basePrefixApi, tickerDetailsQuery)
+
+  resp, err := p.client.Get(reqURL, nil)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get response: %v", err)
+  }
+  tickerDetailsResp := &polygonTickerDetailsResponse{}
+
+  if err = p.client.ParseResponse(resp, tickerDetailsResp); err != nil {
+    return nil, fmt.Errorf("cannot parse response: %v", err)
+  }
+  if tickerDetailsResp.Status != respStatusOK {
+    return nil, fmt.Errorf("bad response status: %s", tickerDetailsResp.Status)
+  }
+  if tickerDetailsResp.Results == nil {
+    return nil, fmt.Errorf("ticker details results not found")
+  }
+  return tickerDetailsResp.Results, nil
+}
+
+func (p *polygonProvider) GetAggregates(option *AggregatesOption) (*AggregatesPage, error) {
+  reqURL := option.Cursor
+
+  if reqURL == "" {
+    reqURL = buildAggregatesReqURL(option)
+  }
+  resp, err := p.client.Get(reqURL, nil)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get response: %v", err)
+  }
+  aggregatesResp := &polygonAggregatesResponse{}
+
+  if err = p.client.ParseResponse(resp, aggregatesResp); err != nil {
+    return nil, fmt.Errorf("cannot parse reponse: %v", err)
+  }
+  if aggregatesResp.QueryCount == 0 && aggregatesResp.Count == 0 {
+    return &AggregatesPage{}, nil
+  }
+  // adjusted series are calculated from raw bars and stored splits
+  if aggregatesResp.Adjusted {
+    return nil, fmt.Errorf("unexpected adjusted aggregates for ticker '%s'", option.TickerId)
+  }
+  return &AggregatesPage{
+    Bars:       aggregatesResp.Results,
+    NextCursor: aggregatesResp.NextURL,
+  }, nil
+}
+
+func buildAggregatesReqURL(option *AggregatesOption) string {
+  const dateFormat = "2006-01-02"
+
+  from := option.From.Format(dateFormat)
+  to := option.To.Format(dateFormat)
+
+  query := url.Values{}
+  query.Add("adjusted", "false")
+
+  rangeQuery := fmt.Sprintf(stocksApi, option.TickerId, option.Multiplier, option.Timespan, from, to)
+  reqURL := fmt.Sprint(basePrefixApi, rangeQuery, "?", query.Encode())
+  return reqURL
+}
+
+func (p *polygonProvider) GetBrandingImage(imageURL string) ([]byte, error) {
+  imageResp, err := p.client.GetFullResp(imageURL, nil)
+  if err != nil {
+    return nil, fmt.Errorf("cannot get image response: %v", err)
+  }
+  return imageResp.Content, nil
+}
+
+func (p *polygonProvider) GetSplits(tickerId string) ([]*Split, error) {
+  var splits []*Split
+  reqURL := buildCorporateActionsReqURL(splitsApi, tickerId)
+
+  for reqURL != "" {
+    resp, err := p.client.Get(reqURL, nil)
+    if err != nil {
+      return nil, fmt.Errorf("cannot get response: %v", err)
+    }
+    splitsResp := &polygonSplitsResponse{}
+
+    if err = p.client.ParseResponse(resp, splitsResp); err != nil {
+      return nil, fmt.Errorf("cannot parse response: %v", err)
+    }
+    if splitsResp.Status != respStatusOK {
+      return nil, fmt.Errorf("bad response status: %s", splitsResp.Status)
+    }
+    splits = append(splits, splitsResp.Results...)
+    reqURL = splitsResp.NextUrl
+  }
+  return splits, nil
+}
+
+func (p *polygonProvider) GetDividends(tickerId string) ([]*Dividend, error) {
+  var dividends []*Dividend
+  reqURL := buildCorporateActionsReqURL(dividendsApi, tickerId)
+
+  for reqURL != "" {
+    resp, err := p.client.Get(reqURL, nil)
+    if err != nil {
+      return nil, fmt.Errorf("cannot get response: %v", err)
+    }
+    dividendsResp := &polygonDividendsResponse{}
+
+    if err = p.client.ParseResponse(resp, dividendsResp); err != nil {
+      return nil, fmt.Errorf("cannot parse response: %v", err)
+    }
+    if dividendsResp.Status != respStatusOK {
+      return nil, fmt.Errorf("bad response status: %s", dividendsResp.Status)
+    }
+    dividends = append(dividends, dividendsResp.Results...)
+    reqURL = dividendsResp.NextUrl
+  }
+  return dividends, nil
+}
+
+func (p *polygonProvider) GetFinancials(tickerId string) ([]*Financial, error) {
+  var financials []*Financial
+  reqURL := buildFinancialsReqURL(tickerId)
+
+  for reqURL != "" {
+    resp, err := p.client.Get(reqURL, nil)
+    if err != nil {
+      return nil, fmt.Errorf("cannot get response: %v", err)
+    }
+    financialsResp := &polygonFinancialsResponse{}
+
+    if err = p.client.ParseResponse(resp, financialsResp); err != nil {
+      return nil, fmt.Errorf("cannot parse response: %v", err)
+    }
+    if financialsResp.Status != respStatusOK {
+      return nil, fmt.Errorf("bad response status: %s", financialsResp.Status)
+    }
+    financials = append(financials, financialsResp.Results...)
+    reqURL = financialsResp.NextUrl
+  }
+  return financials, nil
+}
+
+func buildFinancialsReqURL(tickerId string) string {
+  const pageLimit = "100"
+
+  query := url.Values{}
+  query.Add("ticker", tickerId)
+  query.Add("limit", pageLimit)
+  query.Add("order", "asc")
+  query.Add("sort", "period_of_report_date")
+
+  return fmt.Sprint(basePrefixApi, financialsApi, "?", query.Encode())
+}
+
+func buildCorporateActionsReqURL(api, tickerId string) string {
+  const pageLimit = "1000"
+
+  query := url.Values{}
+  query.Add("ticker", tickerId)
+  query.Add("limit", pageLimit)
+
+  return fmt.Sprint(basePrefixApi, api, "?", query.Encode())
+}
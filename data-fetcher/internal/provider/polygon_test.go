@@ -0,0 +1,95 @@
+package provider
+
+import (
+  "context"
+  "testing"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/httpclient"
+)
+
+// testdata/polygon contain responses recorded with scrubbed api key,
+// the first page of each request has next_url of the second one
+const polygonRecordsPath = "testdata/polygon"
+
+func newReplayPolygonProvider(t *testing.T) MarketDataProvider {
+  t.Helper()
+
+  provider, err := newPolygonProvider(context.Background(), &Config{
+    Name:        NamePolygon,
+    HttpMode:    httpclient.ModeReplay,
+    RecordsPath: polygonRecordsPath,
+  })
+  if err != nil {
+    t.Fatalf("cannot create replay polygon provider: %v", err)
+  }
+  return provider
+}
+
+func TestPolygonSplitsPagination(t *testing.T) {
+  provider := newReplayPolygonProvider(t)
+
+  splits, err := provider.GetSplits("AAPL")
+  if err != nil {
+    t.Fatalf("cannot get splits: %v", err)
+  }
+  expected := []struct {
+    executionDate string
+    splitTo       float64
+  }{
+    {"2005-02-28", 2},
+    {"2014-06-09", 7},
+    {"2020-08-31", 4},
+  }
+  if len(splits) != len(expected) {
+    t.Fatalf("expected %d splits of both pages, got %d", len(expected), len(splits))
+  }
+  for idx, split := range splits {
+    if split.ExecutionDate != expected[idx].executionDate || split.SplitTo != expected[idx].splitTo {
+      t.Errorf("split %d: expected %s 1:%v, got %s %v:%v", idx, expected[idx].executionDate,
+        expected[idx].splitTo, split.ExecutionDate, split.SplitFrom, split.SplitTo)
+    }
+  }
+}
+
+func TestPolygonAggregatesPagination(t *testing.T) {
+  provider := newReplayPolygonProvider(t)
+
+  option := &AggregatesOption{
+    TickerId:   "AAPL",
+    Multiplier: 1,
+    Timespan:   TimespanDay,
+    From:       time.Date(2023, time.January, 3, 0, 0, 0, 0, time.UTC),
+    To:         time.Date(2023, time.January, 6, 0, 0, 0, 0, time.UTC),
+  }
+  var (
+    bars  []*Bar
+    pages int
+  )
+  for {
+    page, err := provider.GetAggregates(option)
+    if err != nil {
+      t.Fatalf("cannot get aggregates page %d: %v", pages+1, err)
+    }
+    pages++
+    bars = append(bars, page.Bars...)
+
+    if page.NextCursor == "" {
+      break
+    }
+    option.Cursor = page.NextCursor
+  }
+  if pages != 2 {
+    t.Errorf("expected 2 pages, got %d", pages)
+  }
+  expected := []int64{1672722000000, 1672808400000, 1672894800000, 1672981200000}
+
+  if len(bars) != len(expected) {
+    t.Fatalf("expected %d bars, got %d", len(expected), len(bars))
+  }
+  for idx, bar := range bars {
+    if bar.Timestamp != expected[idx] {
+      t.Errorf("bar %d: expected timestamp %d, got %d", idx, expected[idx], bar.Timestamp)
+    }
+  }
+}
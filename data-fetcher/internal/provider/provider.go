@@ -0,0 +1,34 @@
+package provider
+
+import (
+  "context"
+  "fmt"
+)
+
+const (
+  NamePolygon = "polygon"
+  NameFile    = "file"
+)
+
+type MarketDataProvider interface {
+  Name() string
+  ListTickers(option *ListTickersOption) (*TickersPage, error)
+  GetTickerDetails(tickerId string) (*TickerDetails, error)
+  GetAggregates(option *AggregatesOption) (*AggregatesPage, error)
+  GetBrandingImage(imageURL string) ([]byte, error)
+  GetSplits(tickerId string) ([]*Split, error)
+  GetDividends(tickerId string) ([]*Dividend, error)
+  GetFinancials(tickerId string) ([]*Financial, error)
+}
+
+func NewProvider(ctx context.Context, config *Config) (MarketDataProvider, error) {
+  switch config.Name {
+  case NamePolygon:
+    return newPolygonProvider(ctx, config)
+  case NameFile:
+    return newFileProvider(config)
+  default:
+    return nil, fmt.Errorf("unknown market data provider '%s'. possible: %s, %s",
+      config.Name, NamePolygon, NameFile)
+  }
+}
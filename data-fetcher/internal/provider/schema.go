@@ -0,0 +1,43 @@
+package provider
+
+type polygonTickersResponse struct {
+  Results []*Ticker `json:"results"`
+  Status  string    `json:"status"`
+  Count   int       `json:"count"`
+  NextUrl string    `json:"next_url"`
+}
+
+type polygonAggregatesResponse struct {
+  Adjusted     bool   `json:"adjusted"`
+  QueryCount   int    `json:"queryCount"`
+  RequestId    string `json:"request_id"`
+  Results      []*Bar `json:"results"`
+  ResultsCount int    `json:"resultsCount"`
+  Status       string `json:"status"`
+  Ticker       string `json:"ticker"`
+  Count        int    `json:"count"`
+  NextURL      string `json:"next_url"`
+}
+
+type polygonSplitsResponse struct {
+  Results []*Split `json:"results"`
+  Status  string   `json:"status"`
+  NextUrl string   `json:"next_url"`
+}
+
+type polygonDividendsResponse struct {
+  Results []*Dividend `json:"results"`
+  Status  string      `json:"status"`
+  NextUrl string      `json:"next_url"`
+}
+
+type polygonFinancialsResponse struct {
+  Results []*Financial `json:"results"`
+  Status  string       `json:"status"`
+  NextUrl string       `json:"next_url"`
+}
+
+type polygonTickerDetailsResponse struct {
+  Results *TickerDetails `json:"results"`
+  Status  string         `json:"status"`
+}
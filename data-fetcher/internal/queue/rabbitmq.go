@@ -24,6 +24,7 @@ const (
 
 type MediaServiceQueue interface {
   PublishMessage(message *domain.PutMessage) error
+  Close() error
 }
 
 type mediaServiceQueue struct {
@@ -94,3 +95,7 @@ func formPublishingFromMessage(message *domain.PutMessage) (*ampq.Publishing, er
     Timestamp: utils.NotTimeUTC(),
   }, nil
 }
+
+func (msq *mediaServiceQueue) Close() error {
+  return msq.mq.Close()
+}
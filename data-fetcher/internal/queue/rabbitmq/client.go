@@ -2,6 +2,7 @@ package rabbitmq
 
 import (
   "context"
+  "errors"
   "fmt"
 
   "github.com/UshakovN/stock-predictor-service/utils"
@@ -12,6 +13,13 @@ type Client interface {
   QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args ampq.Table) (ampq.Queue, error)
   PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg ampq.Publishing) error
   Consume(queue, cons string, autoAck, excl, noLocal, noWait bool, args ampq.Table) (<-chan ampq.Delivery, error)
+  Close() error
+}
+
+// client hold the connection to close it together with the channel
+type client struct {
+  *ampq.Channel
+  conn *ampq.Connection
 }
 
 func NewClient(config *Config) (Client, error) {
@@ -42,5 +50,18 @@ func NewClient(config *Config) (Client, error) {
     return nil, fmt.Errorf("ampq server channel opening failed: %v", err)
   }
 
-  return ch, nil
+  return &client{
+    Channel: ch,
+    conn:    conn,
+  }, nil
+}
+
+func (c *client) Close() error {
+  if err := c.Channel.Close(); err != nil && !errors.Is(err, ampq.ErrClosed) {
+    return fmt.Errorf("cannot close ampq server channel: %v", err)
+  }
+  if err := c.conn.Close(); err != nil && !errors.Is(err, ampq.ErrClosed) {
+    return fmt.Errorf("cannot close rabbitmq connection: %v", err)
+  }
+  return nil
 }
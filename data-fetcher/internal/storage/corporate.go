@@ -0,0 +1,185 @@
+package storage
+
+import (
+  "fmt"
+  "main/internal/domain"
+
+  sq "github.com/Masterminds/squirrel"
+  "github.com/jackc/pgx/v4"
+  log "github.com/sirupsen/logrus"
+)
+
+// PutStockSplits insert new splits and adjust stored stocks before the split execution,
+// so adjusted series of already stored stocks stay consistent with new stocks
+func (s *storage) PutStockSplits(splits []*domain.StockSplit) error {
+  if len(splits) == 0 {
+    return nil
+  }
+  var inserted int
+
+  if err := s.client.BeginTxFunc(s.ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+    for _, split := range splits {
+      if split == nil {
+        continue
+      }
+      builder := sq.Insert(`stock_split`).
+        Columns(
+          `split_id`,
+          `ticker_id`,
+          `execution_date`,
+          `split_from`,
+          `split_to`,
+          `created_at`,
+        ).
+        Values(
+          split.SplitId,
+          split.TickerId,
+          split.ExecutionDate,
+          split.SplitFrom,
+          split.SplitTo,
+          split.CreatedAt,
+        ).
+        Suffix(`ON CONFLICT (split_id) DO NOTHING`).
+        PlaceholderFormat(sq.Dollar)
+
+      query, args := mustBuildQuery(builder)
+
+      tag, err := tx.Exec(s.ctx, query, args...)
+      if err != nil {
+        return fmt.Errorf("cannot put stock split '%s': %v", split.SplitId, err)
+      }
+      // split already stored, so stocks already adjusted
+      if tag.RowsAffected() == 0 {
+        continue
+      }
+      if err = doPutQueryTx(s.ctx, tx, buildAdjustStocksQuery(split)); err != nil {
+        return fmt.Errorf("cannot adjust stocks by split '%s': %v", split.SplitId, err)
+      }
+      inserted++
+    }
+    return nil
+  }); err != nil {
+    return err
+  }
+  if inserted != 0 {
+    log.Infof("put %d new splits for ticker '%s' in storage and adjusted stored stocks",
+      inserted, splits[0].TickerId)
+  }
+  return nil
+}
+
+// buildAdjustStocksQuery scale adjusted values of the stocks before the split.
+// stocks stored before adjustment have null adjusted values, so raw values are scaled for them
+func buildAdjustStocksQuery(split *domain.StockSplit) queryBuilder {
+  priceRatio := split.SplitFrom / split.SplitTo
+
+  return sq.Update(`stock`).
+    Set(`adj_open_price`, sq.Expr(`coalesce(adj_open_price, open_price) * ?`, priceRatio)).
+    Set(`adj_close_price`, sq.Expr(`coalesce(adj_close_price, close_price) * ?`, priceRatio)).
+    Set(`adj_highest_price`, sq.Expr(`coalesce(adj_highest_price, highest_price) * ?`, priceRatio)).
+    Set(`adj_lowest_price`, sq.Expr(`coalesce(adj_lowest_price, lowest_price) * ?`, priceRatio)).
+    Set(`adj_trading_volume`, sq.Expr(`coalesce(adj_trading_volume, trading_volume) / ?`, priceRatio)).
+    Where(sq.And{
+      sq.Eq{
+        `ticker_id`: split.TickerId,
+      },
+      sq.Lt{
+        `stocked_at`: split.ExecutionDate,
+      },
+    }).
+    PlaceholderFormat(sq.Dollar)
+}
+
+func (s *storage) GetStockSplits(tickerId string) ([]*domain.StockSplit, error) {
+  builder := sq.Select(
+    `split_id`,
+    `ticker_id`,
+    `execution_date`,
+    `split_from`,
+    `split_to`,
+    `created_at`,
+  ).
+    From(`stock_split`).
+    Where(sq.Eq{
+      `ticker_id`: tickerId,
+    }).
+    OrderBy(`execution_date`).
+    PlaceholderFormat(sq.Dollar)
+
+  var splits []*domain.StockSplit
+
+  if err := s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      split := &domain.StockSplit{}
+
+      found, err := scanQueriedRow(rows,
+        &split.SplitId,
+        &split.TickerId,
+        &split.ExecutionDate,
+        &split.SplitFrom,
+        &split.SplitTo,
+        &split.CreatedAt,
+      )
+      if err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      splits = append(splits, split)
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return splits, nil
+}
+
+func (s *storage) PutStockDividends(dividends []*domain.StockDividend) error {
+  builder := sq.Insert(`stock_dividend`).
+    Columns(
+      `dividend_id`,
+      `ticker_id`,
+      `cash_amount`,
+      `currency`,
+      `dividend_type`,
+      `frequency`,
+      `ex_dividend_date`,
+      `declaration_date`,
+      `record_date`,
+      `pay_date`,
+      `created_at`,
+    ).
+    Suffix(`ON CONFLICT (dividend_id) DO NOTHING`).
+    PlaceholderFormat(sq.Dollar)
+
+  var count int
+
+  for _, dividend := range dividends {
+    if dividend == nil {
+      continue
+    }
+    builder = builder.Values(
+      dividend.DividendId,
+      dividend.TickerId,
+      dividend.CashAmount,
+      dividend.Currency,
+      dividend.DividendType,
+      dividend.Frequency,
+      dividend.ExDividendDate,
+      dividend.DeclarationDate,
+      dividend.RecordDate,
+      dividend.PayDate,
+      dividend.CreatedAt,
+    )
+    count++
+  }
+  if count == 0 {
+    return nil
+  }
+  if err := s.doPutQuery(builder); err != nil {
+    return fmt.Errorf("cannot put stock dividends: %v", err)
+  }
+  return nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "time"
+
+  sq "github.com/Masterminds/squirrel"
+)
+
+// PutTickerFinancials insert financials of the ticker, restated financials replace stored ones
+func (s *storage) PutTickerFinancials(financials []*domain.TickerFinancial) error {
+  financials = latestFinancials(financials)
+  if len(financials) == 0 {
+    return nil
+  }
+  builder := sq.Insert(`ticker_financial`).
+    Columns(
+      `financial_id`,
+      `ticker_id`,
+      `timeframe`,
+      `fiscal_period`,
+      `fiscal_year`,
+      `start_date`,
+      `end_date`,
+      `filing_date`,
+      `revenue`,
+      `net_income`,
+      `basic_eps`,
+      `diluted_eps`,
+      `shares_outstanding`,
+      `created_at`,
+      `updated_at`,
+    ).
+    Suffix(`ON CONFLICT (financial_id) DO UPDATE SET
+      filing_date = EXCLUDED.filing_date,
+      revenue = EXCLUDED.revenue,
+      net_income = EXCLUDED.net_income,
+      basic_eps = EXCLUDED.basic_eps,
+      diluted_eps = EXCLUDED.diluted_eps,
+      shares_outstanding = EXCLUDED.shares_outstanding,
+      updated_at = EXCLUDED.updated_at`).
+    PlaceholderFormat(sq.Dollar)
+
+  for _, financial := range financials {
+    builder = builder.Values(
+      financial.FinancialId,
+      financial.TickerId,
+      financial.Timeframe,
+      financial.FiscalPeriod,
+      financial.FiscalYear,
+      financial.StartDate,
+      financial.EndDate,
+      financial.FilingDate,
+      financial.Revenue,
+      financial.NetIncome,
+      financial.BasicEps,
+      financial.DilutedEps,
+      financial.SharesOutstanding,
+      financial.CreatedAt,
+      financial.UpdatedAt,
+    )
+  }
+  if err := s.doPutQuery(builder); err != nil {
+    return fmt.Errorf("cannot put ticker financials: %v", err)
+  }
+  return nil
+}
+
+// latestFinancials keep the latest filed financials of the period. amended filings have the same id,
+// and one insert query cannot update the same row twice
+func latestFinancials(financials []*domain.TickerFinancial) []*domain.TickerFinancial {
+  latest := make([]*domain.TickerFinancial, 0, len(financials))
+  indexes := make(map[string]int, len(financials))
+
+  for _, financial := range financials {
+    if financial == nil {
+      continue
+    }
+    idx, ok := indexes[financial.FinancialId]
+    if !ok {
+      indexes[financial.FinancialId] = len(latest)
+      latest = append(latest, financial)
+      continue
+    }
+    if filedLater(financial.FilingDate, latest[idx].FilingDate) {
+      latest[idx] = financial
+    }
+  }
+  return latest
+}
+
+// filedLater report that filing date is after the other one, unknown date is the earliest
+func filedLater(date, other *time.Time) bool {
+  if date == nil {
+    return false
+  }
+  return other == nil || date.After(*other)
+}
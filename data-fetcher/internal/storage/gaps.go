@@ -0,0 +1,222 @@
+package storage
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "time"
+
+  sq "github.com/Masterminds/squirrel"
+  datafetcher "github.com/UshakovN/stock-predictor-service/contract/data-fetcher"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  "github.com/jackc/pgx/v4"
+  log "github.com/sirupsen/logrus"
+)
+
+type GetStockGapsOption struct {
+  TickerId string
+  Status   string
+}
+
+// CloseStockGapsOption select stored gaps which are not detected anymore
+type CloseStockGapsOption struct {
+  // all missing days of the gaps are stored
+  FilledIds []string
+  // gaps are replaced by the narrower detected ones
+  ReplacedIds []string
+}
+
+// GetStockDates return sorted distinct dates of stored stocks with specified granularity
+func (s *storage) GetStockDates(tickerId, timespan string, multiplier int) ([]time.Time, error) {
+  builder := sq.Select(
+    `DISTINCT date_trunc('day', stocked_at) AS stocked_date`,
+  ).
+    From(`stock`).
+    Where(sq.Eq{
+      `ticker_id`:  tickerId,
+      `timespan`:   timespan,
+      `multiplier`: multiplier,
+    }).
+    OrderBy(`stocked_date`).
+    PlaceholderFormat(sq.Dollar)
+
+  var (
+    dates []time.Time
+    found bool
+    err   error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      var date time.Time
+
+      if found, err = scanQueriedRow(rows, &date); err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      dates = append(dates, date)
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return dates, nil
+}
+
+func (s *storage) PutStockGap(gap *domain.StockGap) error {
+  if gap == nil {
+    return fmt.Errorf("stock gap is a nil")
+  }
+  builder := sq.Insert(`stock_gap`).
+    Columns(
+      `gap_id`,
+      `ticker_id`,
+      `timespan`,
+      `multiplier`,
+      `date_from`,
+      `date_to`,
+      `missing_days`,
+      `filled_days`,
+      `status`,
+      `attempts`,
+      `created_at`,
+      `updated_at`,
+    ).
+    Values(
+      gap.GapId,
+      gap.TickerId,
+      gap.Timespan,
+      gap.Multiplier,
+      gap.DateFrom,
+      gap.DateTo,
+      gap.MissingDays,
+      gap.FilledDays,
+      gap.Status,
+      gap.Attempts,
+      gap.CreatedAt,
+      gap.UpdatedAt,
+    ).
+    Suffix(`ON CONFLICT (gap_id) DO UPDATE SET
+      filled_days = EXCLUDED.filled_days,
+      status = EXCLUDED.status,
+      attempts = stock_gap.attempts + 1,
+      updated_at = EXCLUDED.updated_at`).
+    PlaceholderFormat(sq.Dollar)
+
+  if err := s.doPutQuery(builder); err != nil {
+    return err
+  }
+  log.Infof("put stock gap '%s' with status '%s' to storage", gap.GapId, gap.Status)
+
+  return nil
+}
+
+func (s *storage) GetStockGaps(option *GetStockGapsOption) ([]*domain.StockGap, error) {
+  builder := sq.Select(
+    `gap_id`,
+    `ticker_id`,
+    `timespan`,
+    `multiplier`,
+    `date_from`,
+    `date_to`,
+    `missing_days`,
+    `filled_days`,
+    `status`,
+    `attempts`,
+    `created_at`,
+    `updated_at`,
+  ).
+    From(`stock_gap`).
+    OrderBy(`ticker_id`, `date_from`).
+    PlaceholderFormat(sq.Dollar)
+
+  if option != nil {
+    where := sq.Eq{}
+    if option.TickerId != "" {
+      where[`ticker_id`] = option.TickerId
+    }
+    if option.Status != "" {
+      where[`status`] = option.Status
+    }
+    builder = builder.Where(where)
+  }
+
+  var (
+    gaps  []*domain.StockGap
+    found bool
+    err   error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      gap := &domain.StockGap{}
+
+      if found, err = scanQueriedRow(rows,
+        &gap.GapId,
+        &gap.TickerId,
+        &gap.Timespan,
+        &gap.Multiplier,
+        &gap.DateFrom,
+        &gap.DateTo,
+        &gap.MissingDays,
+        &gap.FilledDays,
+        &gap.Status,
+        &gap.Attempts,
+        &gap.CreatedAt,
+        &gap.UpdatedAt,
+      ); err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      gaps = append(gaps, gap)
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return gaps, nil
+}
+
+// CloseStockGaps mark filled gaps and delete replaced ones in one transaction,
+// so the gaps report contain only the last detected ranges
+func (s *storage) CloseStockGaps(option *CloseStockGapsOption) error {
+  if option == nil || len(option.FilledIds) == 0 && len(option.ReplacedIds) == 0 {
+    return nil
+  }
+  if err := s.client.BeginTxFunc(s.ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+    if len(option.FilledIds) != 0 {
+      builder := sq.Update(`stock_gap`).
+        Set(`filled_days`, sq.Expr(`missing_days`)).
+        Set(`status`, datafetcher.GapStatusFilled).
+        Set(`updated_at`, utils.NotTimeUTC()).
+        Where(sq.Eq{
+          `gap_id`: option.FilledIds,
+        }).
+        PlaceholderFormat(sq.Dollar)
+
+      if err := doPutQueryTx(s.ctx, tx, builder); err != nil {
+        return fmt.Errorf("cannot mark stock gaps filled: %v", err)
+      }
+    }
+    if len(option.ReplacedIds) != 0 {
+      builder := sq.Delete(`stock_gap`).
+        Where(sq.Eq{
+          `gap_id`: option.ReplacedIds,
+        }).
+        PlaceholderFormat(sq.Dollar)
+
+      if err := doPutQueryTx(s.ctx, tx, builder); err != nil {
+        return fmt.Errorf("cannot delete replaced stock gaps: %v", err)
+      }
+    }
+    return nil
+  }); err != nil {
+    return err
+  }
+  log.Infof("closed stock gaps: %d filled, %d replaced", len(option.FilledIds), len(option.ReplacedIds))
+
+  return nil
+}
@@ -0,0 +1,312 @@
+package storage
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "time"
+
+  sq "github.com/Masterminds/squirrel"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  "github.com/jackc/pgx/v4"
+  log "github.com/sirupsen/logrus"
+)
+
+const (
+  historyTableTicker        = "ticker"
+  historyTableTickerDetails = "ticker_details"
+)
+
+type columnChange struct {
+  column   string
+  previous any
+  current  any
+}
+
+// diffColumns return changes between previous and current column values
+func diffColumns(columns []string, previous, current []any) []*columnChange {
+  var changes []*columnChange
+
+  for idx, column := range columns {
+    if formatColumnValue(previous[idx]) == formatColumnValue(current[idx]) {
+      continue
+    }
+    changes = append(changes, &columnChange{
+      column:   column,
+      previous: previous[idx],
+      current:  current[idx],
+    })
+  }
+  return changes
+}
+
+// formatColumnValue format optional time by its value instead of the pointer
+func formatColumnValue(value any) string {
+  if t, ok := value.(*time.Time); ok {
+    if t == nil {
+      return ""
+    }
+    return t.UTC().Format(time.RFC3339)
+  }
+  return fmt.Sprint(value)
+}
+
+func tickerTrackedColumns() []string {
+  return []string{
+    `company_name`,
+    `company_locale`,
+    `currency_name`,
+    `ticker_cik`,
+    `active`,
+  }
+}
+
+func tickerTrackedValues(ticker *domain.Ticker) []any {
+  return []any{
+    ticker.CompanyName,
+    ticker.CompanyLocale,
+    ticker.CurrencyName,
+    ticker.TickerCik,
+    ticker.Active,
+  }
+}
+
+func tickerDetailsTrackedColumns() []string {
+  return []string{
+    `company_description`,
+    `homepage_url`,
+    `phone_number`,
+    `total_employees`,
+    `company_state`,
+    `company_city`,
+    `company_address`,
+    `company_postal_code`,
+    `sic_code`,
+    `sic_description`,
+    `sector`,
+    `primary_exchange`,
+    `market`,
+    `list_date`,
+  }
+}
+
+// tickerDetailsSelectColumns select tracked columns added later as empty values,
+// they are null for details stored before
+func tickerDetailsSelectColumns() []string {
+  nullable := map[string]struct{}{
+    `sic_code`:         {},
+    `sic_description`:  {},
+    `sector`:           {},
+    `primary_exchange`: {},
+    `market`:           {},
+  }
+  columns := tickerDetailsTrackedColumns()
+  selected := make([]string, 0, len(columns))
+
+  for _, column := range columns {
+    if _, ok := nullable[column]; ok {
+      column = fmt.Sprintf(`coalesce(%s, '')`, column)
+    }
+    selected = append(selected, column)
+  }
+  return selected
+}
+
+func tickerDetailsTrackedValues(details *domain.TickerDetails) []any {
+  return []any{
+    details.CompanyDescription,
+    details.HomepageUrl,
+    details.PhoneNumber,
+    details.TotalEmployees,
+    details.CompanyState,
+    details.CompanyCity,
+    details.CompanyAddress,
+    details.CompanyPostalCode,
+    details.SicCode,
+    details.SicDescription,
+    details.Sector,
+    details.PrimaryExchange,
+    details.Market,
+    details.ListDate,
+  }
+}
+
+// upsertTicker overwrite changed ticker columns if ticker updated
+// in external source after the stored one and save previous values in history
+func (s *storage) upsertTicker(ticker *domain.Ticker) (bool, error) {
+  var changed bool
+
+  err := s.client.BeginTxFunc(s.ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+    builder := sq.Select(
+      `external_updated_at`,
+    ).
+      From(`ticker`).
+      Where(sq.Eq{
+        `ticker_id`: ticker.TickerId,
+      }).
+      Suffix(`FOR UPDATE`).
+      PlaceholderFormat(sq.Dollar)
+
+    builder = builder.Columns(tickerTrackedColumns()...)
+
+    stored := &domain.Ticker{}
+    var (
+      found bool
+      err   error
+    )
+    if err = s.doGetQueryTx(tx, builder, func(rows pgx.Rows) error {
+      found, err = scanFirstQueriedRow(rows,
+        &stored.ExternalUpdatedAt,
+        &stored.CompanyName,
+        &stored.CompanyLocale,
+        &stored.CurrencyName,
+        &stored.TickerCik,
+        &stored.Active,
+      )
+      return err
+    }); err != nil {
+      return fmt.Errorf("cannot get stored ticker: %v", err)
+    }
+    if !found {
+      changed = true
+      return doPutQueryTx(s.ctx, tx, buildPutTickerQuery(ticker))
+    }
+    if !ticker.ExternalUpdatedAt.After(stored.ExternalUpdatedAt) {
+      return nil
+    }
+    changes := diffColumns(tickerTrackedColumns(), tickerTrackedValues(stored), tickerTrackedValues(ticker))
+
+    if err = s.putHistory(tx, ticker.TickerId, historyTableTicker, ticker.ExternalUpdatedAt, changes); err != nil {
+      return err
+    }
+    updateBuilder := sq.Update(`ticker`).
+      Set(`external_updated_at`, ticker.ExternalUpdatedAt).
+      Where(sq.Eq{
+        `ticker_id`: ticker.TickerId,
+      }).
+      PlaceholderFormat(sq.Dollar)
+
+    for _, change := range changes {
+      updateBuilder = updateBuilder.Set(change.column, change.current)
+    }
+    changed = len(changes) != 0
+
+    return doPutQueryTx(s.ctx, tx, updateBuilder)
+  })
+  if err != nil {
+    return false, err
+  }
+  return changed, nil
+}
+
+// upsertTickerDetails overwrite changed ticker details columns
+// and save previous values in history
+func (s *storage) upsertTickerDetails(details *domain.TickerDetails, outbox []*domain.BrandingOutbox) (bool, error) {
+  var changed bool
+
+  err := s.client.BeginTxFunc(s.ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+    if err := putBrandingOutboxTx(s.ctx, tx, outbox); err != nil {
+      return err
+    }
+    builder := sq.Select(tickerDetailsSelectColumns()...).
+      From(`ticker_details`).
+      Where(sq.Eq{
+        `ticker_id`: details.TickerId,
+      }).
+      Suffix(`FOR UPDATE`).
+      PlaceholderFormat(sq.Dollar)
+
+    stored := &domain.TickerDetails{}
+    var (
+      found bool
+      err   error
+    )
+    if err = s.doGetQueryTx(tx, builder, func(rows pgx.Rows) error {
+      found, err = scanFirstQueriedRow(rows,
+        &stored.CompanyDescription,
+        &stored.HomepageUrl,
+        &stored.PhoneNumber,
+        &stored.TotalEmployees,
+        &stored.CompanyState,
+        &stored.CompanyCity,
+        &stored.CompanyAddress,
+        &stored.CompanyPostalCode,
+        &stored.SicCode,
+        &stored.SicDescription,
+        &stored.Sector,
+        &stored.PrimaryExchange,
+        &stored.Market,
+        &stored.ListDate,
+      )
+      return err
+    }); err != nil {
+      return fmt.Errorf("cannot get stored ticker details: %v", err)
+    }
+    if !found {
+      changed = true
+      return doPutQueryTx(s.ctx, tx, buildPutTickerDetailsQuery(details))
+    }
+    changes := diffColumns(tickerDetailsTrackedColumns(), tickerDetailsTrackedValues(stored), tickerDetailsTrackedValues(details))
+
+    // ticker details do not have external update time
+    if err = s.putHistory(tx, details.TickerId, historyTableTickerDetails, utils.NotTimeUTC(), changes); err != nil {
+      return err
+    }
+    // refresh time is updated even if nothing changed
+    updateBuilder := sq.Update(`ticker_details`).
+      Set(`updated_at`, details.UpdatedAt).
+      Where(sq.Eq{
+        `ticker_id`: details.TickerId,
+      }).
+      PlaceholderFormat(sq.Dollar)
+
+    for _, change := range changes {
+      updateBuilder = updateBuilder.Set(change.column, change.current)
+    }
+    changed = len(changes) != 0
+
+    return doPutQueryTx(s.ctx, tx, updateBuilder)
+  })
+  if err != nil {
+    return false, err
+  }
+  return changed, nil
+}
+
+func (s *storage) putHistory(tx pgx.Tx, tickerId, tableName string, externalUpdatedAt time.Time, changes []*columnChange) error {
+  if len(changes) == 0 {
+    return nil
+  }
+  builder := sq.Insert(`ticker_history`).
+    Columns(
+      // `history_id` is serial type, autoincrement
+      `ticker_id`,
+      `table_name`,
+      `column_name`,
+      `previous_value`,
+      `current_value`,
+      `external_updated_at`,
+      `created_at`,
+    ).
+    PlaceholderFormat(sq.Dollar)
+
+  createdAt := utils.NotTimeUTC()
+
+  for _, change := range changes {
+    builder = builder.Values(
+      tickerId,
+      tableName,
+      change.column,
+      formatColumnValue(change.previous),
+      formatColumnValue(change.current),
+      externalUpdatedAt,
+      createdAt,
+    )
+  }
+  if err := doPutQueryTx(s.ctx, tx, builder); err != nil {
+    return fmt.Errorf("cannot put ticker history: %v", err)
+  }
+  log.Infof("put %d changes of '%s' for ticker '%s' to history",
+    len(changes), tableName, tickerId)
+
+  return nil
+}
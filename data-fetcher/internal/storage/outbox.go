@@ -0,0 +1,163 @@
+package storage
+
+import (
+  "context"
+  "fmt"
+  "main/internal/domain"
+
+  sq "github.com/Masterminds/squirrel"
+  "github.com/UshakovN/stock-predictor-service/utils"
+  "github.com/jackc/pgx/v4"
+)
+
+const (
+  OutboxStatusPending = "pending"
+  OutboxStatusSent    = "sent"
+  // messages dropped after the attempts limit before, relayed again as pending ones
+  OutboxStatusFailed = "failed"
+)
+
+// putBrandingOutboxTx insert outbox messages. message with changed image url
+// is queued again, unchanged one keep its status, so branding is sent once
+func putBrandingOutboxTx(ctx context.Context, tx pgx.Tx, outbox []*domain.BrandingOutbox) error {
+  builder := sq.Insert(`branding_outbox`).
+    Columns(
+      `outbox_id`,
+      `ticker_id`,
+      `branding_type`,
+      `image_url`,
+      `status`,
+      `attempts`,
+      `last_error`,
+      `next_attempt_at`,
+      `created_at`,
+      `updated_at`,
+    ).
+    Suffix(`ON CONFLICT (outbox_id) DO UPDATE SET
+      image_url = EXCLUDED.image_url,
+      status = EXCLUDED.status,
+      attempts = EXCLUDED.attempts,
+      last_error = EXCLUDED.last_error,
+      next_attempt_at = EXCLUDED.next_attempt_at,
+      updated_at = EXCLUDED.updated_at
+      WHERE branding_outbox.image_url <> EXCLUDED.image_url`).
+    PlaceholderFormat(sq.Dollar)
+
+  var count int
+
+  for _, message := range outbox {
+    if message == nil {
+      continue
+    }
+    builder = builder.Values(
+      message.OutboxId,
+      message.TickerId,
+      message.BrandingType,
+      message.ImageUrl,
+      message.Status,
+      message.Attempts,
+      message.LastError,
+      message.NextAttemptAt,
+      message.CreatedAt,
+      message.UpdatedAt,
+    )
+    count++
+  }
+  if count == 0 {
+    return nil
+  }
+  if err := doPutQueryTx(ctx, tx, builder); err != nil {
+    return fmt.Errorf("cannot put branding outbox: %v", err)
+  }
+  return nil
+}
+
+// GetPendingBrandingOutbox return pending and previously failed messages which next attempt time has come
+func (s *storage) GetPendingBrandingOutbox(limit int) ([]*domain.BrandingOutbox, error) {
+  builder := sq.Select(
+    `outbox_id`,
+    `ticker_id`,
+    `branding_type`,
+    `image_url`,
+    `status`,
+    `attempts`,
+    `last_error`,
+    `next_attempt_at`,
+    `created_at`,
+    `updated_at`,
+  ).
+    From(`branding_outbox`).
+    Where(sq.And{
+      sq.Eq{
+        `status`: []string{
+          OutboxStatusPending,
+          OutboxStatusFailed,
+        },
+      },
+      sq.LtOrEq{
+        `next_attempt_at`: utils.NotTimeUTC(),
+      },
+    }).
+    OrderBy(`next_attempt_at`).
+    Limit(uint64(limit)).
+    PlaceholderFormat(sq.Dollar)
+
+  var (
+    outbox []*domain.BrandingOutbox
+    found  bool
+    err    error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      message := &domain.BrandingOutbox{}
+
+      if found, err = scanQueriedRow(rows,
+        &message.OutboxId,
+        &message.TickerId,
+        &message.BrandingType,
+        &message.ImageUrl,
+        &message.Status,
+        &message.Attempts,
+        &message.LastError,
+        &message.NextAttemptAt,
+        &message.CreatedAt,
+        &message.UpdatedAt,
+      ); err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      outbox = append(outbox, message)
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return outbox, nil
+}
+
+// PutBrandingOutboxAttempt store result of the message publishing attempt
+func (s *storage) PutBrandingOutboxAttempt(outbox *domain.BrandingOutbox) error {
+  if outbox == nil {
+    return fmt.Errorf("branding outbox is a nil")
+  }
+  builder := sq.Update(`branding_outbox`).
+    SetMap(map[string]any{
+      `status`:          outbox.Status,
+      `attempts`:        outbox.Attempts,
+      `last_error`:      outbox.LastError,
+      `next_attempt_at`: outbox.NextAttemptAt,
+      `updated_at`:      outbox.UpdatedAt,
+    }).
+    Where(sq.Eq{
+      `outbox_id`: outbox.OutboxId,
+    }).
+    PlaceholderFormat(sq.Dollar)
+
+  if err := s.doPutQuery(builder); err != nil {
+    return fmt.Errorf("cannot update branding outbox '%s': %v", outbox.OutboxId, err)
+  }
+  return nil
+}
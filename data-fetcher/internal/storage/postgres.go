@@ -6,6 +6,7 @@ import (
   "main/internal/domain"
   "sync"
   "sync/atomic"
+  "time"
 
   sq "github.com/Masterminds/squirrel"
   "github.com/UshakovN/stock-predictor-service/postgres"
@@ -15,23 +16,84 @@ import (
 
 const counterInc = 1
 
+// fetcher state is stored in the single row
+const fetcherStateId = 1
+
+// granularity of the bars stored without it
+const (
+  defaultTimespan   = "day"
+  defaultMultiplier = 1
+)
+
+const (
+  stockColumnsCount      = 16
+  defaultStocksBatchSize = 1000
+  // postgres limit bind parameters count in one query by 65535
+  maxStocksBatchSize = 65535 / stockColumnsCount
+)
+
 type queryBuilder interface {
   ToSql() (string, []any, error)
 }
 
 type Storage interface {
   PutTicker(ticker *domain.Ticker) error
-  PutTickerDetails(ticker *domain.TickerDetails) error
-  PutStock(stock *domain.Stock) error
+  PutTickerDetails(ticker *domain.TickerDetails, outbox []*domain.BrandingOutbox) error
+  RefreshTickerDetails(ticker *domain.TickerDetails, outbox []*domain.BrandingOutbox) error
+  GetTickerDetailsUpdatedAt(tickerId string) (time.Time, bool, error)
+  PutStocks(stocks []*domain.Stock) (int64, error)
+  PutStocksWithCheckpoint(stocks []*domain.Stock, checkpoint *domain.FetcherCheckpoint) (int64, error)
+  BackfillStocksGranularity() (int64, error)
+  PutFetcherCheckpoint(checkpoint *domain.FetcherCheckpoint) error
+  GetFetcherCheckpoint(tickerId, timespan string, multiplier int) (*domain.FetcherCheckpoint, bool, error)
   PutFetcherState(state *domain.FetcherState) error
   GetFetcherState() (*domain.FetcherState, bool, error)
   GetTickers() ([]*domain.Ticker, error)
+  GetWatchedTickerIds() ([]string, error)
+  GetSubscriptionCounts() (map[string]int, error)
+  GetTickersActivity() (map[string]*domain.TickerActivity, error)
+  GetStockDates(tickerId, timespan string, multiplier int) ([]time.Time, error)
+  PutStockGap(gap *domain.StockGap) error
+  GetStockGaps(option *GetStockGapsOption) ([]*domain.StockGap, error)
+  CloseStockGaps(option *CloseStockGapsOption) error
+  PutStockSplits(splits []*domain.StockSplit) error
+  GetStockSplits(tickerId string) ([]*domain.StockSplit, error)
+  GetLastStock(tickerId, timespan string, multiplier int, before time.Time) (*domain.Stock, bool, error)
+  PutStockQuarantine(quarantined []*domain.StockQuarantine) error
+  GetQuarantineCounts(tickerId string) ([]*domain.QuarantineCount, error)
+  PutStockDividends(dividends []*domain.StockDividend) error
+  PutTickerFinancials(financials []*domain.TickerFinancial) error
+  GetTickerRefreshedAt(tickerId, resource string) (time.Time, bool, error)
+  PutTickerRefreshedAt(tickerId, resource string, refreshedAt time.Time) error
+  GetPendingBrandingOutbox(limit int) ([]*domain.BrandingOutbox, error)
+  PutBrandingOutboxAttempt(outbox *domain.BrandingOutbox) error
+  GetCounters() *domain.StorageCounters
+  Close()
 }
 
 type storage struct {
-  ctx      context.Context
-  client   postgres.Client
-  counters *storageCounters
+  ctx             context.Context
+  client          postgres.Client
+  counters        *storageCounters
+  stocksBatchSize int
+  updateTickers   bool
+}
+
+type Options func(s *storage)
+
+// WithStocksBatchSize set count of stocks inserted by one query in PutStocks
+func WithStocksBatchSize(batchSize int) Options {
+  return func(s *storage) {
+    if batchSize <= 0 {
+      return
+    }
+    if batchSize > maxStocksBatchSize {
+      log.Warnf("stocks batch size %d exceeds maximum. will be used %d",
+        batchSize, maxStocksBatchSize)
+      batchSize = maxStocksBatchSize
+    }
+    s.stocksBatchSize = batchSize
+  }
 }
 
 type storageCounters struct {
@@ -40,16 +102,29 @@ type storageCounters struct {
   tickerDetails atomic.Uint64
 }
 
-func NewStorage(ctx context.Context, config *postgres.Config) (Storage, error) {
+// WithTickersUpdate enable update mode for tickers and ticker details.
+// changed columns will be overwritten and previous values saved in history
+func WithTickersUpdate(enabled bool) Options {
+  return func(s *storage) {
+    s.updateTickers = enabled
+  }
+}
+
+func NewStorage(ctx context.Context, config *postgres.Config, options ...Options) (Storage, error) {
   client, err := postgres.NewClient(ctx, config)
   if err != nil {
     return nil, fmt.Errorf("cannot create new postgres client: %v", err)
   }
-  return &storage{
-    ctx:      ctx,
-    client:   client,
-    counters: newStorageCounters(),
-  }, nil
+  s := &storage{
+    ctx:             ctx,
+    client:          client,
+    counters:        newStorageCounters(),
+    stocksBatchSize: defaultStocksBatchSize,
+  }
+  for _, opt := range options {
+    opt(s)
+  }
+  return s, nil
 }
 
 func newStorageCounters() *storageCounters {
@@ -60,11 +135,45 @@ func newStorageCounters() *storageCounters {
   }
 }
 
+func (s *storage) Close() {
+  s.client.Close()
+}
+
+// GetCounters return count of entities put in storage since the service start
+func (s *storage) GetCounters() *domain.StorageCounters {
+  return &domain.StorageCounters{
+    Tickers:       s.counters.ticker.Load(),
+    TickerDetails: s.counters.tickerDetails.Load(),
+    Stocks:        s.counters.stock.Load(),
+  }
+}
+
 func (s *storage) PutTicker(ticker *domain.Ticker) error {
   if ticker == nil {
     return fmt.Errorf("ticker is a nil")
   }
-  builder := sq.Insert(`ticker`).
+  if s.updateTickers {
+    changed, err := s.upsertTicker(ticker)
+    if err != nil {
+      return err
+    }
+    if changed {
+      log.Infof("upsert ticker '%s' for company '%s' to storage. total: %d",
+        ticker.TickerId, ticker.CompanyName, s.counters.ticker.Add(counterInc))
+    }
+    return nil
+  }
+  if err := s.doPutQuery(buildPutTickerQuery(ticker)); err != nil {
+    return err
+  }
+  log.Infof("put ticker '%s' for company '%s' to storage. total: %d",
+    ticker.TickerId, ticker.CompanyName, s.counters.ticker.Add(counterInc))
+
+  return nil
+}
+
+func buildPutTickerQuery(ticker *domain.Ticker) queryBuilder {
+  return sq.Insert(`ticker`).
     Columns(
       `ticker_id`,
       `company_name`,
@@ -87,21 +196,82 @@ func (s *storage) PutTicker(ticker *domain.Ticker) error {
     ).
     Suffix(`ON CONFLICT (ticker_id) DO NOTHING`).
     PlaceholderFormat(sq.Dollar)
+}
 
-  if err := s.doPutQuery(builder); err != nil {
+// PutTickerDetails insert ticker details and branding outbox messages in one transaction
+func (s *storage) PutTickerDetails(tickerDetails *domain.TickerDetails, outbox []*domain.BrandingOutbox) error {
+  if tickerDetails == nil {
+    return fmt.Errorf("ticker details is a nil")
+  }
+  if s.updateTickers {
+    changed, err := s.upsertTickerDetails(tickerDetails, outbox)
+    if err != nil {
+      return err
+    }
+    if changed {
+      log.Infof("upsert ticker details for ticker '%s' to storage. total: %d",
+        tickerDetails.TickerId, s.counters.tickerDetails.Add(counterInc))
+    }
+    return nil
+  }
+  if err := s.client.BeginTxFunc(s.ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+    if err := doPutQueryTx(s.ctx, tx, buildPutTickerDetailsQuery(tickerDetails)); err != nil {
+      return err
+    }
+    return putBrandingOutboxTx(s.ctx, tx, outbox)
+  }); err != nil {
     return err
   }
-  log.Infof("put ticker '%s' for company '%s' to storage. total: %d",
-    ticker.TickerId, ticker.CompanyName, s.counters.ticker.Add(counterInc))
+  log.Infof("put ticker details for ticker '%s' to storage. total: %d",
+    tickerDetails.TickerId, s.counters.tickerDetails.Add(counterInc))
 
   return nil
 }
 
-func (s *storage) PutTickerDetails(tickerDetails *domain.TickerDetails) error {
+// RefreshTickerDetails overwrite changed ticker details regardless of tickers update option,
+// so details stored before new columns were added are filled too
+func (s *storage) RefreshTickerDetails(tickerDetails *domain.TickerDetails, outbox []*domain.BrandingOutbox) error {
   if tickerDetails == nil {
     return fmt.Errorf("ticker details is a nil")
   }
-  builder := sq.Insert(`ticker_details`).
+  changed, err := s.upsertTickerDetails(tickerDetails, outbox)
+  if err != nil {
+    return err
+  }
+  if changed {
+    log.Infof("refresh ticker details for ticker '%s' in storage", tickerDetails.TickerId)
+  }
+  return nil
+}
+
+// GetTickerDetailsUpdatedAt return time of the last ticker details refresh,
+// details stored without refresh time are not found
+func (s *storage) GetTickerDetailsUpdatedAt(tickerId string) (time.Time, bool, error) {
+  builder := sq.Select(
+    `updated_at`,
+  ).
+    From(`ticker_details`).
+    Where(sq.Eq{
+      `ticker_id`: tickerId,
+    }).
+    PlaceholderFormat(sq.Dollar)
+
+  var updatedAt *time.Time
+
+  if err := s.doGetQuery(builder, func(rows pgx.Rows) error {
+    _, err := scanFirstQueriedRow(rows, &updatedAt)
+    return err
+  }); err != nil {
+    return time.Time{}, false, err
+  }
+  if updatedAt == nil {
+    return time.Time{}, false, nil
+  }
+  return *updatedAt, true, nil
+}
+
+func buildPutTickerDetailsQuery(tickerDetails *domain.TickerDetails) queryBuilder {
+  return sq.Insert(`ticker_details`).
     Columns(
       `ticker_id`,
       `company_description`,
@@ -112,6 +282,13 @@ func (s *storage) PutTickerDetails(tickerDetails *domain.TickerDetails) error {
       `company_city`,
       `company_address`,
       `company_postal_code`,
+      `sic_code`,
+      `sic_description`,
+      `sector`,
+      `primary_exchange`,
+      `market`,
+      `list_date`,
+      `updated_at`,
     ).
     Values(
       tickerDetails.TickerId,
@@ -123,56 +300,135 @@ func (s *storage) PutTickerDetails(tickerDetails *domain.TickerDetails) error {
       tickerDetails.CompanyCity,
       tickerDetails.CompanyAddress,
       tickerDetails.CompanyPostalCode,
+      tickerDetails.SicCode,
+      tickerDetails.SicDescription,
+      tickerDetails.Sector,
+      tickerDetails.PrimaryExchange,
+      tickerDetails.Market,
+      tickerDetails.ListDate,
+      tickerDetails.UpdatedAt,
     ).
     Suffix(`ON CONFLICT (ticker_id) DO NOTHING`).
     PlaceholderFormat(sq.Dollar)
+}
 
-  if err := s.doPutQuery(builder); err != nil {
-    return err
+var stockColumns = []string{
+  `stock_id`,
+  `ticker_id`,
+  `open_price`,
+  `close_price`,
+  `highest_price`,
+  `lowest_price`,
+  `trading_volume`,
+  `adj_open_price`,
+  `adj_close_price`,
+  `adj_highest_price`,
+  `adj_lowest_price`,
+  `adj_trading_volume`,
+  `timespan`,
+  `multiplier`,
+  `stocked_at`,
+  `created_at`,
+}
+
+func stockValues(stock *domain.Stock) []any {
+  return []any{
+    stock.StockId,
+    stock.TickerId,
+    stock.OpenPrice,
+    stock.ClosePrice,
+    stock.HighestPrice,
+    stock.LowestPrice,
+    stock.TradingVolume,
+    stock.AdjOpenPrice,
+    stock.AdjClosePrice,
+    stock.AdjHighestPrice,
+    stock.AdjLowestPrice,
+    stock.AdjTradingVolume,
+    stock.Timespan,
+    stock.Multiplier,
+    stock.StockedAt,
+    stock.CreatedAt,
   }
-  log.Infof("put ticker details for ticker '%s' to storage. total: %d",
-    tickerDetails.TickerId, s.counters.tickerDetails.Add(counterInc))
+}
 
-  return nil
+// PutStocks insert stocks by multi-row queries with batches in one transaction
+// and return count of inserted stocks, already stored stocks are skipped
+func (s *storage) PutStocks(stocks []*domain.Stock) (int64, error) {
+  return s.PutStocksWithCheckpoint(stocks, nil)
 }
 
-func (s *storage) PutStock(stock *domain.Stock) error {
-  if stock == nil {
-    return fmt.Errorf("stock is a nil")
+// PutStocksWithCheckpoint insert stocks and fetcher checkpoint in one transaction,
+// so stored checkpoint always match to stored stocks
+func (s *storage) PutStocksWithCheckpoint(stocks []*domain.Stock, checkpoint *domain.FetcherCheckpoint) (int64, error) {
+  batch := make([]*domain.Stock, 0, len(stocks))
+
+  for _, stock := range stocks {
+    if stock != nil {
+      batch = append(batch, stock)
+    }
   }
-  builder := sq.Insert(`stock`).
-    Columns(
-      `stock_id`,
-      `ticker_id`,
-      `open_price`,
-      `close_price`,
-      `highest_price`,
-      `lowest_price`,
-      `trading_volume`,
-      `stocked_at`,
-      `created_at`,
-    ).
-    Values(
-      stock.StockId,
-      stock.TickerId,
-      stock.OpenPrice,
-      stock.ClosePrice,
-      stock.HighestPrice,
-      stock.LowestPrice,
-      stock.TradingVolume,
-      stock.StockedAt,
-      stock.CreatedAt,
-    ).
-    Suffix(`ON CONFLICT (stock_id) DO NOTHING`).
+  if len(batch) == 0 && checkpoint == nil {
+    return 0, nil
+  }
+  var inserted int64
+
+  if err := s.client.BeginTxFunc(s.ctx, pgx.TxOptions{},
+    func(tx pgx.Tx) error {
+      for start := 0; start < len(batch); start += s.stocksBatchSize {
+        end := start + s.stocksBatchSize
+        if end > len(batch) {
+          end = len(batch)
+        }
+        builder := sq.Insert(`stock`).
+          Columns(stockColumns...).
+          Suffix(`ON CONFLICT (stock_id) DO NOTHING`).
+          PlaceholderFormat(sq.Dollar)
+
+        for _, stock := range batch[start:end] {
+          builder = builder.Values(stockValues(stock)...)
+        }
+        batchInserted, err := doInsertQueryTx(s.ctx, tx, builder)
+        if err != nil {
+          return fmt.Errorf("cannot put stocks batch: %v", err)
+        }
+        inserted += batchInserted
+      }
+      if checkpoint == nil {
+        return nil
+      }
+      if err := doPutQueryTx(s.ctx, tx, buildPutCheckpointQuery(checkpoint)); err != nil {
+        return fmt.Errorf("cannot put fetcher checkpoint: %v", err)
+      }
+      return nil
+    }); err != nil {
+    return 0, err
+  }
+  if inserted == 0 {
+    return 0, nil
+  }
+  log.Infof("put %d stocks for ticker '%s' in storage. total: %d",
+    inserted, batch[0].TickerId, s.counters.stock.Add(uint64(inserted)))
+
+  return inserted, nil
+}
+
+// BackfillStocksGranularity set daily granularity of the bars stored before granularities were introduced.
+// daily bars keep legacy ids, so they are not overwritten on fetching and stay without granularity otherwise
+func (s *storage) BackfillStocksGranularity() (int64, error) {
+  builder := sq.Update(`stock`).
+    Set(`timespan`, defaultTimespan).
+    Set(`multiplier`, defaultMultiplier).
+    Where(sq.Eq{`timespan`: nil}).
     PlaceholderFormat(sq.Dollar)
 
-  if err := s.doPutQuery(builder); err != nil {
-    return err
-  }
-  log.Infof("put stock '%s' for ticker '%s' in storage. total: %d",
-    stock.StockId, stock.TickerId, s.counters.stock.Add(counterInc))
+  query, args := mustBuildQuery(builder)
 
-  return nil
+  tag, err := s.client.Exec(s.ctx, query, args...)
+  if err != nil {
+    return 0, fmt.Errorf("cannot do exec: %v", err)
+  }
+  return tag.RowsAffected(), nil
 }
 
 func (s *storage) doPutQuery(builder queryBuilder) error {
@@ -183,6 +439,25 @@ func (s *storage) doPutQuery(builder queryBuilder) error {
   return nil
 }
 
+func doPutQueryTx(ctx context.Context, tx pgx.Tx, builder queryBuilder) error {
+  query, args := mustBuildQuery(builder)
+  if _, err := tx.Exec(ctx, query, args...); err != nil {
+    return fmt.Errorf("cannot do transaction exec: %v", err)
+  }
+  return nil
+}
+
+// doInsertQueryTx return count of inserted rows, rows skipped on conflict are not counted
+func doInsertQueryTx(ctx context.Context, tx pgx.Tx, builder queryBuilder) (int64, error) {
+  query, args := mustBuildQuery(builder)
+
+  tag, err := tx.Exec(ctx, query, args...)
+  if err != nil {
+    return 0, fmt.Errorf("cannot do transaction exec: %v", err)
+  }
+  return tag.RowsAffected(), nil
+}
+
 func mustBuildQuery(builder queryBuilder) (string, []any) {
   query, args, err := builder.ToSql()
   if err != nil {
@@ -191,10 +466,11 @@ func mustBuildQuery(builder queryBuilder) (string, []any) {
   return query, args
 }
 
+// PutFetcherState overwrite the single fetcher state row
 func (s *storage) PutFetcherState(state *domain.FetcherState) error {
   builder := sq.Insert(`fetcher_state`).
     Columns(
-      // `fetcher_state_id` is serial type, autoincrement
+      `state_id`,
       `ticker_req_url`,
       `ticker_details_req_url`,
       `stock_req_url`,
@@ -202,26 +478,30 @@ func (s *storage) PutFetcherState(state *domain.FetcherState) error {
       `finished`,
     ).
     Values(
+      fetcherStateId,
       state.TickerReqUrl,
       state.TickerDetailsReqUrl,
       state.StockReqUrl,
       state.CreatedAt,
       state.Finished,
     ).
+    Suffix(`ON CONFLICT (state_id) DO UPDATE SET
+      ticker_req_url = EXCLUDED.ticker_req_url,
+      ticker_details_req_url = EXCLUDED.ticker_details_req_url,
+      stock_req_url = EXCLUDED.stock_req_url,
+      created_at = EXCLUDED.created_at,
+      finished = EXCLUDED.finished`).
     PlaceholderFormat(sq.Dollar)
 
   if err := s.doPutQuery(builder); err != nil {
     return err
   }
-  log.Infof("sucessfully put fetcher state to storage")
+  log.Debugf("sucessfully put fetcher state to storage")
 
   return nil
 }
 
 func (s *storage) GetFetcherState() (*domain.FetcherState, bool, error) {
-  const (
-    queryLimit = 5
-  )
   builder := sq.Select(
     `state_id`,
     `ticker_req_url`,
@@ -232,7 +512,7 @@ func (s *storage) GetFetcherState() (*domain.FetcherState, bool, error) {
   ).
     From(`fetcher_state`).
     OrderBy(`created_at DESC`).
-    Limit(queryLimit).
+    Limit(1).
     PlaceholderFormat(sq.Dollar)
 
   state := &domain.FetcherState{}
@@ -262,6 +542,81 @@ func (s *storage) GetFetcherState() (*domain.FetcherState, bool, error) {
   return state, true, nil
 }
 
+func (s *storage) PutFetcherCheckpoint(checkpoint *domain.FetcherCheckpoint) error {
+  if checkpoint == nil {
+    return fmt.Errorf("fetcher checkpoint is a nil")
+  }
+  return s.doPutQuery(buildPutCheckpointQuery(checkpoint))
+}
+
+func buildPutCheckpointQuery(checkpoint *domain.FetcherCheckpoint) queryBuilder {
+  return sq.Insert(`fetcher_checkpoint`).
+    Columns(
+      `ticker_id`,
+      `timespan`,
+      `multiplier`,
+      `last_bar_at`,
+      `cursor`,
+      `last_error`,
+      `updated_at`,
+    ).
+    Values(
+      checkpoint.TickerId,
+      checkpoint.Timespan,
+      checkpoint.Multiplier,
+      checkpoint.LastBarAt,
+      checkpoint.Cursor,
+      checkpoint.LastError,
+      checkpoint.UpdatedAt,
+    ).
+    Suffix(`ON CONFLICT (ticker_id, timespan, multiplier) DO UPDATE SET
+      last_bar_at = EXCLUDED.last_bar_at,
+      cursor = EXCLUDED.cursor,
+      last_error = EXCLUDED.last_error,
+      updated_at = EXCLUDED.updated_at`).
+    PlaceholderFormat(sq.Dollar)
+}
+
+func (s *storage) GetFetcherCheckpoint(tickerId, timespan string, multiplier int) (*domain.FetcherCheckpoint, bool, error) {
+  builder := sq.Select(
+    `ticker_id`,
+    `timespan`,
+    `multiplier`,
+    `last_bar_at`,
+    `cursor`,
+    `last_error`,
+    `updated_at`,
+  ).
+    From(`fetcher_checkpoint`).
+    Where(sq.Eq{
+      `ticker_id`:  tickerId,
+      `timespan`:   timespan,
+      `multiplier`: multiplier,
+    }).
+    PlaceholderFormat(sq.Dollar)
+
+  checkpoint := &domain.FetcherCheckpoint{}
+  var (
+    found bool
+    err   error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    found, err = scanFirstQueriedRow(rows,
+      &checkpoint.TickerId,
+      &checkpoint.Timespan,
+      &checkpoint.Multiplier,
+      &checkpoint.LastBarAt,
+      &checkpoint.Cursor,
+      &checkpoint.LastError,
+      &checkpoint.UpdatedAt,
+    )
+    return err
+  }); err != nil {
+    return nil, false, err
+  }
+  return checkpoint, found, nil
+}
+
 func (s *storage) GetTickers() ([]*domain.Ticker, error) {
   builder := sq.Select(
     `ticker_id`,
@@ -310,6 +665,123 @@ func (s *storage) GetTickers() ([]*domain.Ticker, error) {
   return tickers, nil
 }
 
+// GetWatchedTickerIds return tickers with active client subscriptions
+func (s *storage) GetWatchedTickerIds() ([]string, error) {
+  builder := sq.Select(
+    `DISTINCT ticker_id`,
+  ).
+    From(`subscription`).
+    Where(sq.Eq{
+      `active`: true,
+    }).
+    OrderBy(`ticker_id`).
+    PlaceholderFormat(sq.Dollar)
+
+  var (
+    tickerIds []string
+    found     bool
+    err       error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      var tickerId string
+
+      if found, err = scanQueriedRow(rows, &tickerId); err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      tickerIds = append(tickerIds, tickerId)
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return tickerIds, nil
+}
+
+// GetSubscriptionCounts return count of active client subscriptions per ticker
+func (s *storage) GetSubscriptionCounts() (map[string]int, error) {
+  builder := sq.Select(
+    `ticker_id`,
+    `count(*)`,
+  ).
+    From(`subscription`).
+    Where(sq.Eq{
+      `active`: true,
+    }).
+    GroupBy(`ticker_id`).
+    PlaceholderFormat(sq.Dollar)
+
+  var (
+    counts = map[string]int{}
+    found  bool
+    err    error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      var (
+        tickerId string
+        count    int
+      )
+      if found, err = scanQueriedRow(rows, &tickerId, &count); err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      counts[tickerId] = count
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return counts, nil
+}
+
+// GetTickersActivity return the last stored bar and the last refresh time per ticker
+func (s *storage) GetTickersActivity() (map[string]*domain.TickerActivity, error) {
+  builder := sq.Select(
+    `ticker_id`,
+    `max(last_bar_at)`,
+    `max(updated_at)`,
+  ).
+    From(`fetcher_checkpoint`).
+    GroupBy(`ticker_id`).
+    PlaceholderFormat(sq.Dollar)
+
+  var (
+    activities = map[string]*domain.TickerActivity{}
+    found      bool
+    err        error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      activity := &domain.TickerActivity{}
+
+      if found, err = scanQueriedRow(rows,
+        &activity.TickerId,
+        &activity.LastBarAt,
+        &activity.RefreshedAt,
+      ); err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      activities[activity.TickerId] = activity
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return activities, nil
+}
+
 func (s *storage) doGetQuery(builder queryBuilder, handler func(rows pgx.Rows) error) error {
   query, args := mustBuildQuery(builder)
   rows, err := s.client.Query(s.ctx, query, args...)
@@ -319,6 +791,17 @@ func (s *storage) doGetQuery(builder queryBuilder, handler func(rows pgx.Rows) e
   return handler(rows)
 }
 
+func (s *storage) doGetQueryTx(tx pgx.Tx, builder queryBuilder, handler func(rows pgx.Rows) error) error {
+  query, args := mustBuildQuery(builder)
+  rows, err := tx.Query(s.ctx, query, args...)
+  if err != nil {
+    return fmt.Errorf("cannot do transaction query: %v", err)
+  }
+  defer rows.Close()
+
+  return handler(rows)
+}
+
 func scanFirstQueriedRow(rows pgx.Rows, fields ...any) (bool, error) {
   var (
     hasRows bool
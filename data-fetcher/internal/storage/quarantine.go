@@ -0,0 +1,174 @@
+package storage
+
+import (
+  "fmt"
+  "main/internal/domain"
+  "time"
+
+  sq "github.com/Masterminds/squirrel"
+  "github.com/jackc/pgx/v4"
+  log "github.com/sirupsen/logrus"
+)
+
+// GetLastStock return the last stored stock with specified granularity stocked before the time
+func (s *storage) GetLastStock(tickerId, timespan string, multiplier int, before time.Time) (*domain.Stock, bool, error) {
+  builder := sq.Select(
+    `stock_id`,
+    `ticker_id`,
+    `open_price`,
+    `close_price`,
+    `highest_price`,
+    `lowest_price`,
+    `coalesce(adj_close_price, close_price)`,
+    `stocked_at`,
+  ).
+    From(`stock`).
+    Where(sq.And{
+      sq.Eq{
+        `ticker_id`:  tickerId,
+        `timespan`:   timespan,
+        `multiplier`: multiplier,
+      },
+      sq.Lt{
+        `stocked_at`: before,
+      },
+    }).
+    OrderBy(`stocked_at DESC`).
+    Limit(1).
+    PlaceholderFormat(sq.Dollar)
+
+  stock := &domain.Stock{}
+  var (
+    found bool
+    err   error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    found, err = scanFirstQueriedRow(rows,
+      &stock.StockId,
+      &stock.TickerId,
+      &stock.OpenPrice,
+      &stock.ClosePrice,
+      &stock.HighestPrice,
+      &stock.LowestPrice,
+      &stock.AdjClosePrice,
+      &stock.StockedAt,
+    )
+    return err
+  }); err != nil {
+    return nil, false, err
+  }
+  return stock, found, nil
+}
+
+func (s *storage) PutStockQuarantine(quarantined []*domain.StockQuarantine) error {
+  builder := sq.Insert(`stock_quarantine`).
+    Columns(
+      `quarantine_id`,
+      `stock_id`,
+      `ticker_id`,
+      `timespan`,
+      `multiplier`,
+      `open_price`,
+      `close_price`,
+      `highest_price`,
+      `lowest_price`,
+      `trading_volume`,
+      `stocked_at`,
+      `rule`,
+      `reason`,
+      `created_at`,
+    ).
+    Suffix(`ON CONFLICT (quarantine_id) DO UPDATE SET
+      reason = EXCLUDED.reason,
+      created_at = EXCLUDED.created_at`).
+    PlaceholderFormat(sq.Dollar)
+
+  // duplicates of the same stock have the same id,
+  // and one insert query cannot update the same row twice, so the last one is kept
+  indexes := make(map[string]int, len(quarantined))
+  unique := make([]*domain.StockQuarantine, 0, len(quarantined))
+
+  for _, q := range quarantined {
+    if q == nil {
+      continue
+    }
+    if idx, ok := indexes[q.QuarantineId]; ok {
+      unique[idx] = q
+      continue
+    }
+    indexes[q.QuarantineId] = len(unique)
+    unique = append(unique, q)
+  }
+  if len(unique) == 0 {
+    return nil
+  }
+  for _, q := range unique {
+    builder = builder.Values(
+      q.QuarantineId,
+      q.StockId,
+      q.TickerId,
+      q.Timespan,
+      q.Multiplier,
+      q.OpenPrice,
+      q.ClosePrice,
+      q.HighestPrice,
+      q.LowestPrice,
+      q.TradingVolume,
+      q.StockedAt,
+      q.Rule,
+      q.Reason,
+      q.CreatedAt,
+    )
+  }
+  if err := s.doPutQuery(builder); err != nil {
+    return fmt.Errorf("cannot put quarantined stocks: %v", err)
+  }
+  log.Infof("put %d quarantined stocks to storage", len(unique))
+
+  return nil
+}
+
+// GetQuarantineCounts return count of quarantined stocks per validation rule
+func (s *storage) GetQuarantineCounts(tickerId string) ([]*domain.QuarantineCount, error) {
+  builder := sq.Select(
+    `rule`,
+    `count(*)`,
+  ).
+    From(`stock_quarantine`).
+    GroupBy(`rule`).
+    OrderBy(`rule`).
+    PlaceholderFormat(sq.Dollar)
+
+  if tickerId != "" {
+    builder = builder.Where(sq.Eq{
+      `ticker_id`: tickerId,
+    })
+  }
+
+  var (
+    counts []*domain.QuarantineCount
+    found  bool
+    err    error
+  )
+  if err = s.doGetQuery(builder, func(rows pgx.Rows) error {
+    for {
+      count := &domain.QuarantineCount{}
+
+      if found, err = scanQueriedRow(rows,
+        &count.Rule,
+        &count.Count,
+      ); err != nil {
+        return err
+      }
+      if !found {
+        break
+      }
+      counts = append(counts, count)
+    }
+    return nil
+
+  }); err != nil {
+    return nil, err
+  }
+  return counts, nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+  "time"
+
+  sq "github.com/Masterminds/squirrel"
+  "github.com/jackc/pgx/v4"
+)
+
+// resources of the ticker refreshed once per interval.
+// refresh time is stored apart from the resource rows, so ticker without rows is not requested every time
+const (
+  RefreshCorporateActions = "corporate_actions"
+  RefreshFinancials       = "financials"
+)
+
+// GetTickerRefreshedAt return time of the last successful refresh of the ticker resource
+func (s *storage) GetTickerRefreshedAt(tickerId, resource string) (time.Time, bool, error) {
+  builder := sq.Select(
+    `refreshed_at`,
+  ).
+    From(`ticker_refresh`).
+    Where(sq.Eq{
+      `ticker_id`: tickerId,
+      `resource`:  resource,
+    }).
+    PlaceholderFormat(sq.Dollar)
+
+  var refreshedAt *time.Time
+
+  if err := s.doGetQuery(builder, func(rows pgx.Rows) error {
+    _, err := scanFirstQueriedRow(rows, &refreshedAt)
+    return err
+  }); err != nil {
+    return time.Time{}, false, err
+  }
+  if refreshedAt == nil {
+    return time.Time{}, false, nil
+  }
+  return *refreshedAt, true, nil
+}
+
+func (s *storage) PutTickerRefreshedAt(tickerId, resource string, refreshedAt time.Time) error {
+  builder := sq.Insert(`ticker_refresh`).
+    Columns(
+      `ticker_id`,
+      `resource`,
+      `refreshed_at`,
+    ).
+    Values(
+      tickerId,
+      resource,
+      refreshedAt,
+    ).
+    Suffix(`ON CONFLICT (ticker_id, resource) DO UPDATE SET
+      refreshed_at = EXCLUDED.refreshed_at`).
+    PlaceholderFormat(sq.Dollar)
+
+  return s.doPutQuery(builder)
+}
@@ -0,0 +1,90 @@
+package stream
+
+import "time"
+
+const minuteMillis = int64(time.Minute / time.Millisecond)
+
+type barKey struct {
+  tickerId string
+  start    int64
+}
+
+// minuteAggregator build minute bars from trades. bar is completed
+// when trades watermark passed the minute end with the grace for late trades
+type minuteAggregator struct {
+  grace     int64
+  watermark int64
+  bars      map[barKey]*Bar
+}
+
+func newMinuteAggregator(grace time.Duration) *minuteAggregator {
+  return &minuteAggregator{
+    grace: int64(grace / time.Millisecond),
+    bars:  map[barKey]*Bar{},
+  }
+}
+
+func (a *minuteAggregator) add(trade *tradeEvent) {
+  if trade.Symbol == "" || trade.Price <= 0 {
+    return
+  }
+  key := barKey{
+    tickerId: trade.Symbol,
+    start:    trade.Timestamp - trade.Timestamp%minuteMillis,
+  }
+  // trades of already flushed minutes are too late, their bars are persisted
+  if key.start+minuteMillis+a.grace <= a.watermark {
+    return
+  }
+  if trade.Timestamp > a.watermark {
+    a.watermark = trade.Timestamp
+  }
+  bar, ok := a.bars[key]
+  if !ok {
+    a.bars[key] = &Bar{
+      TickerId: trade.Symbol,
+      Open:     trade.Price,
+      Close:    trade.Price,
+      Highest:  trade.Price,
+      Lowest:   trade.Price,
+      Volume:   trade.Size,
+      Start:    key.start,
+    }
+    return
+  }
+  if trade.Price > bar.Highest {
+    bar.Highest = trade.Price
+  }
+  if trade.Price < bar.Lowest {
+    bar.Lowest = trade.Price
+  }
+  // trades of the bar may come out of order, but bar close is the last received trade
+  bar.Close = trade.Price
+  bar.Volume += trade.Size
+}
+
+// flushCompleted return and forget bars which minute is completed
+func (a *minuteAggregator) flushCompleted() []*Bar {
+  return a.flush(func(bar *Bar) bool {
+    return bar.Start+minuteMillis+a.grace <= a.watermark
+  })
+}
+
+// flushAll return all bars including incomplete ones, it is used on shutdown
+func (a *minuteAggregator) flushAll() []*Bar {
+  return a.flush(func(*Bar) bool {
+    return true
+  })
+}
+
+func (a *minuteAggregator) flush(completed func(bar *Bar) bool) []*Bar {
+  var bars []*Bar
+
+  for key, bar := range a.bars {
+    if completed(bar) {
+      bars = append(bars, bar)
+      delete(a.bars, key)
+    }
+  }
+  return bars
+}
@@ -0,0 +1,271 @@
+package stream
+
+import (
+  "context"
+  "fmt"
+  "os"
+  "strings"
+  "time"
+
+  "github.com/gorilla/websocket"
+  log "github.com/sirupsen/logrus"
+)
+
+const (
+  minReconnectBackoff = 1 * time.Second
+  maxReconnectBackoff = 1 * time.Minute
+  lateTradesGrace     = 2 * time.Second
+  handshakeTimeout    = 30 * time.Second
+  // bars of failed handling are kept for retry, the oldest are dropped over the limit
+  maxPendingBars = 10000
+
+  recordFilePerm = 0644
+)
+
+// BarsHandler persist completed minute bars
+type BarsHandler func(bars []*Bar) error
+
+type Client struct {
+  url        string
+  apiToken   string
+  channel    string
+  recordPath string
+  aggregator *minuteAggregator
+  pending    []*Bar
+}
+
+func NewClient(config *Config) (*Client, error) {
+  if config.Url == "" {
+    return nil, fmt.Errorf("stream url must be specified")
+  }
+  channel := config.Channel
+  if channel == "" {
+    channel = ChannelAggregates
+  }
+  if channel != ChannelTrades && channel != ChannelAggregates {
+    return nil, fmt.Errorf("unknown stream channel '%s'. possible: %s, %s",
+      channel, ChannelTrades, ChannelAggregates)
+  }
+  return &Client{
+    url:        config.Url,
+    apiToken:   config.ApiToken,
+    channel:    channel,
+    recordPath: config.RecordPath,
+    aggregator: newMinuteAggregator(lateTradesGrace),
+  }, nil
+}
+
+// Run stream bars of the tickers until context is done and reconnect with backoff on errors.
+// bars aggregated from trades are kept between sessions, so reconnect do not split minute bars
+func (c *Client) Run(ctx context.Context, tickerIds []string, handler BarsHandler) {
+  backoff := minReconnectBackoff
+
+  for {
+    err := c.session(ctx, tickerIds, handler, func() {
+      // authenticated session reset backoff
+      backoff = minReconnectBackoff
+    })
+    if ctx.Err() != nil {
+      break
+    }
+    log.Errorf("stream session failed: %v. reconnect in %v", err, backoff)
+
+    timer := time.NewTimer(backoff)
+    select {
+    case <-ctx.Done():
+      timer.Stop()
+    case <-timer.C:
+    }
+    if ctx.Err() != nil {
+      break
+    }
+    if backoff *= 2; backoff > maxReconnectBackoff {
+      backoff = maxReconnectBackoff
+    }
+  }
+  if err := c.handle(handler, c.aggregator.flushAll()); err != nil {
+    log.Errorf("cannot handle stream bars on shutdown: %v", err)
+  }
+}
+
+// handle pass bars to the handler with pending bars of the failed handling before them.
+// bars are kept pending on handler error and retried with the next bars
+func (c *Client) handle(handler BarsHandler, bars []*Bar) error {
+  bars = append(c.pending, bars...)
+  if len(bars) == 0 {
+    return nil
+  }
+  if err := handler(bars); err != nil {
+    if dropped := len(bars) - maxPendingBars; dropped > 0 {
+      log.Warnf("stream pending bars exceed %d. dropped %d oldest bars", maxPendingBars, dropped)
+      bars = bars[dropped:]
+    }
+    c.pending = bars
+    return fmt.Errorf("%d bars pending: %v", len(bars), err)
+  }
+  c.pending = nil
+
+  return nil
+}
+
+func (c *Client) session(ctx context.Context, tickerIds []string, handler BarsHandler, onAuth func()) error {
+  dialer := &websocket.Dialer{
+    HandshakeTimeout: handshakeTimeout,
+  }
+  conn, _, err := dialer.DialContext(ctx, c.url, nil)
+  if err != nil {
+    return fmt.Errorf("cannot dial '%s': %v", c.url, err)
+  }
+  defer conn.Close()
+
+  // blocked read is interrupted by closing connection
+  done := make(chan struct{})
+  defer close(done)
+
+  go func() {
+    select {
+    case <-ctx.Done():
+      _ = conn.Close()
+    case <-done:
+    }
+  }()
+  recorder, err := c.openRecorder()
+  if err != nil {
+    return err
+  }
+  if recorder != nil {
+    defer recorder.Close()
+  }
+  if err = c.handshake(conn, tickerIds, recorder); err != nil {
+    return err
+  }
+  onAuth()
+  log.Infof("stream %s subscribed for %d tickers", c.channel, len(tickerIds))
+
+  for {
+    frame, err := c.readFrame(conn, recorder)
+    if err != nil {
+      return err
+    }
+    bars, err := c.handleFrame(frame)
+    if err != nil {
+      return err
+    }
+    if len(bars) == 0 {
+      continue
+    }
+    if err = c.handle(handler, bars); err != nil {
+      log.Errorf("cannot handle stream bars: %v", err)
+    }
+  }
+}
+
+func (c *Client) handshake(conn *websocket.Conn, tickerIds []string, recorder *os.File) error {
+  if err := c.expectStatus(conn, recorder, statusConnected); err != nil {
+    return err
+  }
+  if err := conn.WriteJSON(&actionMessage{
+    Action: actionAuth,
+    Params: c.apiToken,
+  }); err != nil {
+    return fmt.Errorf("cannot send auth action: %v", err)
+  }
+  if err := c.expectStatus(conn, recorder, statusAuthSuccess); err != nil {
+    return err
+  }
+  if err := conn.WriteJSON(&actionMessage{
+    Action: actionSubscribe,
+    Params: c.subscribeParams(tickerIds),
+  }); err != nil {
+    return fmt.Errorf("cannot send subscribe action: %v", err)
+  }
+  return nil
+}
+
+// subscribeParams form polygon channels, e.g. 'AM.AAPL,AM.MSFT'
+func (c *Client) subscribeParams(tickerIds []string) string {
+  prefix := eventAggregate
+  if c.channel == ChannelTrades {
+    prefix = eventTrade
+  }
+  params := make([]string, 0, len(tickerIds))
+
+  for _, tickerId := range tickerIds {
+    params = append(params, fmt.Sprint(prefix, ".", tickerId))
+  }
+  return strings.Join(params, ",")
+}
+
+func (c *Client) expectStatus(conn *websocket.Conn, recorder *os.File, expected string) error {
+  frame, err := c.readFrame(conn, recorder)
+  if err != nil {
+    return err
+  }
+  parsed, err := parseFrame(frame)
+  if err != nil {
+    return err
+  }
+  for _, status := range parsed.statuses {
+    switch status.Status {
+    case expected:
+      return nil
+    case statusAuthFailed, statusMaxConnection:
+      return fmt.Errorf("stream status '%s': %s", status.Status, status.Message)
+    }
+  }
+  return fmt.Errorf("expected stream status '%s' not received", expected)
+}
+
+func (c *Client) readFrame(conn *websocket.Conn, recorder *os.File) ([]byte, error) {
+  _, frame, err := conn.ReadMessage()
+  if err != nil {
+    return nil, fmt.Errorf("cannot read frame: %v", err)
+  }
+  if recorder != nil {
+    if err = writeRecordedFrame(recorder, frame); err != nil {
+      log.Errorf("cannot record stream frame: %v", err)
+    }
+  }
+  return frame, nil
+}
+
+func (c *Client) handleFrame(frame []byte) ([]*Bar, error) {
+  parsed, err := parseFrame(frame)
+  if err != nil {
+    return nil, err
+  }
+  for _, status := range parsed.statuses {
+    log.Infof("stream status '%s': %s", status.Status, status.Message)
+  }
+  bars := make([]*Bar, 0, len(parsed.aggregates))
+
+  for _, aggregate := range parsed.aggregates {
+    bars = append(bars, &Bar{
+      TickerId: aggregate.Symbol,
+      Open:     aggregate.Open,
+      Close:    aggregate.Close,
+      Highest:  aggregate.High,
+      Lowest:   aggregate.Low,
+      Volume:   aggregate.Volume,
+      Start:    aggregate.Start,
+    })
+  }
+  for _, trade := range parsed.trades {
+    c.aggregator.add(trade)
+  }
+  if len(parsed.trades) != 0 {
+    bars = append(bars, c.aggregator.flushCompleted()...)
+  }
+  return bars, nil
+}
+
+func (c *Client) openRecorder() (*os.File, error) {
+  if c.recordPath == "" {
+    return nil, nil
+  }
+  recorder, err := os.OpenFile(c.recordPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, recordFilePerm)
+  if err != nil {
+    return nil, fmt.Errorf("cannot open stream record file '%s': %v", c.recordPath, err)
+  }
+  return recorder, nil
+}
@@ -0,0 +1,88 @@
+package stream
+
+import (
+  "context"
+  "errors"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestClientRunRetriesFailedBars(t *testing.T) {
+  frames, err := LoadRecordedFrames("testdata/aggregates.jsonl")
+  if err != nil {
+    t.Fatalf("cannot load recorded frames: %v", err)
+  }
+  if len(frames) != 2 {
+    t.Fatalf("expected 2 frames with bars, got %d", len(frames))
+  }
+  server := httptest.NewServer(StubHandler(frames))
+  defer server.Close()
+
+  client, err := NewClient(&Config{
+    Url:     strings.Replace(server.URL, "http://", "ws://", 1),
+    Channel: ChannelAggregates,
+  })
+  if err != nil {
+    t.Fatalf("cannot create client: %v", err)
+  }
+  ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+  defer cancel()
+
+  var calls [][]*Bar
+
+  client.Run(ctx, []string{"AAPL", "MSFT"}, func(bars []*Bar) error {
+    calls = append(calls, bars)
+    // storage is down on the first batch
+    if len(calls) == 1 {
+      return errors.New("storage is unavailable")
+    }
+    cancel()
+    return nil
+  })
+
+  if len(calls) != 2 {
+    t.Fatalf("expected 2 handler calls, got %d", len(calls))
+  }
+  expected := []struct {
+    tickerId string
+    start    int64
+  }{
+    {"AAPL", 1696944600000},
+    {"MSFT", 1696944600000},
+    {"AAPL", 1696944660000},
+  }
+  retried := calls[1]
+
+  if len(retried) != len(expected) {
+    t.Fatalf("expected %d bars with retried ones, got %d", len(expected), len(retried))
+  }
+  for idx, bar := range retried {
+    if bar.TickerId != expected[idx].tickerId || bar.Start != expected[idx].start {
+      t.Errorf("bar %d: expected %s at %d, got %s at %d",
+        idx, expected[idx].tickerId, expected[idx].start, bar.TickerId, bar.Start)
+    }
+  }
+  if len(client.pending) != 0 {
+    t.Errorf("expected no pending bars after successful handling, got %d", len(client.pending))
+  }
+}
+
+func TestClientHandleDropsOldestPendingBars(t *testing.T) {
+  client := &Client{}
+  bars := make([]*Bar, maxPendingBars+1)
+
+  for idx := range bars {
+    bars[idx] = &Bar{TickerId: "AAPL", Start: int64(idx) * minuteMillis}
+  }
+  if err := client.handle(func([]*Bar) error { return errors.New("storage is unavailable") }, bars); err == nil {
+    t.Fatalf("expected handler error")
+  }
+  if len(client.pending) != maxPendingBars {
+    t.Fatalf("expected %d pending bars, got %d", maxPendingBars, len(client.pending))
+  }
+  if client.pending[0].Start != minuteMillis {
+    t.Errorf("expected the oldest bar dropped, first pending bar start %d", client.pending[0].Start)
+  }
+}
@@ -0,0 +1,13 @@
+package stream
+
+type Config struct {
+  Enabled  bool   `yaml:"enabled"`
+  Url      string `yaml:"url"`
+  ApiToken string `yaml:"api_token"`
+  // channel 'trades' aggregate trades to minute bars, 'aggregates' receive minute bars as is
+  Channel string `yaml:"channel"`
+  // tickers with active subscriptions are streamed if not specified
+  Tickers []string `yaml:"tickers"`
+  // received frames are appended to the file to replay them by the stub
+  RecordPath string `yaml:"record_path"`
+}
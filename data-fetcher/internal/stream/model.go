@@ -0,0 +1,116 @@
+package stream
+
+import (
+  "encoding/json"
+  "fmt"
+)
+
+const (
+  ChannelTrades     = "trades"
+  ChannelAggregates = "aggregates"
+)
+
+// polygon websocket event types and statuses
+const (
+  eventStatus    = "status"
+  eventTrade     = "T"
+  eventAggregate = "AM"
+
+  statusConnected     = "connected"
+  statusAuthSuccess   = "auth_success"
+  statusAuthFailed    = "auth_failed"
+  statusSuccess       = "success"
+  statusMaxConnection = "max_connections"
+
+  actionAuth      = "auth"
+  actionSubscribe = "subscribe"
+)
+
+// Bar is minute bar of the ticker, start is unix milliseconds of the minute start
+type Bar struct {
+  TickerId string
+  Open     float64
+  Close    float64
+  Highest  float64
+  Lowest   float64
+  Volume   float64
+  Start    int64
+}
+
+type actionMessage struct {
+  Action string `json:"action"`
+  Params string `json:"params"`
+}
+
+type eventHeader struct {
+  Event string `json:"ev"`
+}
+
+type statusEvent struct {
+  Status  string `json:"status"`
+  Message string `json:"message"`
+}
+
+type tradeEvent struct {
+  Symbol    string  `json:"sym"`
+  Price     float64 `json:"p"`
+  Size      float64 `json:"s"`
+  Timestamp int64   `json:"t"`
+}
+
+type aggregateEvent struct {
+  Symbol string  `json:"sym"`
+  Open   float64 `json:"o"`
+  Close  float64 `json:"c"`
+  High   float64 `json:"h"`
+  Low    float64 `json:"l"`
+  Volume float64 `json:"v"`
+  Start  int64   `json:"s"`
+}
+
+// parsedFrame hold events of one websocket frame, polygon send events in array
+type parsedFrame struct {
+  statuses   []*statusEvent
+  trades     []*tradeEvent
+  aggregates []*aggregateEvent
+}
+
+func parseFrame(frame []byte) (*parsedFrame, error) {
+  var rawEvents []json.RawMessage
+
+  if err := json.Unmarshal(frame, &rawEvents); err != nil {
+    return nil, fmt.Errorf("cannot unmarshal frame: %v", err)
+  }
+  parsed := &parsedFrame{}
+
+  for _, rawEvent := range rawEvents {
+    header := &eventHeader{}
+
+    if err := json.Unmarshal(rawEvent, header); err != nil {
+      return nil, fmt.Errorf("cannot unmarshal event header: %v", err)
+    }
+    var target any
+
+    switch header.Event {
+    case eventStatus:
+      status := &statusEvent{}
+      parsed.statuses = append(parsed.statuses, status)
+      target = status
+    case eventTrade:
+      trade := &tradeEvent{}
+      parsed.trades = append(parsed.trades, trade)
+      target = trade
+    case eventAggregate:
+      aggregate := &aggregateEvent{}
+      parsed.aggregates = append(parsed.aggregates, aggregate)
+      target = aggregate
+    default:
+      // other events are not used
+      continue
+    }
+    if err := json.Unmarshal(rawEvent, target); err != nil {
+      return nil, fmt.Errorf("cannot unmarshal '%s' event: %v", header.Event, err)
+    }
+  }
+  return parsed, nil
+}
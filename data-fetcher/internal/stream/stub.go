@@ -0,0 +1,117 @@
+package stream
+
+import (
+  "bufio"
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "os"
+
+  "github.com/gorilla/websocket"
+  log "github.com/sirupsen/logrus"
+)
+
+// recorded stream is file with one compacted frame per line
+func writeRecordedFrame(recorder *os.File, frame []byte) error {
+  buf := &bytes.Buffer{}
+
+  if err := json.Compact(buf, frame); err != nil {
+    return fmt.Errorf("cannot compact frame: %v", err)
+  }
+  buf.WriteByte('\n')
+
+  if _, err := recorder.Write(buf.Bytes()); err != nil {
+    return fmt.Errorf("cannot write frame: %v", err)
+  }
+  return nil
+}
+
+// LoadRecordedFrames read frames of the recorded stream skipping status events,
+// the stub send its own statuses on handshake
+func LoadRecordedFrames(path string) ([][]byte, error) {
+  file, err := os.Open(path)
+  if err != nil {
+    return nil, fmt.Errorf("cannot open recorded stream '%s': %v", path, err)
+  }
+  defer file.Close()
+
+  var frames [][]byte
+  scanner := bufio.NewScanner(file)
+
+  for scanner.Scan() {
+    frame := bytes.TrimSpace(scanner.Bytes())
+    if len(frame) == 0 {
+      continue
+    }
+    parsed, err := parseFrame(frame)
+    if err != nil {
+      return nil, fmt.Errorf("malformed recorded frame: %v", err)
+    }
+    if len(parsed.trades) == 0 && len(parsed.aggregates) == 0 {
+      continue
+    }
+    frames = append(frames, append([]byte(nil), frame...))
+  }
+  if err = scanner.Err(); err != nil {
+    return nil, fmt.Errorf("cannot read recorded stream: %v", err)
+  }
+  return frames, nil
+}
+
+// StubHandler serve polygon websocket protocol locally: accept any auth and subscription,
+// replay the frames and close the connection. it is used with httptest server in tests
+func StubHandler(frames [][]byte) http.Handler {
+  upgrader := websocket.Upgrader{}
+
+  return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    conn, err := upgrader.Upgrade(w, r, nil)
+    if err != nil {
+      log.Errorf("stream stub cannot upgrade connection: %v", err)
+      return
+    }
+    defer conn.Close()
+
+    if err = serveStub(conn, frames); err != nil {
+      log.Errorf("stream stub failed: %v", err)
+    }
+  })
+}
+
+func serveStub(conn *websocket.Conn, frames [][]byte) error {
+  if err := writeStubStatus(conn, statusConnected, "Connected Successfully"); err != nil {
+    return err
+  }
+  for _, reply := range []string{statusAuthSuccess, statusSuccess} {
+    action := &actionMessage{}
+
+    if err := conn.ReadJSON(action); err != nil {
+      return fmt.Errorf("cannot read action: %v", err)
+    }
+    if err := writeStubStatus(conn, reply, fmt.Sprintf("%s: %s", action.Action, action.Params)); err != nil {
+      return err
+    }
+  }
+  for _, frame := range frames {
+    if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+      return fmt.Errorf("cannot write frame: %v", err)
+    }
+  }
+  closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "recorded stream finished")
+
+  if err := conn.WriteMessage(websocket.CloseMessage, closeMessage); err != nil {
+    return fmt.Errorf("cannot write close message: %v", err)
+  }
+  return nil
+}
+
+func writeStubStatus(conn *websocket.Conn, status, message string) error {
+  if err := conn.WriteJSON([]map[string]string{{
+    "ev":      eventStatus,
+    "status":  status,
+    "message": message,
+  }}); err != nil {
+    return fmt.Errorf("cannot write '%s' status: %v", status, err)
+  }
+  return nil
+}
@@ -5,11 +5,9 @@ import (
   "flag"
   "fmt"
   "main/internal/handler"
-  "os"
-  "os/signal"
-  "syscall"
 
   "github.com/UshakovN/stock-predictor-service/config"
+  "github.com/UshakovN/stock-predictor-service/lifecycle"
   log "github.com/sirupsen/logrus"
 )
 
@@ -26,7 +24,8 @@ import (
 // @name X-Auth-Token
 //
 func main() {
-  ctx := context.Background()
+  lc := lifecycle.New()
+  ctx := lc.Context()
 
   servePort := flag.String("port", "8083", "serving port")
   serveHost := flag.String("host", "", "host prefix for serve media content")
@@ -39,24 +38,27 @@ func main() {
   }
   hostPrefix := fmt.Sprintf("%s:%s", *serveHost, *servePort)
 
-  h, err := handler.NewHandler(ctx, hostPrefix, cfg)
+  // clients are closed on shutdown after the queue consuming stopped
+  h, err := handler.NewHandler(lifecycle.WithoutCancel(ctx), hostPrefix, cfg)
   if err != nil {
     log.Fatalf("cannot create new handler: %v", err)
   }
   h.BindRouter()
 
-  go h.ContinuouslyServeHttp(*servePort)
+  lc.ServeHttp(*servePort)
   log.Infof("ready for serve http on port: %s", *servePort)
 
-  go h.ContinuouslyServeQueue()
+  lc.Go("queue consuming", func() {
+    if err := h.ContinuouslyServeQueue(ctx); err != nil {
+      log.Errorf("queue consuming stopped: %v", err)
+      lc.Stop()
+    }
+  })
   log.Println("ready for serve message queue")
 
-  serviceShutdown()
-}
-
-func serviceShutdown() {
-  exitSignal := make(chan os.Signal)
-  signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
+  lc.OnShutdown("media service clients", func(context.Context) error {
+    return h.Close()
+  })
 
-  <-exitSignal
+  lc.Wait()
 }
@@ -20,7 +20,6 @@ import (
   "github.com/UshakovN/stock-predictor-service/hash"
   "github.com/UshakovN/stock-predictor-service/swagger"
   "github.com/UshakovN/stock-predictor-service/utils"
-  log "github.com/sirupsen/logrus"
 )
 
 const fromMediaServiceHttp = "mediaservice_http"
@@ -213,18 +212,16 @@ func (h *Handler) HandleHealth(w http.ResponseWriter, _ *http.Request) error {
   return nil
 }
 
-func (h *Handler) ContinuouslyServeHttp(port string) {
-  err := http.ListenAndServe(fmt.Sprint(":", port), nil)
-  if err != nil {
-    log.Fatalf("listen and serve error: %v", err)
+// ContinuouslyServeQueue handle queue messages until context is done
+func (h *Handler) ContinuouslyServeQueue(ctx context.Context) error {
+  if err := h.service.HandleQueueMessages(ctx); err != nil {
+    return fmt.Errorf("handle queue messages error: %v", err)
   }
+  return nil
 }
 
-func (h *Handler) ContinuouslyServeQueue() {
-  err := h.service.HandleQueueMessages()
-  if err != nil {
-    log.Fatalf("handle queue messages error: %v", err)
-  }
+func (h *Handler) Close() error {
+  return h.service.Close()
 }
 
 func (h *Handler) formGetResponse(media *domain.Media) *mediaservice.GetResponse {
@@ -36,7 +36,8 @@ type MessageHandler func(message *domain.PutMessage) error
 
 type MediaServiceQueue interface {
   PublishMessage(message *domain.PutMessage) error
-  ConsumeMessages(handler MessageHandler) error
+  ConsumeMessages(ctx context.Context, handler MessageHandler) error
+  Close() error
 }
 
 type mediaServiceQueue struct {
@@ -96,7 +97,9 @@ func (msq *mediaServiceQueue) PublishMessage(message *domain.PutMessage) error {
   return nil
 }
 
-func (msq *mediaServiceQueue) ConsumeMessages(handler MessageHandler) error {
+// ConsumeMessages handle deliveries until context is done.
+// the handled delivery is acknowledged before the consuming stop
+func (msq *mediaServiceQueue) ConsumeMessages(ctx context.Context, handler MessageHandler) error {
   if handler == nil {
     return fmt.Errorf("message handler is a nil")
   }
@@ -115,51 +118,65 @@ func (msq *mediaServiceQueue) ConsumeMessages(handler MessageHandler) error {
   if err != nil {
     return fmt.Errorf("cannot consume messages from '%s' queue", msq.key)
   }
+  for {
+    select {
+    case <-ctx.Done():
+      log.Infof("consuming messages from '%s' queue stopped", msq.key)
+      return nil
+
+    case delivery, ok := <-consumerChan:
+      if !ok {
+        return fmt.Errorf("consumer channel of '%s' queue closed", msq.key)
+      }
+      handleDelivery(delivery, handler)
+    }
+  }
+}
+
+func handleDelivery(delivery ampq.Delivery, handler MessageHandler) {
   const (
     handlerRetryCount   = 5
     handlerWaitInterval = 1 * time.Second
   )
-  go func() {
-    for delivery := range consumerChan {
-      // form domain message
-      message, err := formMessageFromDelivery(delivery)
-      if err != nil {
-        log.Errorf("malformed message in delivery: %v", err)
-        continue
-      }
-      // format message description
-      messageDesc := fmt.Sprintf("message with name '%s' for section '%s'",
-        message.MetaInfo.Name, message.MetaInfo.Section)
-
-      // handle queue messages with retries
-      err = utils.DoWithRetry(func() error {
-        if err := handler(message); err != nil {
-          return fmt.Errorf("cannot handle %s. error: %v", messageDesc, err)
-        }
-        return nil
-      },
-        &utils.RetryOption{
-          RetryCount:   handlerRetryCount,
-          WaitInterval: handlerWaitInterval,
-        })
-      if err != nil {
-        log.Errorf("handle failed for %s. error: %v", messageDesc, err)
-
-        // send delivery negative acknowledgement to consumer and do requeue
-        if err = delivery.Nack(ackNoMultiple, ackRequeue); err != nil {
-          log.Errorf("cannot nack consumer about not handled delivery: %v", err)
-        }
-        continue
-      }
-      // send delivery acknowledgement to consumer
-      if err = delivery.Ack(ackNoMultiple); err != nil {
-        log.Errorf("cannot ack consumer about handled delivery: %v", err)
-      }
-      log.Infof("successfully handled: %s", messageDesc)
+  // form domain message
+  message, err := formMessageFromDelivery(delivery)
+  if err != nil {
+    log.Errorf("malformed message in delivery: %v", err)
+    return
+  }
+  // format message description
+  messageDesc := fmt.Sprintf("message with name '%s' for section '%s'",
+    message.MetaInfo.Name, message.MetaInfo.Section)
+
+  // handle queue messages with retries
+  err = utils.DoWithRetry(func() error {
+    if err := handler(message); err != nil {
+      return fmt.Errorf("cannot handle %s. error: %v", messageDesc, err)
     }
-  }()
+    return nil
+  },
+    &utils.RetryOption{
+      RetryCount:   handlerRetryCount,
+      WaitInterval: handlerWaitInterval,
+    })
+  if err != nil {
+    log.Errorf("handle failed for %s. error: %v", messageDesc, err)
 
-  return nil
+    // send delivery negative acknowledgement to consumer and do requeue
+    if err = delivery.Nack(ackNoMultiple, ackRequeue); err != nil {
+      log.Errorf("cannot nack consumer about not handled delivery: %v", err)
+    }
+    return
+  }
+  // send delivery acknowledgement to consumer
+  if err = delivery.Ack(ackNoMultiple); err != nil {
+    log.Errorf("cannot ack consumer about handled delivery: %v", err)
+  }
+  log.Infof("successfully handled: %s", messageDesc)
+}
+
+func (msq *mediaServiceQueue) Close() error {
+  return msq.mq.Close()
 }
 
 func formMessageFromDelivery(delivery ampq.Delivery) (*domain.PutMessage, error) {
@@ -19,7 +19,8 @@ type MediaService interface {
   GetMedia(input *domain.GetMediaInput) (*domain.Media, error)
   GetMediaBatch(inputs []*domain.GetMediaInput) ([]*domain.Media, error)
   PutMedia(input *domain.PutMediaInput) error
-  HandleQueueMessages() error
+  HandleQueueMessages(ctx context.Context) error
+  Close() error
 }
 
 type mediaService struct {
@@ -102,8 +103,8 @@ func (m *mediaService) PutMedia(input *domain.PutMediaInput) error {
   return nil
 }
 
-func (m *mediaService) HandleQueueMessages() error {
-  return m.msQueue.ConsumeMessages(func(message *domain.PutMessage) error {
+func (m *mediaService) HandleQueueMessages(ctx context.Context) error {
+  return m.msQueue.ConsumeMessages(ctx, func(message *domain.PutMessage) error {
 
     createFileResult, err := m.createNewMediaFileOrIgnore(
       message.MetaInfo.Name,
@@ -132,6 +133,16 @@ func (m *mediaService) HandleQueueMessages() error {
   })
 }
 
+// Close release queue and storage clients, must be called after messages handling stopped
+func (m *mediaService) Close() error {
+  if err := m.msQueue.Close(); err != nil {
+    return fmt.Errorf("cannot close media service queue: %v", err)
+  }
+  m.storage.Close()
+
+  return nil
+}
+
 type createFileResult struct {
   createdOrOverwritten bool
   fileId               string
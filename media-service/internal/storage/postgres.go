@@ -21,6 +21,7 @@ type Storage interface {
   PutStoredMedia(storedMedia *StoredMedia) error
   GetStoredMedia(storedMediaId string) (*StoredMedia, bool, error)
   GetStoredMediaBatch(storedMediaIds []string) ([]*StoredMedia, error)
+  Close()
 }
 
 type storage struct {
@@ -41,6 +42,10 @@ func NewStorage(ctx context.Context, config *postgres.Config) (Storage, error) {
   }, nil
 }
 
+func (s *storage) Close() {
+  s.client.Close()
+}
+
 func (s *storage) PutStoredMedia(storedMedia *StoredMedia) error {
   builder := sq.Insert(`stored_media`).
     Columns(
@@ -0,0 +1,131 @@
+package calendar
+
+import (
+  "fmt"
+  "time"
+
+  // embed time zone database, so exchange location is available in any environment
+  _ "time/tzdata"
+)
+
+// calendar of NYSE and NASDAQ regular sessions.
+// dates are compared by year, month and day in their own location,
+// so UTC midnight dates used in storages are supported as is
+
+const exchangeTimeZone = "America/New_York"
+
+var exchangeLocation = mustLoadLocation(exchangeTimeZone)
+
+const (
+  sessionOpenHour    = 9
+  sessionOpenMinute  = 30
+  sessionCloseHour   = 16
+  earlyCloseHour     = 13
+  sessionCloseMinute = 0
+)
+
+func mustLoadLocation(name string) *time.Location {
+  location, err := time.LoadLocation(name)
+  if err != nil {
+    panic(fmt.Sprintf("cannot load location '%s': %v", name, err))
+  }
+  return location
+}
+
+// Location return time zone of the exchange
+func Location() *time.Location {
+  return exchangeLocation
+}
+
+// Today return current exchange date at UTC midnight
+func Today() time.Time {
+  now := time.Now().In(exchangeLocation)
+  return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsTradingDay report that exchange has regular session at date
+func IsTradingDay(date time.Time) bool {
+  return !isWeekend(date) && !IsHoliday(date)
+}
+
+// IsEarlyClose report that exchange session closes at 13:00 at date
+func IsEarlyClose(date time.Time) bool {
+  if !IsTradingDay(date) {
+    return false
+  }
+  _, found := earlyCloses(date.Year())[dateKeyOf(date)]
+  return found
+}
+
+// Session return open and close time of regular session at date
+func Session(date time.Time) (time.Time, time.Time, bool) {
+  if !IsTradingDay(date) {
+    return time.Time{}, time.Time{}, false
+  }
+  year, month, day := date.Date()
+
+  closeHour := sessionCloseHour
+  if IsEarlyClose(date) {
+    closeHour = earlyCloseHour
+  }
+  openAt := time.Date(year, month, day, sessionOpenHour, sessionOpenMinute, 0, 0, exchangeLocation)
+  closeAt := time.Date(year, month, day, closeHour, sessionCloseMinute, 0, 0, exchangeLocation)
+
+  return openAt, closeAt, true
+}
+
+// IsSessionOpen report that regular session is open at specified time
+func IsSessionOpen(t time.Time) bool {
+  t = t.In(exchangeLocation)
+
+  openAt, closeAt, ok := Session(t)
+  if !ok {
+    return false
+  }
+  return !t.Before(openAt) && t.Before(closeAt)
+}
+
+// NextTradingDay return the first trading day after date
+func NextTradingDay(date time.Time) time.Time {
+  next := truncateDate(date).AddDate(0, 0, 1)
+
+  for !IsTradingDay(next) {
+    next = next.AddDate(0, 0, 1)
+  }
+  return next
+}
+
+// PrevTradingDay return the last trading day before date
+func PrevTradingDay(date time.Time) time.Time {
+  prev := truncateDate(date).AddDate(0, 0, -1)
+
+  for !IsTradingDay(prev) {
+    prev = prev.AddDate(0, 0, -1)
+  }
+  return prev
+}
+
+// TradingDaysBetween return trading days in range including both borders
+func TradingDaysBetween(from, to time.Time) []time.Time {
+  var days []time.Time
+
+  last := truncateDate(to)
+
+  for day := truncateDate(from); !day.After(last); day = day.AddDate(0, 0, 1) {
+    if IsTradingDay(day) {
+      days = append(days, day)
+    }
+  }
+  return days
+}
+
+func isWeekend(date time.Time) bool {
+  weekday := date.Weekday()
+  return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// truncateDate keep date in its own location
+func truncateDate(date time.Time) time.Time {
+  year, month, day := date.Date()
+  return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
@@ -0,0 +1,214 @@
+package calendar
+
+import (
+  "testing"
+  "time"
+)
+
+const dateLayout = "2006-01-02"
+
+type monthDay struct {
+  month time.Month
+  day   int
+}
+
+// NYSE holidays and early closes published for the years
+var exchangeYears = []struct {
+  year        int
+  holidays    []monthDay
+  earlyCloses []monthDay
+}{
+  {
+    // independence and christmas days observed on monday and friday,
+    // new year of the next year falls on saturday and is not observed
+    year: 2021,
+    holidays: []monthDay{
+      {time.January, 1}, {time.January, 18}, {time.February, 15}, {time.April, 2}, {time.May, 31},
+      {time.July, 5}, {time.September, 6}, {time.November, 25}, {time.December, 24},
+    },
+    earlyCloses: []monthDay{
+      {time.November, 26},
+    },
+  },
+  {
+    // the first year of juneteenth, observed on monday
+    year: 2022,
+    holidays: []monthDay{
+      {time.January, 17}, {time.February, 21}, {time.April, 15}, {time.May, 30}, {time.June, 20},
+      {time.July, 4}, {time.September, 5}, {time.November, 24}, {time.December, 26},
+    },
+    earlyCloses: []monthDay{
+      {time.November, 25},
+    },
+  },
+  {
+    year: 2023,
+    holidays: []monthDay{
+      {time.January, 2}, {time.January, 16}, {time.February, 20}, {time.April, 7}, {time.May, 29},
+      {time.June, 19}, {time.July, 4}, {time.September, 4}, {time.November, 23}, {time.December, 25},
+    },
+    earlyCloses: []monthDay{
+      {time.July, 3}, {time.November, 24},
+    },
+  },
+  {
+    year: 2024,
+    holidays: []monthDay{
+      {time.January, 1}, {time.January, 15}, {time.February, 19}, {time.March, 29}, {time.May, 27},
+      {time.June, 19}, {time.July, 4}, {time.September, 2}, {time.November, 28}, {time.December, 25},
+    },
+    earlyCloses: []monthDay{
+      {time.July, 3}, {time.November, 29}, {time.December, 24},
+    },
+  },
+  {
+    year: 2025,
+    holidays: []monthDay{
+      {time.January, 1}, {time.January, 20}, {time.February, 17}, {time.April, 18}, {time.May, 26},
+      {time.June, 19}, {time.July, 4}, {time.September, 1}, {time.November, 27}, {time.December, 25},
+    },
+    earlyCloses: []monthDay{
+      {time.July, 3}, {time.November, 28}, {time.December, 24},
+    },
+  },
+}
+
+// yearDays return days of the year matching the check
+func yearDays(year int, check func(date time.Time) bool) map[monthDay]struct{} {
+  days := map[monthDay]struct{}{}
+
+  for date := dateOf(year, time.January, 1); date.Year() == year; date = date.AddDate(0, 0, 1) {
+    if check(date) {
+      days[monthDay{date.Month(), date.Day()}] = struct{}{}
+    }
+  }
+  return days
+}
+
+func checkYearDays(t *testing.T, name string, year int, expected []monthDay, actual map[monthDay]struct{}) {
+  t.Helper()
+
+  for _, day := range expected {
+    if _, ok := actual[day]; !ok {
+      t.Errorf("%d: expected %s on %s %d", year, name, day.month, day.day)
+    }
+    delete(actual, day)
+  }
+  for day := range actual {
+    t.Errorf("%d: unexpected %s on %s %d", year, name, day.month, day.day)
+  }
+}
+
+func TestHolidays(t *testing.T) {
+  for _, exchangeYear := range exchangeYears {
+    checkYearDays(t, "holiday", exchangeYear.year, exchangeYear.holidays, yearDays(exchangeYear.year, IsHoliday))
+  }
+}
+
+func TestEarlyCloses(t *testing.T) {
+  for _, exchangeYear := range exchangeYears {
+    checkYearDays(t, "early close", exchangeYear.year, exchangeYear.earlyCloses, yearDays(exchangeYear.year, IsEarlyClose))
+  }
+}
+
+func TestHolidayNames(t *testing.T) {
+  testCases := []struct {
+    date  time.Time
+    name  string
+    found bool
+  }{
+    {dateOf(2024, time.March, 29), "Good Friday", true},
+    {dateOf(2022, time.June, 20), "Juneteenth National Independence Day", true},
+    // juneteenth is not observed by exchange before 2022
+    {dateOf(2021, time.June, 18), "", false},
+    {dateOf(2023, time.January, 2), "New Year's Day", true},
+    // new year on saturday is not moved to the previous year
+    {dateOf(2021, time.December, 31), "", false},
+    {dateOf(2021, time.December, 24), "Christmas Day", true},
+  }
+  for _, testCase := range testCases {
+    name, found := HolidayName(testCase.date)
+
+    if name != testCase.name || found != testCase.found {
+      t.Errorf("%s: expected '%s' %v, got '%s' %v",
+        testCase.date.Format(dateLayout), testCase.name, testCase.found, name, found)
+    }
+  }
+}
+
+func TestEasterSunday(t *testing.T) {
+  testCases := []struct {
+    year     int
+    expected time.Time
+  }{
+    {2000, dateOf(2000, time.April, 23)},
+    {2008, dateOf(2008, time.March, 23)},
+    {2019, dateOf(2019, time.April, 21)},
+    {2024, dateOf(2024, time.March, 31)},
+    {2038, dateOf(2038, time.April, 25)},
+  }
+  for _, testCase := range testCases {
+    if actual := easterSunday(testCase.year); !actual.Equal(testCase.expected) {
+      t.Errorf("%d: expected easter %s, got %s",
+        testCase.year, testCase.expected.Format(dateLayout), actual.Format(dateLayout))
+    }
+  }
+}
+
+func TestSession(t *testing.T) {
+  testCases := []struct {
+    date    time.Time
+    open    string
+    close   string
+    trading bool
+  }{
+    // winter and summer sessions differ in UTC by daylight saving time
+    {date: dateOf(2024, time.January, 2), open: "2024-01-02T14:30:00Z", close: "2024-01-02T21:00:00Z", trading: true},
+    {date: dateOf(2024, time.July, 1), open: "2024-07-01T13:30:00Z", close: "2024-07-01T20:00:00Z", trading: true},
+    {date: dateOf(2024, time.July, 3), open: "2024-07-03T13:30:00Z", close: "2024-07-03T17:00:00Z", trading: true},
+    {date: dateOf(2024, time.November, 29), open: "2024-11-29T14:30:00Z", close: "2024-11-29T18:00:00Z", trading: true},
+    {date: dateOf(2024, time.July, 4)},
+    {date: dateOf(2024, time.July, 6)},
+  }
+  for _, testCase := range testCases {
+    openAt, closeAt, trading := Session(testCase.date)
+    date := testCase.date.Format(dateLayout)
+
+    if trading != testCase.trading {
+      t.Errorf("%s: expected trading %v, got %v", date, testCase.trading, trading)
+      continue
+    }
+    if !trading {
+      continue
+    }
+    if actual := openAt.UTC().Format(time.RFC3339); actual != testCase.open {
+      t.Errorf("%s: expected open at %s, got %s", date, testCase.open, actual)
+    }
+    if actual := closeAt.UTC().Format(time.RFC3339); actual != testCase.close {
+      t.Errorf("%s: expected close at %s, got %s", date, testCase.close, actual)
+    }
+  }
+}
+
+func TestTradingDaysNavigation(t *testing.T) {
+  testCases := []struct {
+    name     string
+    actual   time.Time
+    expected time.Time
+  }{
+    {"next over new year", NextTradingDay(dateOf(2022, time.December, 30)), dateOf(2023, time.January, 3)},
+    {"next over not observed new year", NextTradingDay(dateOf(2021, time.December, 30)), dateOf(2021, time.December, 31)},
+    {"prev over good friday", PrevTradingDay(dateOf(2024, time.April, 1)), dateOf(2024, time.March, 28)},
+    {"prev over observed juneteenth", PrevTradingDay(dateOf(2022, time.June, 21)), dateOf(2022, time.June, 17)},
+  }
+  for _, testCase := range testCases {
+    if !testCase.actual.Equal(testCase.expected) {
+      t.Errorf("%s: expected %s, got %s",
+        testCase.name, testCase.expected.Format(dateLayout), testCase.actual.Format(dateLayout))
+    }
+  }
+  // week of thanksgiving has 4 trading days
+  if days := TradingDaysBetween(dateOf(2023, time.November, 20), dateOf(2023, time.November, 26)); len(days) != 4 {
+    t.Errorf("expected 4 trading days of thanksgiving week, got %d", len(days))
+  }
+}
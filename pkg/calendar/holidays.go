@@ -0,0 +1,144 @@
+package calendar
+
+import (
+  "sync"
+  "time"
+)
+
+type dateKey struct {
+  year  int
+  month time.Month
+  day   int
+}
+
+func dateKeyOf(date time.Time) dateKey {
+  year, month, day := date.Date()
+  return dateKey{
+    year:  year,
+    month: month,
+    day:   day,
+  }
+}
+
+var (
+  holidaysMu    sync.Mutex
+  holidaysCache = map[int]map[dateKey]string{}
+)
+
+// IsHoliday report that exchange is closed at date because of the holiday
+func IsHoliday(date time.Time) bool {
+  _, found := holidays(date.Year())[dateKeyOf(date)]
+  return found
+}
+
+// HolidayName return holiday name at date
+func HolidayName(date time.Time) (string, bool) {
+  name, found := holidays(date.Year())[dateKeyOf(date)]
+  return name, found
+}
+
+func holidays(year int) map[dateKey]string {
+  holidaysMu.Lock()
+  defer holidaysMu.Unlock()
+
+  if cached, ok := holidaysCache[year]; ok {
+    return cached
+  }
+  yearHolidays := map[dateKey]string{}
+
+  add := func(date time.Time, name string) {
+    // observed holidays can be moved to another year, e.g. new year
+    if date.Year() == year {
+      yearHolidays[dateKeyOf(date)] = name
+    }
+  }
+  // new year day observed on monday if falls on sunday,
+  // but it is not observed on friday if falls on saturday
+  if newYear := dateOf(year, time.January, 1); newYear.Weekday() == time.Sunday {
+    add(newYear.AddDate(0, 0, 1), "New Year's Day")
+  } else
+  if newYear.Weekday() != time.Saturday {
+    add(newYear, "New Year's Day")
+  }
+  add(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King, Jr. Day")
+  add(nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday")
+  add(easterSunday(year).AddDate(0, 0, -2), "Good Friday")
+  add(lastWeekday(year, time.May, time.Monday), "Memorial Day")
+
+  const juneteenthSince = 2022
+  if year >= juneteenthSince {
+    add(observed(dateOf(year, time.June, 19)), "Juneteenth National Independence Day")
+  }
+  add(observed(dateOf(year, time.July, 4)), "Independence Day")
+  add(nthWeekday(year, time.September, time.Monday, 1), "Labor Day")
+  add(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day")
+  add(observed(dateOf(year, time.December, 25)), "Christmas Day")
+
+  holidaysCache[year] = yearHolidays
+
+  return yearHolidays
+}
+
+// earlyCloses return days with session closing at 13:00
+func earlyCloses(year int) map[dateKey]struct{} {
+  closes := map[dateKey]struct{}{}
+
+  add := func(date time.Time) {
+    if !isWeekend(date) && !IsHoliday(date) {
+      closes[dateKeyOf(date)] = struct{}{}
+    }
+  }
+  add(dateOf(year, time.July, 3))
+  add(nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1))
+  add(dateOf(year, time.December, 24))
+
+  return closes
+}
+
+func dateOf(year int, month time.Month, day int) time.Time {
+  return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// observed move holiday from saturday to friday and from sunday to monday
+func observed(date time.Time) time.Time {
+  switch date.Weekday() {
+  case time.Saturday:
+    return date.AddDate(0, 0, -1)
+  case time.Sunday:
+    return date.AddDate(0, 0, 1)
+  default:
+    return date
+  }
+}
+
+func nthWeekday(year int, month time.Month, weekday time.Weekday, nth int) time.Time {
+  first := dateOf(year, month, 1)
+  shift := (int(weekday) - int(first.Weekday()) + 7) % 7
+  return first.AddDate(0, 0, shift+(nth-1)*7)
+}
+
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+  last := dateOf(year, month+1, 1).AddDate(0, 0, -1)
+  shift := (int(last.Weekday()) - int(weekday) + 7) % 7
+  return last.AddDate(0, 0, -shift)
+}
+
+// easterSunday calculate gregorian easter date by anonymous algorithm
+func easterSunday(year int) time.Time {
+  a := year % 19
+  b := year / 100
+  c := year % 100
+  d := b / 4
+  e := b % 4
+  f := (b + 8) / 25
+  g := (b - f + 1) / 3
+  h := (19*a + b - d - g + 15) % 30
+  i := c / 4
+  k := c % 4
+  l := (32 + 2*e + 2*i - h - k) % 7
+  m := (a + 11*h + 22*l) / 451
+  month := (h + l - 7*m + 114) / 31
+  day := (h+l-7*m+114)%31 + 1
+
+  return dateOf(year, time.Month(month), day)
+}
@@ -10,6 +10,9 @@ import (
 
 type Client interface {
   GetTickers(req *TickersRequest) (*TickersResponse, error)
+  GetStocks(req *StocksRequest) (*StocksResponse, error)
+  PageTickers(req *TickersRequest, count int, handle func(tickers []*Ticker) error) error
+  PageStocks(req *StocksRequest, count int, handle func(stocks []*Stock) error) error
 }
 
 type client struct {
@@ -41,3 +44,84 @@ func (c *client) GetTickers(req *TickersRequest) (*TickersResponse, error) {
   }
   return resp, nil
 }
+
+func (c *client) GetStocks(req *StocksRequest) (*StocksResponse, error) {
+  const (
+    getStocksRoute = "/stocks"
+  )
+  content, err := c.apiClient.Post(getStocksRoute, req, nil)
+  if err != nil {
+    return nil, fmt.Errorf("cannot do post request to '%s': %v", getStocksRoute, err)
+  }
+  resp := &StocksResponse{}
+
+  if err = c.apiClient.ParseResponse(content, resp); err != nil {
+    return nil, fmt.Errorf("cannot parse response from '%s': %v", getStocksRoute, err)
+  }
+  return resp, nil
+}
+
+// PageTickers request all tickers matched the request by cursor pages with count tickers
+// and handle each page. pagination of the request is replaced by cursor
+func (c *client) PageTickers(req *TickersRequest, count int, handle func(tickers []*Ticker) error) error {
+  if req == nil {
+    req = &TickersRequest{}
+  }
+  paged := *req
+  paged.ResourceRequest = cursorResourceRequest(req.ResourceRequest, count)
+
+  for {
+    resp, err := c.GetTickers(&paged)
+    if err != nil {
+      return err
+    }
+    if len(resp.Tickers) != 0 {
+      if err = handle(resp.Tickers); err != nil {
+        return err
+      }
+    }
+    if resp.ResourceResponse == nil || resp.NextCursor == "" {
+      return nil
+    }
+    paged.Cursor.After = resp.NextCursor
+  }
+}
+
+// PageStocks request all stocks matched the request by cursor pages with count stocks
+// and handle each page. pagination of the request is replaced by cursor
+func (c *client) PageStocks(req *StocksRequest, count int, handle func(stocks []*Stock) error) error {
+  if req == nil {
+    req = &StocksRequest{}
+  }
+  paged := *req
+  paged.ResourceRequest = cursorResourceRequest(req.ResourceRequest, count)
+
+  for {
+    resp, err := c.GetStocks(&paged)
+    if err != nil {
+      return err
+    }
+    if len(resp.Stocks) != 0 {
+      if err = handle(resp.Stocks); err != nil {
+        return err
+      }
+    }
+    if resp.ResourceResponse == nil || resp.NextCursor == "" {
+      return nil
+    }
+    paged.Cursor.After = resp.NextCursor
+  }
+}
+
+// cursorResourceRequest copy request with the first page cursor instead of pagination
+func cursorResourceRequest(req *ResourceRequest, count int) *ResourceRequest {
+  paged := &ResourceRequest{}
+  if req != nil {
+    *paged = *req
+  }
+  paged.Pagination = nil
+  paged.Cursor = &Cursor{
+    Count: count,
+  }
+  return paged
+}
@@ -9,6 +9,7 @@ import (
 
 type ResourceRequest struct {
   Pagination *Pagination `json:"pagination,omitempty"`
+  Cursor     *Cursor     `json:"cursor,omitempty"`
   Sort       *Sort       `json:"sort,omitempty"`
   Filters    []*Filter   `json:"filters,omitempty"`
 }
@@ -16,6 +17,8 @@ type ResourceRequest struct {
 type ResourceResponse struct {
   Success bool `json:"success"`
   Count   int  `json:"count"`
+  // next cursor is returned in cursor mode, empty on the last page
+  NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type TickersRequest struct {
@@ -28,15 +31,29 @@ type Pagination struct {
   Count int `json:"count"`
 }
 
+// Cursor is keyset pagination, after is next cursor of the previous page, empty for the first page.
+// sort of the next pages requests must be the same as of the first page
+type Cursor struct {
+  After string `json:"after,omitempty"`
+  Count int    `json:"count"`
+}
+
 type Sort struct {
   Field string `json:"field"`
   Order string `json:"order"`
 }
 
+// Filter contain one condition or group of the filters.
+// border compare is one of eq, neq, gt, gte, lt, lte, like and ilike, like and ilike match value prefix
 type Filter struct {
   Border  *BorderFilter  `json:"border,omitempty"`
   Between *BetweenFilter `json:"between,omitempty"`
   List    *ListFilter    `json:"list,omitempty"`
+  NotIn   *ListFilter    `json:"not_in,omitempty"`
+  IsNull  *NullFilter    `json:"is_null,omitempty"`
+  And     []*Filter      `json:"and,omitempty"`
+  Or      []*Filter      `json:"or,omitempty"`
+  Not     *Filter        `json:"not,omitempty"`
 }
 
 type BorderFilter struct {
@@ -56,6 +73,10 @@ type ListFilter struct {
   Values []any  `json:"values"`
 }
 
+type NullFilter struct {
+  Field string `json:"field"`
+}
+
 type WithFields struct {
   Media bool `json:"media"`
 }
@@ -66,18 +87,24 @@ type Ticker struct {
 }
 
 type TickerFields struct {
-  TickerId           string    `json:"ticker_id"`
-  CompanyName        string    `json:"company_name"`
-  CompanyLocale      string    `json:"company_locale"`
-  CompanyDescription string    `json:"company_description"`
-  CompanyState       string    `json:"company_state"`
-  CompanyCity        string    `json:"company_city"`
-  CompanyAddress     string    `json:"company_address"`
-  HomepageUrl        string    `json:"homepage_url"`
-  CurrencyName       string    `json:"currency_name"`
-  TotalEmployees     int       `json:"total_employees"`
-  Active             bool      `json:"active"`
-  CreatedAt          time.Time `json:"created_at"`
+  TickerId           string     `json:"ticker_id"`
+  CompanyName        string     `json:"company_name"`
+  CompanyLocale      string     `json:"company_locale"`
+  CompanyDescription string     `json:"company_description"`
+  CompanyState       string     `json:"company_state"`
+  CompanyCity        string     `json:"company_city"`
+  CompanyAddress     string     `json:"company_address"`
+  HomepageUrl        string     `json:"homepage_url"`
+  CurrencyName       string     `json:"currency_name"`
+  TotalEmployees     int        `json:"total_employees"`
+  SicCode            string     `json:"sic_code"`
+  SicDescription     string     `json:"sic_description"`
+  Sector             string     `json:"sector"`
+  PrimaryExchange    string     `json:"primary_exchange"`
+  Market             string     `json:"market"`
+  ListDate           *time.Time `json:"list_date"`
+  Active             bool       `json:"active"`
+  CreatedAt          time.Time  `json:"created_at"`
 }
 
 type TickerMedia struct {
@@ -92,6 +119,9 @@ type TickersResponse struct {
 
 type StocksRequest struct {
   *ResourceRequest
+  Timespan   string `json:"timespan,omitempty"`
+  Multiplier int    `json:"multiplier,omitempty"`
+  Adjusted   bool   `json:"adjusted,omitempty"`
 }
 
 type Stock struct {
@@ -101,6 +131,9 @@ type Stock struct {
   HighestPrice  float64   `json:"highest_price"`
   LowestPrice   float64   `json:"lowest_price"`
   TradingVolume float64   `json:"trading_volume"`
+  Adjusted      bool      `json:"adjusted"`
+  Timespan      string    `json:"timespan"`
+  Multiplier    int       `json:"multiplier"`
   StockedTime   time.Time `json:"stocked_time"`
   CreatedAt     time.Time `json:"created_at"`
 }
@@ -110,6 +143,96 @@ type StocksResponse struct {
   Stocks []*Stock `json:"stocks"`
 }
 
+// StocksAggregateRequest request daily bars of the ticker resampled to the period
+// for the date range, period is one of week, month and quarter
+type StocksAggregateRequest struct {
+  Pagination *Pagination `json:"pagination,omitempty"`
+  TickerId   string      `json:"ticker_id"`
+  Period     string      `json:"period"`
+  From       time.Time   `json:"from"`
+  To         time.Time   `json:"to"`
+  Adjusted   bool        `json:"adjusted,omitempty"`
+}
+
+type AggregatedStock struct {
+  TickerId         string    `json:"ticker_id"`
+  Period           string    `json:"period"`
+  PeriodStart      time.Time `json:"period_start"`
+  OpenPrice        float64   `json:"open_price"`
+  ClosePrice       float64   `json:"close_price"`
+  HighestPrice     float64   `json:"highest_price"`
+  LowestPrice      float64   `json:"lowest_price"`
+  TradingVolume    float64   `json:"trading_volume"`
+  Adjusted         bool      `json:"adjusted"`
+  BarsCount        int       `json:"bars_count"`
+  FirstStockedTime time.Time `json:"first_stocked_time"`
+  LastStockedTime  time.Time `json:"last_stocked_time"`
+}
+
+type StocksAggregateResponse struct {
+  *ResourceResponse
+  Stocks []*AggregatedStock `json:"stocks"`
+}
+
+// IndicatorsRequest request indicator series of the ticker daily bars for the date range.
+// indicator name is one of sma, ema, rsi, macd, bollinger, atr and obv, not specified params are defaulted
+type IndicatorsRequest struct {
+  TickerId   string       `json:"ticker_id"`
+  From       time.Time    `json:"from"`
+  To         time.Time    `json:"to"`
+  Adjusted   bool         `json:"adjusted,omitempty"`
+  Indicators []*Indicator `json:"indicators"`
+}
+
+type Indicator struct {
+  Name   string  `json:"name"`
+  Period int     `json:"period,omitempty"`
+  Fast   int     `json:"fast,omitempty"`
+  Slow   int     `json:"slow,omitempty"`
+  Signal int     `json:"signal,omitempty"`
+  Width  float64 `json:"width,omitempty"`
+}
+
+// IndicatorSeries lines are aligned with the response times, null value mean not enough bars for the period.
+// lines are 'value' for the one line indicators, 'macd', 'signal', 'histogram' for macd
+// and 'middle', 'upper', 'lower' for bollinger
+type IndicatorSeries struct {
+  Indicator *Indicator            `json:"indicator"`
+  Lines     map[string][]*float64 `json:"lines"`
+}
+
+type IndicatorsResponse struct {
+  Success  bool               `json:"success"`
+  TickerId string             `json:"ticker_id"`
+  Times    []time.Time        `json:"times"`
+  Series   []*IndicatorSeries `json:"series"`
+}
+
+type FundamentalsRequest struct {
+  *ResourceRequest
+}
+
+type Financial struct {
+  TickerId          string     `json:"ticker_id"`
+  Timeframe         string     `json:"timeframe"`
+  FiscalPeriod      string     `json:"fiscal_period"`
+  FiscalYear        int        `json:"fiscal_year"`
+  StartDate         time.Time  `json:"start_date"`
+  EndDate           time.Time  `json:"end_date"`
+  FilingDate        *time.Time `json:"filing_date"`
+  Revenue           *float64   `json:"revenue"`
+  NetIncome         *float64   `json:"net_income"`
+  BasicEps          *float64   `json:"basic_eps"`
+  DilutedEps        *float64   `json:"diluted_eps"`
+  SharesOutstanding *float64   `json:"shares_outstanding"`
+  UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type FundamentalsResponse struct {
+  *ResourceResponse
+  Financials []*Financial `json:"financials"`
+}
+
 type SubscribeRequest struct {
   TickerId string `json:"ticker_id"`
 }
@@ -130,6 +253,21 @@ func (r *ResourceRequest) Validate() error {
   const (
     maxCountPerPage = 1000
   )
+  if r.Pagination != nil && r.Cursor != nil {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "pagination and cursor cannot be specified together", nil)
+  }
+  if r.Cursor != nil {
+    if r.Cursor.Count <= 0 {
+      return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+        "cursor count must be positive", nil)
+    }
+    if r.Cursor.Count > maxCountPerPage {
+      return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+        fmt.Sprintf("cursor count must be lower than %d", maxCountPerPage), nil)
+    }
+    return nil
+  }
   if r.Pagination == nil {
     return nil
   }
@@ -148,6 +286,96 @@ func (r *ResourceRequest) Validate() error {
   return nil
 }
 
+func (r *StocksAggregateRequest) Validate() error {
+  if r.TickerId == "" {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "ticker id must be specified", nil)
+  }
+  switch r.Period {
+  case "week", "month", "quarter":
+  default:
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("unknown period '%s'. possible: week, month, quarter", r.Period), nil)
+  }
+  if r.From.IsZero() || r.To.IsZero() {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "from and to dates must be specified", nil)
+  }
+  if r.From.After(r.To) {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "from date must not be after to date", nil)
+  }
+  return nil
+}
+
+func (r *IndicatorsRequest) Validate() error {
+  const (
+    maxIndicators = 16
+  )
+  if r.TickerId == "" {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "ticker id must be specified", nil)
+  }
+  if r.From.IsZero() || r.To.IsZero() {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "from and to dates must be specified", nil)
+  }
+  if r.From.After(r.To) {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "from date must not be after to date", nil)
+  }
+  if len(r.Indicators) == 0 || len(r.Indicators) > maxIndicators {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("from 1 to %d indicators must be specified", maxIndicators), nil)
+  }
+  for idx, indicator := range r.Indicators {
+    if indicator == nil {
+      return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+        fmt.Sprintf("indicators[%d] must be specified", idx), nil)
+    }
+    switch indicator.Name {
+    case "sma", "ema", "rsi", "macd", "bollinger", "atr", "obv":
+    default:
+      return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+        fmt.Sprintf("indicators[%d] has unknown name '%s'", idx, indicator.Name), nil)
+    }
+    if indicator.Period < 0 || indicator.Fast < 0 || indicator.Slow < 0 || indicator.Signal < 0 || indicator.Width < 0 {
+      return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+        fmt.Sprintf("indicators[%d] params must not be negative", idx), nil)
+    }
+  }
+  return nil
+}
+
+func (r *StocksRequest) Validate() error {
+  return validateGranularity(r.Timespan, r.Multiplier)
+}
+
+func validateGranularity(timespan string, multiplier int) error {
+  if multiplier < 0 {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "multiplier must be positive", nil)
+  }
+  switch timespan {
+  case "", "minute", "hour", "day", "week", "month", "quarter", "year":
+  default:
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("unknown timespan '%s'", timespan), nil)
+  }
+  return nil
+}
+
+func (r *StocksPagesRequest) Validate() error {
+  if r.PagesRequest == nil {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "page_size must be specified", nil)
+  }
+  if err := r.PagesRequest.Validate(); err != nil {
+    return err
+  }
+  return validateGranularity(r.Timespan, r.Multiplier)
+}
+
 func (r *SubscribeRequest) Validate() error {
   if r.TickerId == "" {
     return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
@@ -204,6 +432,13 @@ type PagesRequest struct {
   PageSize int `json:"page_size"`
 }
 
+// StocksPagesRequest calculate pages of the stocks with granularity, default is daily bars
+type StocksPagesRequest struct {
+  *PagesRequest
+  Timespan   string `json:"timespan,omitempty"`
+  Multiplier int    `json:"multiplier,omitempty"`
+}
+
 type PagesResponse struct {
   Success    bool `json:"success"`
   TotalCount int  `json:"total_count"`
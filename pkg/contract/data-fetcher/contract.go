@@ -0,0 +1,178 @@
+package datafetcher
+
+import (
+  "fmt"
+  "strings"
+  "time"
+
+  "github.com/UshakovN/stock-predictor-service/errs"
+)
+
+const (
+  GapStatusFilled          = "filled"
+  GapStatusPartiallyFilled = "partially_filled"
+  GapStatusUnfillable      = "unfillable"
+)
+
+type GapsRequest struct {
+  TickerId string `json:"ticker_id,omitempty"`
+  Status   string `json:"status,omitempty"`
+}
+
+type GapsResponse struct {
+  Success bool   `json:"success"`
+  Count   int    `json:"count"`
+  Gaps    []*Gap `json:"gaps"`
+}
+
+type Gap struct {
+  GapId       string    `json:"gap_id"`
+  TickerId    string    `json:"ticker_id"`
+  Timespan    string    `json:"timespan"`
+  Multiplier  int       `json:"multiplier"`
+  DateFrom    time.Time `json:"date_from"`
+  DateTo      time.Time `json:"date_to"`
+  MissingDays int       `json:"missing_days"`
+  FilledDays  int       `json:"filled_days"`
+  Status      string    `json:"status"`
+  Attempts    int       `json:"attempts"`
+  CreatedAt   time.Time `json:"created_at"`
+  UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (r *GapsRequest) Validate() error {
+  switch r.Status {
+  case "", GapStatusFilled, GapStatusPartiallyFilled, GapStatusUnfillable:
+    return nil
+  default:
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("unknown gap status '%s'. possible: %s, %s, %s", r.Status,
+        GapStatusFilled, GapStatusPartiallyFilled, GapStatusUnfillable), nil)
+  }
+}
+
+type QuarantineRequest struct {
+  TickerId string `json:"ticker_id,omitempty"`
+}
+
+type QuarantineResponse struct {
+  Success bool              `json:"success"`
+  Total   int               `json:"total"`
+  Rules   []*QuarantineRule `json:"rules"`
+}
+
+type QuarantineRule struct {
+  Rule  string `json:"rule"`
+  Count int    `json:"count"`
+}
+
+const ApiTokenHeader = "X-Service-Token"
+
+const (
+  ModeTotal   = "total"
+  ModeCurrent = "current"
+)
+
+const (
+  maxRunTickersCount = 100
+  maxErrorsLimit     = 100
+)
+
+type ControlResponse struct {
+  Success bool `json:"success"`
+}
+
+type RunRequest struct {
+  TickerIds []string `json:"ticker_ids"`
+}
+
+type ModeRequest struct {
+  Mode string `json:"mode"`
+}
+
+type ProgressResponse struct {
+  Success      bool       `json:"success"`
+  Mode         string     `json:"mode"`
+  Paused       bool       `json:"paused"`
+  Running      bool       `json:"running"`
+  Finished     bool       `json:"finished"`
+  UpdatedAt    *time.Time `json:"updated_at"`
+  TickerCursor string     `json:"ticker_cursor"`
+  Counters     *Counters  `json:"counters"`
+}
+
+type Counters struct {
+  Tickers       uint64 `json:"tickers"`
+  TickerDetails uint64 `json:"ticker_details"`
+  Stocks        uint64 `json:"stocks"`
+}
+
+type ScheduleResponse struct {
+  Success bool               `json:"success"`
+  Count   int                `json:"count"`
+  Due     int                `json:"due"`
+  Plan    []*ScheduledTicker `json:"plan"`
+}
+
+type ScheduledTicker struct {
+  TickerId      string     `json:"ticker_id"`
+  Tier          string     `json:"tier"`
+  Priority      float64    `json:"priority"`
+  Subscribers   int        `json:"subscribers"`
+  LastBarAt     *time.Time `json:"last_bar_at"`
+  RefreshedAt   *time.Time `json:"refreshed_at"`
+  NextRefreshAt time.Time  `json:"next_refresh_at"`
+  Due           bool       `json:"due"`
+}
+
+type ErrorsRequest struct {
+  Limit int `json:"limit,omitempty"`
+}
+
+type ErrorsResponse struct {
+  Success bool          `json:"success"`
+  Count   int           `json:"count"`
+  Errors  []*FetchError `json:"errors"`
+}
+
+type FetchError struct {
+  TickerId   string    `json:"ticker_id"`
+  Message    string    `json:"message"`
+  OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (r *RunRequest) Validate() error {
+  if len(r.TickerIds) == 0 {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      "ticker ids must be specified", nil)
+  }
+  if len(r.TickerIds) > maxRunTickersCount {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("ticker ids count must not exceed %d", maxRunTickersCount), nil)
+  }
+  for _, tickerId := range r.TickerIds {
+    if strings.TrimSpace(tickerId) == "" {
+      return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+        "ticker id must not be empty", nil)
+    }
+  }
+  return nil
+}
+
+func (r *ModeRequest) Validate() error {
+  switch r.Mode {
+  case ModeTotal, ModeCurrent:
+    return nil
+  default:
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("unknown mode '%s'. possible: %s, %s", r.Mode, ModeTotal, ModeCurrent), nil)
+  }
+}
+
+func (r *ErrorsRequest) Validate() error {
+  if r.Limit < 0 || r.Limit > maxErrorsLimit {
+    return errs.NewErrorWithMessage(errs.ErrTypeMalformedRequest,
+      fmt.Sprintf("limit must be in range from 0 to %d", maxErrorsLimit), nil)
+  }
+  return nil
+}
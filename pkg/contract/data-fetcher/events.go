@@ -0,0 +1,44 @@
+package datafetcher
+
+import (
+  "encoding/json"
+  "fmt"
+  "time"
+)
+
+const (
+  // EventsExchange is topic exchange of the data fetcher events
+  EventsExchange = "data-fetcher.events"
+  // EventStocksUpdated is routing key of the event published when new bars of the ticker are stored
+  EventStocksUpdated = "stocks.updated"
+)
+
+type StocksUpdatedMessage struct {
+  TickerId    string          `json:"ticker_id"`
+  From        time.Time       `json:"from"`
+  To          time.Time       `json:"to"`
+  Count       int             `json:"count"`
+  Series      []*StocksSeries `json:"series"`
+  PublishedAt time.Time       `json:"published_at"`
+}
+
+// StocksSeries describe stored bars of the ticker with specified granularity
+type StocksSeries struct {
+  Timespan   string    `json:"timespan"`
+  Multiplier int       `json:"multiplier"`
+  From       time.Time `json:"from"`
+  To         time.Time `json:"to"`
+  Count      int       `json:"count"`
+}
+
+func ParseStocksUpdatedMessage(body []byte) (*StocksUpdatedMessage, error) {
+  message := &StocksUpdatedMessage{}
+
+  if err := json.Unmarshal(body, message); err != nil {
+    return nil, fmt.Errorf("cannot unmarshal stocks updated message: %v", err)
+  }
+  if message.TickerId == "" {
+    return nil, fmt.Errorf("ticker id not found in stocks updated message")
+  }
+  return message, nil
+}
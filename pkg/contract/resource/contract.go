@@ -21,10 +21,17 @@ type Sort struct {
   Order string `json:"order"`
 }
 
+// Filter contain one condition or group of the filters.
+// border compare is one of eq, neq, gt, gte, lt, lte, like and ilike, like and ilike match value prefix
 type Filter struct {
   Border  *BorderFilter  `json:"border,omitempty"`
   Between *BetweenFilter `json:"between,omitempty"`
   List    *ListFilter    `json:"list,omitempty"`
+  NotIn   *ListFilter    `json:"not_in,omitempty"`
+  IsNull  *NullFilter    `json:"is_null,omitempty"`
+  And     []*Filter      `json:"and,omitempty"`
+  Or      []*Filter      `json:"or,omitempty"`
+  Not     *Filter        `json:"not,omitempty"`
 }
 
 type BorderFilter struct {
@@ -43,3 +50,7 @@ type ListFilter struct {
   Field  string `json:"field"`
   Values []any  `json:"values"`
 }
+
+type NullFilter struct {
+  Field string `json:"field"`
+}
@@ -3,6 +3,7 @@ package resource
 import (
   "fmt"
   "net/url"
+  "sort"
   "strconv"
   "strings"
 )
@@ -15,20 +16,26 @@ const (
   filterSuffixBorder  = "border"
   filterSuffixBetween = "between"
   filterSuffixList    = "list"
+  filterSuffixNotIn   = "not_in"
+  filterSuffixIsNull  = "is_null"
+
+  filterGroupOr  = "or"
+  filterGroupAnd = "and"
+  filterGroupNot = "not"
 
   keySuffix = "%s[%s]"
 )
 
-var (
-  keyFilterBorder  = fmt.Sprintf(keySuffix, keyFilters, filterSuffixBorder)
-  keyFilterBetween = fmt.Sprintf(keySuffix, keyFilters, filterSuffixBetween)
-  keyFilterList    = fmt.Sprintf(keySuffix, keyFilters, filterSuffixList)
-)
+// filterSuffixes order plain filters of the request
+var filterSuffixes = []string{
+  filterSuffixBorder,
+  filterSuffixBetween,
+  filterSuffixList,
+  filterSuffixNotIn,
+  filterSuffixIsNull,
+}
 
 var keysQuery = []string{
-  keyFilterBorder,
-  keyFilterBetween,
-  keyFilterList,
   keyPagination,
   keySort,
 }
@@ -94,6 +101,11 @@ func (q *Query) SanitizeQuery() url.Values {
   for _, keyQuery := range keysQuery {
     parsed.Del(keyQuery)
   }
+  for key := range parsed {
+    if isFilterKey(key) {
+      parsed.Del(key)
+    }
+  }
   return parsed
 }
 
@@ -200,28 +212,215 @@ func (q *Query) parseSort() (*Sort, error) {
   return parsedSort, nil
 }
 
+// parseFilters parse filters of the bracket paths joined by 'and'. path end with filter type
+// and may start with any nested groups:
+//   filter[<type>]=[...] is plain filter
+//   filter[not][<path>]=[...] negate each filter of the path
+//   filter[or][<group>][<path>]=[...] join filters with the same group name by 'or'
+//   filter[and][<group>][<path>]=[...] join filters with the same group name by 'and'
+// e.g. filter[or][g][and][h][border] is 'and' group h inside of 'or' group g
 func (q *Query) parseFilters() ([]*Filter, error) {
-  var (
-    parsed  []*Filter
-    filters []*Filter
-    err     error
-  )
-  for _, parse := range []func() ([]*Filter, error){
-    q.parseBorderFilters,
-    q.parseBetweenFilters,
-    q.parseListFilters,
-  } {
-    if filters, err = parse(); err != nil {
+  root := &filterNode{}
+
+  // plain filters are parsed first to keep their order
+  for _, suffix := range filterSuffixes {
+    filters, err := q.parseKeyFilters(fmt.Sprintf(keySuffix, keyFilters, suffix), suffix)
+    if err != nil {
+      return nil, err
+    }
+    root.filters = append(root.filters, filters...)
+  }
+  var groupKeys []string
+
+  for key := range q.parsed {
+    if isFilterKey(key) && !isPlainFilterKey(key) {
+      groupKeys = append(groupKeys, key)
+    }
+  }
+  // groups are parsed in the same order for the same query
+  sort.Strings(groupKeys)
+
+  for _, key := range groupKeys {
+    if err := q.parseFilterPath(root, key, filterKeySegments(key)); err != nil {
       return nil, err
     }
-    if len(filters) != 0 {
-      parsed = append(parsed, filters...)
+  }
+  return root.build(), nil
+}
+
+// parseFilterPath add filters of the key to the node by the rest segments of the key path
+func (q *Query) parseFilterPath(node *filterNode, key string, segments []string) error {
+  const (
+    groupNameIdx = 1
+    groupPathIdx = 2
+  )
+  switch {
+  case len(segments) == 0:
+    return fmt.Errorf("%s: filter type not specified. expected: %s", key, filterPathForm)
+  case len(segments) == 1 && isFilterSuffix(segments[0]):
+    filters, err := q.parseKeyFilters(key, segments[0])
+    if err != nil {
+      return err
+    }
+    node.filters = append(node.filters, filters...)
+    return nil
+  case segments[0] == filterGroupNot:
+    if node.not == nil {
+      node.not = &filterNode{}
+    }
+    return q.parseFilterPath(node.not, key, segments[1:])
+  case segments[0] == filterGroupOr || segments[0] == filterGroupAnd:
+    if len(segments) <= groupPathIdx || segments[groupNameIdx] == "" {
+      return fmt.Errorf("%s: %s group must have form [%s][<group>][<path>]", key, segments[0], segments[0])
+    }
+    return q.parseFilterPath(node.group(segments[0], segments[groupNameIdx]), key, segments[groupPathIdx:])
+  default:
+    return fmt.Errorf("%s: unknown filter segment '%s'. expected: %s", key, segments[0], filterPathForm)
+  }
+}
+
+const filterPathForm = "[<type>], [not][<path>], [or][<group>][<path>] or [and][<group>][<path>]"
+
+// filterNode collect filters of the same bracket path prefix
+type filterNode struct {
+  filters []*Filter
+  not     *filterNode
+  groups  []*filterGroup
+}
+
+type filterGroup struct {
+  join string
+  name string
+  node *filterNode
+}
+
+// group return child group of the node with the join and name, group is created on the first use
+func (n *filterNode) group(join, name string) *filterNode {
+  for _, group := range n.groups {
+    if group.join == join && group.name == name {
+      return group.node
+    }
+  }
+  group := &filterGroup{
+    join: join,
+    name: name,
+    node: &filterNode{},
+  }
+  n.groups = append(n.groups, group)
+
+  return group.node
+}
+
+// build return filters of the node in the same form as request filters
+func (n *filterNode) build() []*Filter {
+  filters := append([]*Filter(nil), n.filters...)
+
+  if n.not != nil {
+    for _, filter := range n.not.build() {
+      filters = append(filters, &Filter{
+        Not: filter,
+      })
+    }
+  }
+  for _, group := range n.groups {
+    children := group.node.build()
+
+    if group.join == filterGroupOr {
+      filters = append(filters, &Filter{Or: children})
+    } else {
+      filters = append(filters, &Filter{And: children})
+    }
+  }
+  return filters
+}
+
+// parseKeyFilters parse values of the query key with filters of the type.
+// errors point to the key and the malformed value
+func (q *Query) parseKeyFilters(key, suffix string) ([]*Filter, error) {
+  parse := map[string]func(string) (*Filter, error){
+    filterSuffixBorder:  parseBorderFilter,
+    filterSuffixBetween: parseBetweenFilter,
+    filterSuffixList:    parseListFilter,
+    filterSuffixNotIn:   parseNotInFilter,
+    filterSuffixIsNull:  parseIsNullFilter,
+  }[suffix]
+
+  values := q.parsed[key]
+  filters := make([]*Filter, 0, len(values))
+
+  for _, value := range values {
+    filter, err := parse(value)
+    if err != nil {
+      return nil, fmt.Errorf("%s=%s: %v", key, value, err)
+    }
+    filters = append(filters, filter)
+  }
+  return filters, nil
+}
+
+func isFilterKey(key string) bool {
+  return strings.HasPrefix(key, keyFilters+"[")
+}
+
+func isPlainFilterKey(key string) bool {
+  segments := filterKeySegments(key)
+  return len(segments) == 1 && isFilterSuffix(segments[0])
+}
+
+func isFilterSuffix(suffix string) bool {
+  for _, filterSuffix := range filterSuffixes {
+    if suffix == filterSuffix {
+      return true
     }
   }
-  return parsed, nil
+  return false
+}
+
+// filterKeySegments return segments of the filter key in brackets, e.g. [or g1 border] for filter[or][g1][border].
+// key without brackets has no segments
+func filterKeySegments(key string) []string {
+  const (
+    prefix = "["
+    suffix = "]"
+    sep    = "]["
+  )
+  key = strings.TrimPrefix(key, keyFilters)
+
+  if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, suffix) {
+    return nil
+  }
+  key = strings.TrimSuffix(strings.TrimPrefix(key, prefix), suffix)
+
+  return strings.Split(key, sep)
+}
+
+func parseListFilter(list string) (*Filter, error) {
+  field, values, err := parseFieldValues(list, filterSuffixList)
+  if err != nil {
+    return nil, err
+  }
+  return &Filter{
+    List: &ListFilter{
+      Field:  field,
+      Values: values,
+    },
+  }, nil
+}
+
+func parseNotInFilter(list string) (*Filter, error) {
+  field, values, err := parseFieldValues(list, filterSuffixNotIn)
+  if err != nil {
+    return nil, err
+  }
+  return &Filter{
+    NotIn: &ListFilter{
+      Field:  field,
+      Values: values,
+    },
+  }, nil
 }
 
-func (q *Query) parseListFilters() ([]*Filter, error) {
+func parseFieldValues(list, filterType string) (string, []any, error) {
   const (
     partValSep = "|"
     partsCount = 2
@@ -230,54 +429,72 @@ func (q *Query) parseListFilters() ([]*Filter, error) {
     keyField  = "field"
     keyValues = "values"
   )
-  list := q.parsed[keyFilterList]
-  listFilters := make([]*Filter, 0, len(list))
-
-  for _, list := range list {
-    parts, err := splitPartForm(list, partsCount)
-    if err != nil {
-      return nil, fmt.Errorf("list filter must have form [field:<f>,values:<v|...>]. error: %v", err)
-    }
-    var (
-      partField  string
-      partValues []string
-    )
-    for _, part := range parts {
-      if partField == "" {
-        if partField, err = parseFormPartVal(part, keyField); err != nil {
-          return nil, err
-        }
+  parts, err := splitPartForm(list, partsCount)
+  if err != nil {
+    return "", nil, fmt.Errorf("%s filter must have form [field:<f>,values:<v|...>]. error: %v", filterType, err)
+  }
+  var (
+    partField  string
+    partValues []string
+  )
+  for _, part := range parts {
+    if partField == "" {
+      if partField, err = parseFormPartVal(part, keyField); err != nil {
+        return "", nil, err
       }
-      if len(partValues) == 0 {
-        var merged string
+    }
+    if len(partValues) == 0 {
+      var merged string
 
-        if merged, err = parseFormPartVal(part, keyValues); err != nil {
-          return nil, err
-        }
-        if merged != "" {
-          partValues = strings.Split(merged, partValSep)
-        }
+      if merged, err = parseFormPartVal(part, keyValues); err != nil {
+        return "", nil, err
+      }
+      if merged != "" {
+        partValues = strings.Split(merged, partValSep)
       }
     }
-    if partField == "" || len(partValues) == 0 {
-      return nil, fmt.Errorf("list filter must have form [field:<f>,values:<v|...>]. error: one of parts not specified or malformed")
-    }
-    castedValues := make([]any, 0, len(partValues))
+  }
+  if partField == "" || len(partValues) == 0 {
+    return "", nil, fmt.Errorf("%s filter must have form [field:<f>,values:<v|...>]. error: one of parts not specified or malformed", filterType)
+  }
+  castedValues := make([]any, 0, len(partValues))
+
+  for _, partValue := range partValues {
+    castedValues = append(castedValues, castFieldValue(partValue))
+  }
+  return partField, castedValues, nil
+}
+
+func parseIsNullFilter(isNull string) (*Filter, error) {
+  const partsCount = 1
+
+  const (
+    keyField = "field"
+  )
+  parts, err := splitPartForm(isNull, partsCount)
+  if err != nil {
+    return nil, fmt.Errorf("is_null filter must have form [field:<f>]. error: %v", err)
+  }
+  var partField string
 
-    for _, partValue := range partValues {
-      castedValues = append(castedValues, castFieldValue(partValue))
+  for _, part := range parts {
+    if partField == "" {
+      if partField, err = parseFormPartVal(part, keyField); err != nil {
+        return nil, err
+      }
     }
-    listFilters = append(listFilters, &Filter{
-      List: &ListFilter{
-        Field:  partField,
-        Values: castedValues,
-      },
-    })
   }
-  return listFilters, nil
+  if partField == "" {
+    return nil, fmt.Errorf("is_null filter must have form [field:<f>]. error: field not specified or malformed")
+  }
+  return &Filter{
+    IsNull: &NullFilter{
+      Field: partField,
+    },
+  }, nil
 }
 
-func (q *Query) parseBetweenFilters() ([]*Filter, error) {
+func parseBetweenFilter(between string) (*Filter, error) {
   const partsCount = 2
 
   const (
@@ -285,51 +502,45 @@ func (q *Query) parseBetweenFilters() ([]*Filter, error) {
     keyLeft  = "left"
     keyRight = "right"
   )
-  between := q.parsed[keyFilterBetween]
-  betweenFilters := make([]*Filter, 0, len(between))
-
-  for _, between := range between {
-    parts, err := splitPartForm(between, partsCount)
-    if err != nil {
-      return nil, fmt.Errorf("between filter must have form [field:<f>,left:<l>,right:<r>]. error: %v", err)
-    }
-    var (
-      partField string
-      partLeft  string
-      partRight string
-    )
-    for _, part := range parts {
-      if partField == "" {
-        if partField, err = parseFormPartVal(part, keyField); err != nil {
-          return nil, err
-        }
-      }
-      if partLeft == "" {
-        if partLeft, err = parseFormPartVal(part, keyLeft); err != nil {
-          return nil, err
-        }
+  parts, err := splitPartForm(between, partsCount)
+  if err != nil {
+    return nil, fmt.Errorf("between filter must have form [field:<f>,left:<l>,right:<r>]. error: %v", err)
+  }
+  var (
+    partField string
+    partLeft  string
+    partRight string
+  )
+  for _, part := range parts {
+    if partField == "" {
+      if partField, err = parseFormPartVal(part, keyField); err != nil {
+        return nil, err
       }
-      if partRight == "" {
-        if partRight, err = parseFormPartVal(part, keyRight); err != nil {
-          return nil, err
-        }
+    }
+    if partLeft == "" {
+      if partLeft, err = parseFormPartVal(part, keyLeft); err != nil {
+        return nil, err
       }
     }
-    if partField == "" || partLeft == "" || partRight == "" {
-      return nil, fmt.Errorf("between filter must have form [field:<f>,left:<l>,right:<r>]. error: one of parts not specified or malformed")
+    if partRight == "" {
+      if partRight, err = parseFormPartVal(part, keyRight); err != nil {
+        return nil, err
+      }
     }
-    betweenFilters = append(betweenFilters, &Filter{
-      Between: &BetweenFilter{
-        Field:       partField,
-        LeftBorder:  castFieldValue(partLeft),
-        RightBorder: castFieldValue(partRight),
-      },
-    })
   }
-  return betweenFilters, nil
+  if partField == "" || partLeft == "" || partRight == "" {
+    return nil, fmt.Errorf("between filter must have form [field:<f>,left:<l>,right:<r>]. error: one of parts not specified or malformed")
+  }
+  return &Filter{
+    Between: &BetweenFilter{
+      Field:       partField,
+      LeftBorder:  castFieldValue(partLeft),
+      RightBorder: castFieldValue(partRight),
+    },
+  }, nil
 }
 
-func (q *Query) parseBorderFilters() ([]*Filter, error) {
+func parseBorderFilter(border string) (*Filter, error) {
   const partsCount = 3
 
   const (
@@ -337,48 +548,48 @@ func (q *Query) parseBorderFilters() ([]*Filter, error) {
     keyValue   = "value"
     keyCompare = "compare"
   )
-  borders := q.parsed[keyFilterBorder]
-  borderFilters := make([]*Filter, 0, len(borders))
-
-  for _, border := range borders {
-    parts, err := splitPartForm(border, partsCount)
-    if err != nil {
-      return nil, fmt.Errorf("border filter must have form [field:<f>,value:<v>,compare:<eq|gt|gte|lt|lte>]. error: %v", err)
-    }
-    var (
-      partField   string
-      partValue   string
-      partCompare string
-    )
-    for _, part := range parts {
-      if partField == "" {
-        if partField, err = parseFormPartVal(part, keyField); err != nil {
-          return nil, err
-        }
-      }
-      if partValue == "" {
-        if partValue, err = parseFormPartVal(part, keyValue); err != nil {
-          return nil, err
-        }
+  parts, err := splitPartForm(border, partsCount)
+  if err != nil {
+    return nil, fmt.Errorf("border filter must have form [field:<f>,value:<v>,compare:<%s>]. error: %v", borderCompares, err)
+  }
+  var (
+    partField   string
+    partValue   string
+    partCompare string
+  )
+  for _, part := range parts {
+    if partField == "" {
+      if partField, err = parseFormPartVal(part, keyField); err != nil {
+        return nil, err
       }
-      if partCompare == "" {
-        if partCompare, err = parseFormPartVal(part, keyCompare); err != nil {
-          return nil, err
-        }
+    }
+    if partValue == "" {
+      if partValue, err = parseFormPartVal(part, keyValue); err != nil {
+        return nil, err
       }
     }
-    if partField == "" || partValue == "" || hasMalformedFilterCompare(partCompare) {
-      return nil, fmt.Errorf("border filter must have form [field:<f>,value:<v>,compare:<eq|gt|gte|lt|lte>]. error: one of parts not specified or malformed")
+    if partCompare == "" {
+      if partCompare, err = parseFormPartVal(part, keyCompare); err != nil {
+        return nil, err
+      }
     }
-    borderFilters = append(borderFilters, &Filter{
-      Border: &BorderFilter{
-        Field:   partField,
-        Value:   castFieldValue(partValue),
-        Compare: partCompare,
-      },
-    })
   }
-  return borderFilters, nil
+  if partField == "" || partValue == "" || hasMalformedFilterCompare(partCompare) {
+    return nil, fmt.Errorf("border filter must have form [field:<f>,value:<v>,compare:<%s>]. error: one of parts not specified or malformed", borderCompares)
+  }
+  value := castFieldValue(partValue)
+
+  // like and ilike match string prefix, so value is not casted
+  if partCompare == "like" || partCompare == "ilike" {
+    value = partValue
+  }
+  return &Filter{
+    Border: &BorderFilter{
+      Field:   partField,
+      Value:   value,
+      Compare: partCompare,
+    },
+  }, nil
 }
 
 func splitPartForm(s string, partsCount int) ([]string, error) {
@@ -458,13 +669,18 @@ func parseFormPartVal(part, key string) (string, error) {
   return formPartVal, nil
 }
 
+const borderCompares = "eq|neq|gt|gte|lt|lte|like|ilike"
+
 func hasMalformedFilterCompare(s string) bool {
   _, ok := map[string]struct{}{
-    "eq":  {},
-    "gt":  {},
-    "gte": {},
-    "lt":  {},
-    "lte": {},
+    "eq":    {},
+    "neq":   {},
+    "gt":    {},
+    "gte":   {},
+    "lt":    {},
+    "lte":   {},
+    "like":  {},
+    "ilike": {},
   }[s]
   return !ok
 }
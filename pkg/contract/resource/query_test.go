@@ -0,0 +1,201 @@
+package resource
+
+import (
+  "encoding/json"
+  "net/url"
+  "strings"
+  "testing"
+)
+
+func parseTestFilters(t *testing.T, query url.Values) ([]*Filter, error) {
+  t.Helper()
+
+  q, err := NewQuery(WithParsedQuery(query))
+  if err != nil {
+    t.Fatalf("cannot create query: %v", err)
+  }
+  req, err := q.ParseRequest()
+  if err != nil {
+    return nil, err
+  }
+  return req.Filters, nil
+}
+
+func TestParseFilters(t *testing.T) {
+  testCases := []struct {
+    name    string
+    query   url.Values
+    filters string
+  }{
+    {
+      name: "plain filters keep order of the types",
+      query: url.Values{
+        "filter[list]":   {"[field:ticker_id,values:AAPL|MSFT]"},
+        "filter[border]": {"[field:close_price,value:10.5,compare:gt]", "[field:trading_volume,value:100,compare:gte]"},
+      },
+      filters: `[` +
+        `{"border":{"field":"close_price","value":10.5,"compare":"gt"}},` +
+        `{"border":{"field":"trading_volume","value":100,"compare":"gte"}},` +
+        `{"list":{"field":"ticker_id","values":["AAPL","MSFT"]}}]`,
+    },
+    {
+      name: "not wrap each filter of the path",
+      query: url.Values{
+        "filter[not][list]": {"[field:sector,values:Energy]", "[field:market,values:otc]"},
+      },
+      filters: `[` +
+        `{"not":{"list":{"field":"sector","values":["Energy"]}}},` +
+        `{"not":{"list":{"field":"market","values":["otc"]}}}]`,
+    },
+    {
+      name: "groups with the same name are merged",
+      query: url.Values{
+        "filter[or][g][border]":  {"[field:sector,value:Energy,compare:eq]"},
+        "filter[or][g][list]":    {"[field:ticker_id,values:AAPL]"},
+        "filter[or][h][is_null]": {"[field:sector]"},
+      },
+      filters: `[` +
+        `{"or":[{"border":{"field":"sector","value":"Energy","compare":"eq"}},{"list":{"field":"ticker_id","values":["AAPL"]}}]},` +
+        `{"or":[{"is_null":{"field":"sector"}}]}]`,
+    },
+    {
+      name: "nested groups",
+      query: url.Values{
+        "filter[or][g][and][h][border]": {"[field:close_price,value:10,compare:gt]", "[field:close_price,value:20,compare:lt]"},
+        "filter[or][g][border]":         {"[field:ticker_id,value:AAPL,compare:eq]"},
+      },
+      filters: `[{"or":[` +
+        `{"border":{"field":"ticker_id","value":"AAPL","compare":"eq"}},` +
+        `{"and":[{"border":{"field":"close_price","value":10,"compare":"gt"}},{"border":{"field":"close_price","value":20,"compare":"lt"}}]}]}]`,
+    },
+    {
+      name: "not inside of group",
+      query: url.Values{
+        "filter[and][g][not][is_null]": {"[field:sector]"},
+        "filter[between]":              {"[field:close_price,left:1,right:2.5]"},
+      },
+      filters: `[` +
+        `{"between":{"field":"close_price","left_border":1,"right_border":2.5}},` +
+        `{"and":[{"not":{"is_null":{"field":"sector"}}}]}]`,
+    },
+    {
+      name: "like values are kept as strings",
+      query: url.Values{
+        "filter[border]":        {"[field:ticker_id,value:10,compare:like]", "[field:sic_code,value:2834,compare:eq]"},
+        "filter[or][g][border]": {"[field:company_name,value:true,compare:ilike]"},
+      },
+      filters: `[` +
+        `{"border":{"field":"ticker_id","value":"10","compare":"like"}},` +
+        `{"border":{"field":"sic_code","value":2834,"compare":"eq"}},` +
+        `{"or":[{"border":{"field":"company_name","value":"true","compare":"ilike"}}]}]`,
+    },
+    {
+      name: "not filter keys are ignored",
+      query: url.Values{
+        "filters": {"[field:ticker_id]"},
+        "page":    {"1"},
+      },
+      filters: `null`,
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      filters, err := parseTestFilters(t, testCase.query)
+      if err != nil {
+        t.Fatalf("cannot parse filters: %v", err)
+      }
+      content, err := json.Marshal(filters)
+      if err != nil {
+        t.Fatalf("cannot marshal filters: %v", err)
+      }
+      if string(content) != testCase.filters {
+        t.Errorf("expected filters:\n%s\ngot:\n%s", testCase.filters, content)
+      }
+    })
+  }
+}
+
+func TestParseFiltersErrors(t *testing.T) {
+  testCases := []struct {
+    name  string
+    query url.Values
+    // error must point to the malformed key path
+    errContains []string
+  }{
+    {
+      name:        "unknown segment",
+      query:       url.Values{"filter[xor][g][border]": {"[field:sector,value:Energy,compare:eq]"}},
+      errContains: []string{"filter[xor][g][border]", "unknown filter segment 'xor'"},
+    },
+    {
+      name:        "unknown filter type",
+      query:       url.Values{"filter[or][g][range]": {"[field:sector]"}},
+      errContains: []string{"filter[or][g][range]", "unknown filter segment 'range'"},
+    },
+    {
+      name:        "missing group name",
+      query:       url.Values{"filter[or][border]": {"[field:sector,value:Energy,compare:eq]"}},
+      errContains: []string{"filter[or][border]", "or group must have form"},
+    },
+    {
+      name:        "empty group name",
+      query:       url.Values{"filter[and][][border]": {"[field:sector,value:Energy,compare:eq]"}},
+      errContains: []string{"filter[and][][border]", "and group must have form"},
+    },
+    {
+      name:        "filter type not specified",
+      query:       url.Values{"filter[not]": {"[field:sector]"}},
+      errContains: []string{"filter[not]", "filter type not specified"},
+    },
+    {
+      name:        "malformed nested value",
+      query:       url.Values{"filter[or][g][not][border]": {"[field:sector,value:Energy,compare:approx]"}},
+      errContains: []string{"filter[or][g][not][border]=[field:sector,value:Energy,compare:approx]", "border filter must have form"},
+    },
+    {
+      name:        "malformed plain value",
+      query:       url.Values{"filter[list]": {"field:sector"}},
+      errContains: []string{"filter[list]=field:sector", "malformed brackets"},
+    },
+  }
+  for _, testCase := range testCases {
+    t.Run(testCase.name, func(t *testing.T) {
+      filters, err := parseTestFilters(t, testCase.query)
+      if err == nil {
+        t.Fatalf("expected error, got %d filters", len(filters))
+      }
+      for _, part := range testCase.errContains {
+        if !strings.Contains(err.Error(), part) {
+          t.Errorf("expected error to contain '%s', got '%v'", part, err)
+        }
+      }
+    })
+  }
+}
+
+func TestParseFiltersStableOrder(t *testing.T) {
+  query := url.Values{
+    "filter[or][b][border]": {"[field:sector,value:Energy,compare:eq]"},
+    "filter[or][a][border]": {"[field:sector,value:Utilities,compare:eq]"},
+    "filter[not][list]":     {"[field:market,values:otc]"},
+  }
+  var expected string
+
+  for idx := 0; idx < 10; idx++ {
+    filters, err := parseTestFilters(t, query)
+    if err != nil {
+      t.Fatalf("cannot parse filters: %v", err)
+    }
+    content, err := json.Marshal(filters)
+    if err != nil {
+      t.Fatalf("cannot marshal filters: %v", err)
+    }
+    if idx == 0 {
+      expected = string(content)
+      continue
+    }
+    if string(content) != expected {
+      t.Fatalf("expected the same filters for the same query:\n%s\ngot:\n%s", expected, content)
+    }
+  }
+}
@@ -158,6 +158,16 @@ func WithCustomRetries(retryCount int, retryWait time.Duration) Options {
   }
 }
 
+// WithTransport replace default transport, e.g. with recording or replay one
+func WithTransport(transport http.RoundTripper) Options {
+  return func(c *Client) {
+    if transport == nil {
+      return
+    }
+    c.client.Transport = transport
+  }
+}
+
 func WithRequestsLimit(reqsCount int, perDur, waitDur, deadlineDur time.Duration) Options {
   return func(c *Client) {
     if reqsCount <= 0 {
@@ -0,0 +1,207 @@
+package httpclient
+
+import (
+  "bytes"
+  "crypto/sha256"
+  "encoding/json"
+  "fmt"
+  "io"
+  "net/http"
+  "net/url"
+  "os"
+  "path/filepath"
+)
+
+const (
+  ModeLive   = "live"
+  ModeRecord = "record"
+  ModeReplay = "replay"
+)
+
+const (
+  recordFileExt  = ".json"
+  recordFilePerm = 0644
+  recordDirPerm  = 0755
+  scrubbedValue  = "SCRUBBED"
+)
+
+// record is request and response pair stored on disk
+type record struct {
+  Method     string      `json:"method"`
+  URL        string      `json:"url"`
+  StatusCode int         `json:"status_code"`
+  Header     http.Header `json:"header"`
+  Body       []byte      `json:"body"`
+}
+
+// recordKey identify request without scrubbed query params, so recorded
+// and replayed requests match regardless of the secrets
+type recordKey struct {
+  scrubKeys []string
+}
+
+func (k *recordKey) scrubURL(reqURL *url.URL) string {
+  scrubbed := *reqURL
+  query := scrubbed.Query()
+
+  for _, key := range k.scrubKeys {
+    if query.Has(key) {
+      query.Set(key, scrubbedValue)
+    }
+  }
+  // encoded query is sorted by key
+  scrubbed.RawQuery = query.Encode()
+
+  return scrubbed.String()
+}
+
+func (k *recordKey) fileName(method, scrubbedURL string, body []byte) string {
+  hash := sha256.New()
+  hash.Write([]byte(method))
+  hash.Write([]byte(scrubbedURL))
+  hash.Write(body)
+
+  return fmt.Sprintf("%x%s", hash.Sum(nil), recordFileExt)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+  if req.Body == nil {
+    return nil, nil
+  }
+  body, err := io.ReadAll(req.Body)
+  if err != nil {
+    return nil, fmt.Errorf("cannot read request body: %v", err)
+  }
+  if err = req.Body.Close(); err != nil {
+    return nil, fmt.Errorf("cannot close request body: %v", err)
+  }
+  return body, nil
+}
+
+// recordingTransport do requests with the next transport and write responses to the directory
+type recordingTransport struct {
+  key  *recordKey
+  dir  string
+  next http.RoundTripper
+}
+
+// NewRecordingTransport return transport which write request and response pairs to the directory.
+// values of the scrub keys query params are not written, e.g. api token
+func NewRecordingTransport(dir string, scrubKeys ...string) (http.RoundTripper, error) {
+  if err := os.MkdirAll(dir, recordDirPerm); err != nil {
+    return nil, fmt.Errorf("cannot create records directory '%s': %v", dir, err)
+  }
+  return &recordingTransport{
+    key: &recordKey{
+      scrubKeys: scrubKeys,
+    },
+    dir:  dir,
+    next: http.DefaultTransport,
+  }, nil
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+  reqBody, err := readRequestBody(req)
+  if err != nil {
+    return nil, err
+  }
+  if reqBody != nil {
+    req.Body = io.NopCloser(bytes.NewReader(reqBody))
+  }
+  resp, err := t.next.RoundTrip(req)
+  if err != nil {
+    return nil, err
+  }
+  respBody, err := io.ReadAll(resp.Body)
+  if err != nil {
+    return nil, fmt.Errorf("cannot read response body: %v", err)
+  }
+  if err = resp.Body.Close(); err != nil {
+    return nil, fmt.Errorf("cannot close response body: %v", err)
+  }
+  resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+  scrubbedURL := t.key.scrubURL(req.URL)
+
+  content, err := json.MarshalIndent(&record{
+    Method:     req.Method,
+    URL:        scrubbedURL,
+    StatusCode: resp.StatusCode,
+    Header:     resp.Header,
+    Body:       respBody,
+  }, "", "  ")
+  if err != nil {
+    return nil, fmt.Errorf("cannot marshal record: %v", err)
+  }
+  path := filepath.Join(t.dir, t.key.fileName(req.Method, scrubbedURL, reqBody))
+
+  if err = os.WriteFile(path, content, recordFilePerm); err != nil {
+    return nil, fmt.Errorf("cannot write record '%s': %v", path, err)
+  }
+  return resp, nil
+}
+
+// replayTransport serve responses recorded by recording transport without network
+type replayTransport struct {
+  key *recordKey
+  dir string
+}
+
+// NewReplayTransport return transport which serve recorded responses from the directory.
+// scrub keys must be the same as on recording
+func NewReplayTransport(dir string, scrubKeys ...string) (http.RoundTripper, error) {
+  if _, err := os.Stat(dir); err != nil {
+    return nil, fmt.Errorf("cannot find records directory '%s': %v", dir, err)
+  }
+  return &replayTransport{
+    key: &recordKey{
+      scrubKeys: scrubKeys,
+    },
+    dir: dir,
+  }, nil
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+  reqBody, err := readRequestBody(req)
+  if err != nil {
+    return nil, err
+  }
+  scrubbedURL := t.key.scrubURL(req.URL)
+  path := filepath.Join(t.dir, t.key.fileName(req.Method, scrubbedURL, reqBody))
+
+  content, err := os.ReadFile(path)
+  if err != nil {
+    return nil, fmt.Errorf("record for %s '%s' not found: %v", req.Method, scrubbedURL, err)
+  }
+  stored := &record{}
+
+  if err = json.Unmarshal(content, stored); err != nil {
+    return nil, fmt.Errorf("cannot unmarshal record '%s': %v", path, err)
+  }
+  return &http.Response{
+    Status:        fmt.Sprintf("%d %s", stored.StatusCode, http.StatusText(stored.StatusCode)),
+    StatusCode:    stored.StatusCode,
+    Proto:         req.Proto,
+    ProtoMajor:    req.ProtoMajor,
+    ProtoMinor:    req.ProtoMinor,
+    Header:        stored.Header,
+    Body:          io.NopCloser(bytes.NewReader(stored.Body)),
+    ContentLength: int64(len(stored.Body)),
+    Request:       req,
+  }, nil
+}
+
+// NewModeTransport return transport for the mode, nil transport mean live requests
+func NewModeTransport(mode, dir string, scrubKeys ...string) (http.RoundTripper, error) {
+  switch mode {
+  case "", ModeLive:
+    return nil, nil
+  case ModeRecord:
+    return NewRecordingTransport(dir, scrubKeys...)
+  case ModeReplay:
+    return NewReplayTransport(dir, scrubKeys...)
+  default:
+    return nil, fmt.Errorf("unknown http client mode '%s'. possible: %s, %s, %s",
+      mode, ModeLive, ModeRecord, ModeReplay)
+  }
+}
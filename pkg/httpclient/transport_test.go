@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+)
+
+const (
+  testApiKey    = "apiKey"
+  testApiSecret = "secret-token"
+)
+
+func doTransportGet(t *testing.T, transport http.RoundTripper, reqURL string) (int, string) {
+  t.Helper()
+
+  client := &http.Client{Transport: transport}
+
+  resp, err := client.Get(reqURL)
+  if err != nil {
+    t.Fatalf("cannot get '%s': %v", reqURL, err)
+  }
+  defer resp.Body.Close()
+
+  body, err := io.ReadAll(resp.Body)
+  if err != nil {
+    t.Fatalf("cannot read response body: %v", err)
+  }
+  return resp.StatusCode, string(body)
+}
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+  var served int
+
+  server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    served++
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusAccepted)
+    _, _ = w.Write([]byte(`{"ticker":"` + r.URL.Query().Get("ticker") + `"}`))
+  }))
+  defer server.Close()
+
+  dir := t.TempDir()
+
+  recording, err := NewModeTransport(ModeRecord, dir, testApiKey)
+  if err != nil {
+    t.Fatalf("cannot create recording transport: %v", err)
+  }
+  recordedStatus, recordedBody := doTransportGet(t, recording,
+    server.URL+"/v3/reference/tickers?ticker=AAPL&"+testApiKey+"="+testApiSecret)
+
+  files, err := filepath.Glob(filepath.Join(dir, "*"+recordFileExt))
+  if err != nil || len(files) != 1 {
+    t.Fatalf("expected one record file, got %d: %v", len(files), err)
+  }
+  content, err := os.ReadFile(files[0])
+  if err != nil {
+    t.Fatalf("cannot read record file: %v", err)
+  }
+  if strings.Contains(string(content), testApiSecret) {
+    t.Errorf("record contains api key value: %s", content)
+  }
+  if !strings.Contains(string(content), testApiKey+"="+scrubbedValue) {
+    t.Errorf("record does not contain scrubbed api key: %s", content)
+  }
+
+  replay, err := NewModeTransport(ModeReplay, dir, testApiKey)
+  if err != nil {
+    t.Fatalf("cannot create replay transport: %v", err)
+  }
+  // replayed request match the record with any api key value and query order
+  replayedStatus, replayedBody := doTransportGet(t, replay,
+    server.URL+"/v3/reference/tickers?"+testApiKey+"=another-token&ticker=AAPL")
+
+  if replayedStatus != recordedStatus || replayedBody != recordedBody {
+    t.Errorf("expected replayed %d %s, got %d %s", recordedStatus, recordedBody, replayedStatus, replayedBody)
+  }
+  if served != 1 {
+    t.Errorf("expected one request served by the server, got %d", served)
+  }
+  client := &http.Client{Transport: replay}
+
+  if _, err = client.Get(server.URL + "/v3/reference/tickers?ticker=MSFT"); err == nil {
+    t.Errorf("expected error for request without record")
+  }
+}
+
+func TestNewModeTransport(t *testing.T) {
+  dir := t.TempDir()
+
+  testCases := []struct {
+    mode    string
+    live    bool
+    wantErr bool
+  }{
+    {mode: "", live: true},
+    {mode: ModeLive, live: true},
+    {mode: ModeRecord},
+    {mode: ModeReplay},
+    {mode: "unknown", wantErr: true},
+  }
+  for _, testCase := range testCases {
+    transport, err := NewModeTransport(testCase.mode, dir)
+
+    if (err != nil) != testCase.wantErr {
+      t.Errorf("mode '%s': unexpected error: %v", testCase.mode, err)
+      continue
+    }
+    if !testCase.wantErr && (transport == nil) != testCase.live {
+      t.Errorf("mode '%s': expected live %v, got transport %T", testCase.mode, testCase.live, transport)
+    }
+  }
+  if _, err := NewModeTransport(ModeReplay, filepath.Join(dir, "missing")); err == nil {
+    t.Errorf("expected error for missing records directory")
+  }
+}
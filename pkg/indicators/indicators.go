@@ -0,0 +1,271 @@
+package indicators
+
+import (
+  "errors"
+  "fmt"
+  "math"
+  "time"
+)
+
+// indicators of the price bars. each series is aligned with the input bars,
+// values before the first full period are NaN
+
+var ErrMalformedParam = errors.New("malformed indicator param")
+
+// Stock is price bar of the indicators input. bars must be ordered by time ascending
+type Stock struct {
+  Time   time.Time
+  Open   float64
+  High   float64
+  Low    float64
+  Close  float64
+  Volume float64
+}
+
+type MACDSeries struct {
+  MACD      []float64
+  Signal    []float64
+  Histogram []float64
+}
+
+type BollingerSeries struct {
+  Middle []float64
+  Upper  []float64
+  Lower  []float64
+}
+
+// Closes return close prices of the bars
+func Closes(stocks []Stock) []float64 {
+  closes := make([]float64, 0, len(stocks))
+
+  for _, stock := range stocks {
+    closes = append(closes, stock.Close)
+  }
+  return closes
+}
+
+func checkPeriod(period int) error {
+  if period < 1 {
+    return fmt.Errorf("%w: period must be positive, got %d", ErrMalformedParam, period)
+  }
+  return nil
+}
+
+func nanSeries(size int) []float64 {
+  series := make([]float64, size)
+
+  for idx := range series {
+    series[idx] = math.NaN()
+  }
+  return series
+}
+
+// firstValid return index of the first not NaN value or size of the values
+func firstValid(values []float64) int {
+  for idx, value := range values {
+    if !math.IsNaN(value) {
+      return idx
+    }
+  }
+  return len(values)
+}
+
+// SMA is simple moving average of the values for the period
+func SMA(values []float64, period int) ([]float64, error) {
+  if err := checkPeriod(period); err != nil {
+    return nil, err
+  }
+  series := nanSeries(len(values))
+  var sum float64
+
+  for idx, value := range values {
+    sum += value
+
+    if idx >= period {
+      sum -= values[idx-period]
+    }
+    if idx >= period-1 {
+      series[idx] = sum / float64(period)
+    }
+  }
+  return series, nil
+}
+
+// EMA is exponential moving average of the values with 2/(period+1) smoothing.
+// average is seeded by simple average of the first period, leading NaN values are skipped
+func EMA(values []float64, period int) ([]float64, error) {
+  if err := checkPeriod(period); err != nil {
+    return nil, err
+  }
+  return smoothed(values, period, 2/float64(period+1)), nil
+}
+
+// smoothed is exponential average with the alpha seeded by simple average of the first period
+func smoothed(values []float64, period int, alpha float64) []float64 {
+  series := nanSeries(len(values))
+  start := firstValid(values)
+
+  if len(values)-start < period {
+    return series
+  }
+  var seed float64
+
+  for idx := start; idx < start+period; idx++ {
+    seed += values[idx]
+  }
+  average := seed / float64(period)
+  series[start+period-1] = average
+
+  for idx := start + period; idx < len(values); idx++ {
+    average += alpha * (values[idx] - average)
+    series[idx] = average
+  }
+  return series
+}
+
+// RSI is relative strength index of the close prices with Wilder smoothing of the gains and losses
+func RSI(stocks []Stock, period int) ([]float64, error) {
+  if err := checkPeriod(period); err != nil {
+    return nil, err
+  }
+  series := nanSeries(len(stocks))
+
+  if len(stocks) <= period {
+    return series, nil
+  }
+  gains := nanSeries(len(stocks))
+  losses := nanSeries(len(stocks))
+
+  for idx := 1; idx < len(stocks); idx++ {
+    change := stocks[idx].Close - stocks[idx-1].Close
+    gains[idx] = math.Max(change, 0)
+    losses[idx] = math.Max(-change, 0)
+  }
+  alpha := 1 / float64(period)
+
+  averageGains := smoothed(gains, period, alpha)
+  averageLosses := smoothed(losses, period, alpha)
+
+  for idx := range stocks {
+    gain, loss := averageGains[idx], averageLosses[idx]
+
+    switch {
+    case math.IsNaN(gain) || math.IsNaN(loss):
+    case loss == 0 && gain == 0:
+      // flat prices are neutral
+      series[idx] = 50
+    case loss == 0:
+      series[idx] = 100
+    default:
+      series[idx] = 100 - 100/(1+gain/loss)
+    }
+  }
+  return series, nil
+}
+
+// MACD is difference of the fast and slow close prices EMA, signal is EMA of the difference
+func MACD(stocks []Stock, fast, slow, signal int) (*MACDSeries, error) {
+  for _, period := range []int{fast, slow, signal} {
+    if err := checkPeriod(period); err != nil {
+      return nil, err
+    }
+  }
+  if fast >= slow {
+    return nil, fmt.Errorf("%w: fast period %d must be less than slow period %d", ErrMalformedParam, fast, slow)
+  }
+  closes := Closes(stocks)
+
+  fastSeries, err := EMA(closes, fast)
+  if err != nil {
+    return nil, err
+  }
+  slowSeries, err := EMA(closes, slow)
+  if err != nil {
+    return nil, err
+  }
+  macd := nanSeries(len(stocks))
+
+  for idx := range macd {
+    // NaN of any average is kept
+    macd[idx] = fastSeries[idx] - slowSeries[idx]
+  }
+  signalSeries, err := EMA(macd, signal)
+  if err != nil {
+    return nil, err
+  }
+  histogram := nanSeries(len(stocks))
+
+  for idx := range histogram {
+    histogram[idx] = macd[idx] - signalSeries[idx]
+  }
+  return &MACDSeries{
+    MACD:      macd,
+    Signal:    signalSeries,
+    Histogram: histogram,
+  }, nil
+}
+
+// Bollinger is SMA of the close prices with bands on width population standard deviations
+func Bollinger(stocks []Stock, period int, width float64) (*BollingerSeries, error) {
+  if width <= 0 {
+    return nil, fmt.Errorf("%w: bands width must be positive", ErrMalformedParam)
+  }
+  closes := Closes(stocks)
+
+  middle, err := SMA(closes, period)
+  if err != nil {
+    return nil, err
+  }
+  upper := nanSeries(len(stocks))
+  lower := nanSeries(len(stocks))
+
+  for idx := period - 1; idx < len(stocks); idx++ {
+    var variance float64
+
+    for _, value := range closes[idx-period+1 : idx+1] {
+      variance += (value - middle[idx]) * (value - middle[idx])
+    }
+    deviation := math.Sqrt(variance / float64(period))
+
+    upper[idx] = middle[idx] + width*deviation
+    lower[idx] = middle[idx] - width*deviation
+  }
+  return &BollingerSeries{
+    Middle: middle,
+    Upper:  upper,
+    Lower:  lower,
+  }, nil
+}
+
+// ATR is average true range with Wilder smoothing. true range of the first bar is not known
+func ATR(stocks []Stock, period int) ([]float64, error) {
+  if err := checkPeriod(period); err != nil {
+    return nil, err
+  }
+  trueRanges := nanSeries(len(stocks))
+
+  for idx := 1; idx < len(stocks); idx++ {
+    prevClose := stocks[idx-1].Close
+
+    trueRanges[idx] = math.Max(stocks[idx].High-stocks[idx].Low,
+      math.Max(math.Abs(stocks[idx].High-prevClose), math.Abs(stocks[idx].Low-prevClose)))
+  }
+  return smoothed(trueRanges, period, 1/float64(period)), nil
+}
+
+// OBV is on balance volume accumulated from zero at the first bar
+func OBV(stocks []Stock) []float64 {
+  series := make([]float64, len(stocks))
+
+  for idx := 1; idx < len(stocks); idx++ {
+    series[idx] = series[idx-1]
+
+    switch {
+    case stocks[idx].Close > stocks[idx-1].Close:
+      series[idx] += stocks[idx].Volume
+    case stocks[idx].Close < stocks[idx-1].Close:
+      series[idx] -= stocks[idx].Volume
+    }
+  }
+  return series
+}
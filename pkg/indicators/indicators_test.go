@@ -0,0 +1,363 @@
+package indicators
+
+import (
+  "errors"
+  "math"
+  "testing"
+)
+
+// reference values are rounded to two decimals
+const referenceTolerance = 0.005
+
+// closes of the StockCharts moving averages example
+var averagesCloses = []float64{
+  22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29, 22.15, 22.39, 22.38, 22.61, 23.36,
+}
+
+// closes of the StockCharts RSI example
+var rsiCloses = []float64{
+  44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+  45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+}
+
+func closeStocks(closes []float64) []Stock {
+  stocks := make([]Stock, 0, len(closes))
+
+  for _, price := range closes {
+    stocks = append(stocks, Stock{
+      Open:  price,
+      High:  price,
+      Low:   price,
+      Close: price,
+    })
+  }
+  return stocks
+}
+
+// linearCloses return closes growing by one from one
+func linearCloses(size int) []float64 {
+  closes := make([]float64, 0, size)
+
+  for idx := 0; idx < size; idx++ {
+    closes = append(closes, float64(idx+1))
+  }
+  return closes
+}
+
+func repeated(size int, value float64) []float64 {
+  values := make([]float64, size)
+
+  for idx := range values {
+    values[idx] = value
+  }
+  return values
+}
+
+func nanPrefix(size int, values ...float64) []float64 {
+  return append(nanSeries(size), values...)
+}
+
+func checkSeries(t *testing.T, name string, expected, actual []float64, tolerance float64) {
+  t.Helper()
+
+  if len(actual) != len(expected) {
+    t.Fatalf("%s: expected %d values, got %d", name, len(expected), len(actual))
+  }
+  for idx := range expected {
+    if math.IsNaN(expected[idx]) {
+      if !math.IsNaN(actual[idx]) {
+        t.Errorf("%s: expected warm-up NaN at %d, got %v", name, idx, actual[idx])
+      }
+      continue
+    }
+    if math.IsNaN(actual[idx]) || math.Abs(actual[idx]-expected[idx]) > tolerance {
+      t.Errorf("%s: expected %v at %d, got %v", name, expected[idx], idx, actual[idx])
+    }
+  }
+}
+
+func TestSMA(t *testing.T) {
+  testCases := []struct {
+    name      string
+    values    []float64
+    period    int
+    expected  []float64
+    tolerance float64
+  }{
+    {
+      name:     "period one",
+      values:   []float64{3, 1, 2},
+      period:   1,
+      expected: []float64{3, 1, 2},
+    },
+    {
+      name:     "linear",
+      values:   linearCloses(6),
+      period:   3,
+      expected: nanPrefix(2, 2, 3, 4, 5),
+    },
+    {
+      name:     "short series",
+      values:   []float64{1, 2},
+      period:   3,
+      expected: nanPrefix(2),
+    },
+    {
+      name:      "reference",
+      values:    averagesCloses,
+      period:    10,
+      expected:  nanPrefix(9, 22.22, 22.21, 22.23, 22.26, 22.30, 22.42),
+      tolerance: referenceTolerance,
+    },
+  }
+  for _, testCase := range testCases {
+    series, err := SMA(testCase.values, testCase.period)
+    if err != nil {
+      t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+    }
+    checkSeries(t, testCase.name, testCase.expected, series, testCase.tolerance)
+  }
+}
+
+func TestEMA(t *testing.T) {
+  testCases := []struct {
+    name      string
+    values    []float64
+    period    int
+    expected  []float64
+    tolerance float64
+  }{
+    {
+      // average seeded by SMA lag behind the linear values by (period-1)/2
+      name:     "linear",
+      values:   linearCloses(6),
+      period:   3,
+      expected: nanPrefix(2, 2, 3, 4, 5),
+    },
+    {
+      name:     "leading NaN skipped",
+      values:   nanPrefix(2, 1, 2, 3, 4),
+      period:   3,
+      expected: nanPrefix(4, 2, 3),
+    },
+    {
+      name:      "reference",
+      values:    averagesCloses,
+      period:    10,
+      expected:  nanPrefix(9, 22.22, 22.21, 22.24, 22.27, 22.33, 22.52),
+      tolerance: referenceTolerance,
+    },
+  }
+  for _, testCase := range testCases {
+    series, err := EMA(testCase.values, testCase.period)
+    if err != nil {
+      t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+    }
+    checkSeries(t, testCase.name, testCase.expected, series, testCase.tolerance)
+  }
+}
+
+func TestRSI(t *testing.T) {
+  testCases := []struct {
+    name      string
+    closes    []float64
+    period    int
+    expected  []float64
+    tolerance float64
+  }{
+    {
+      name:     "flat prices",
+      closes:   []float64{10, 10, 10, 10},
+      period:   2,
+      expected: nanPrefix(2, 50, 50),
+    },
+    {
+      name:     "only gains",
+      closes:   []float64{10, 11, 12, 13},
+      period:   2,
+      expected: nanPrefix(2, 100, 100),
+    },
+    {
+      name:     "alternating",
+      closes:   []float64{10, 11, 10, 11, 10},
+      period:   2,
+      expected: nanPrefix(2, 50, 75, 37.5),
+    },
+    {
+      name:     "short series",
+      closes:   []float64{10, 11},
+      period:   2,
+      expected: nanPrefix(2),
+    },
+    {
+      name:      "reference",
+      closes:    rsiCloses,
+      period:    14,
+      expected:  nanPrefix(14, 70.46, 66.25, 66.48, 69.35, 66.29, 57.92),
+      tolerance: referenceTolerance,
+    },
+  }
+  for _, testCase := range testCases {
+    series, err := RSI(closeStocks(testCase.closes), testCase.period)
+    if err != nil {
+      t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+    }
+    checkSeries(t, testCase.name, testCase.expected, series, testCase.tolerance)
+  }
+}
+
+func TestMACD(t *testing.T) {
+  const (
+    fast   = 12
+    slow   = 26
+    signal = 9
+    size   = 40
+  )
+  testCases := []struct {
+    name      string
+    closes    []float64
+    macd      []float64
+    signal    []float64
+    histogram []float64
+  }{
+    {
+      // difference of the averages lagged by (period-1)/2 is (slow-fast)/2
+      name:      "linear",
+      closes:    linearCloses(size),
+      macd:      append(nanSeries(slow-1), repeated(size-slow+1, 7)...),
+      signal:    append(nanSeries(slow+signal-2), repeated(size-slow-signal+2, 7)...),
+      histogram: append(nanSeries(slow+signal-2), repeated(size-slow-signal+2, 0)...),
+    },
+    {
+      name:      "flat prices",
+      closes:    repeated(size, 10),
+      macd:      append(nanSeries(slow-1), repeated(size-slow+1, 0)...),
+      signal:    append(nanSeries(slow+signal-2), repeated(size-slow-signal+2, 0)...),
+      histogram: append(nanSeries(slow+signal-2), repeated(size-slow-signal+2, 0)...),
+    },
+  }
+  for _, testCase := range testCases {
+    series, err := MACD(closeStocks(testCase.closes), fast, slow, signal)
+    if err != nil {
+      t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+    }
+    checkSeries(t, testCase.name+" macd", testCase.macd, series.MACD, 1e-9)
+    checkSeries(t, testCase.name+" signal", testCase.signal, series.Signal, 1e-9)
+    checkSeries(t, testCase.name+" histogram", testCase.histogram, series.Histogram, 1e-9)
+  }
+}
+
+func TestBollinger(t *testing.T) {
+  deviation := math.Sqrt(2.0 / 3)
+
+  testCases := []struct {
+    name   string
+    closes []float64
+    period int
+    width  float64
+    middle []float64
+    upper  []float64
+    lower  []float64
+  }{
+    {
+      name:   "linear",
+      closes: linearCloses(4),
+      period: 3,
+      width:  2,
+      middle: nanPrefix(2, 2, 3),
+      upper:  nanPrefix(2, 2+2*deviation, 3+2*deviation),
+      lower:  nanPrefix(2, 2-2*deviation, 3-2*deviation),
+    },
+    {
+      name:   "flat prices",
+      closes: repeated(3, 5),
+      period: 2,
+      width:  1,
+      middle: nanPrefix(1, 5, 5),
+      upper:  nanPrefix(1, 5, 5),
+      lower:  nanPrefix(1, 5, 5),
+    },
+  }
+  for _, testCase := range testCases {
+    series, err := Bollinger(closeStocks(testCase.closes), testCase.period, testCase.width)
+    if err != nil {
+      t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+    }
+    checkSeries(t, testCase.name+" middle", testCase.middle, series.Middle, 1e-9)
+    checkSeries(t, testCase.name+" upper", testCase.upper, series.Upper, 1e-9)
+    checkSeries(t, testCase.name+" lower", testCase.lower, series.Lower, 1e-9)
+  }
+}
+
+func TestATR(t *testing.T) {
+  testCases := []struct {
+    name     string
+    stocks   []Stock
+    period   int
+    expected []float64
+  }{
+    {
+      name: "constant range",
+      stocks: []Stock{
+        {High: 11, Low: 9, Close: 10},
+        {High: 11, Low: 9, Close: 10},
+        {High: 11, Low: 9, Close: 10},
+        {High: 11, Low: 9, Close: 10},
+      },
+      period:   2,
+      expected: nanPrefix(2, 2, 2),
+    },
+    {
+      // gap from the prior close widen the true range
+      name: "gaps",
+      stocks: []Stock{
+        {High: 11, Low: 9, Close: 10},
+        {High: 14, Low: 12, Close: 13},
+        {High: 12, Low: 11, Close: 11},
+        {High: 12, Low: 10, Close: 11},
+      },
+      period:   2,
+      expected: nanPrefix(2, 3, 2.5),
+    },
+  }
+  for _, testCase := range testCases {
+    series, err := ATR(testCase.stocks, testCase.period)
+    if err != nil {
+      t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+    }
+    checkSeries(t, testCase.name, testCase.expected, series, 1e-9)
+  }
+}
+
+func TestOBV(t *testing.T) {
+  stocks := []Stock{
+    {Close: 10, Volume: 100},
+    {Close: 11, Volume: 200},
+    {Close: 10, Volume: 300},
+    {Close: 10, Volume: 400},
+  }
+  checkSeries(t, "obv", []float64{0, 200, -100, -100}, OBV(stocks), 0)
+}
+
+func TestMalformedParams(t *testing.T) {
+  stocks := closeStocks(linearCloses(5))
+
+  testCases := []struct {
+    name    string
+    compute func() error
+  }{
+    {"sma zero period", func() error { _, err := SMA(Closes(stocks), 0); return err }},
+    {"ema negative period", func() error { _, err := EMA(Closes(stocks), -1); return err }},
+    {"rsi zero period", func() error { _, err := RSI(stocks, 0); return err }},
+    {"atr zero period", func() error { _, err := ATR(stocks, 0); return err }},
+    {"macd fast not less than slow", func() error { _, err := MACD(stocks, 3, 3, 2); return err }},
+    {"macd zero signal", func() error { _, err := MACD(stocks, 2, 3, 0); return err }},
+    {"bollinger zero width", func() error { _, err := Bollinger(stocks, 2, 0); return err }},
+    {"bollinger zero period", func() error { _, err := Bollinger(stocks, 0, 2); return err }},
+  }
+  for _, testCase := range testCases {
+    if err := testCase.compute(); !errors.Is(err, ErrMalformedParam) {
+      t.Errorf("%s: expected malformed param error, got %v", testCase.name, err)
+    }
+  }
+}
@@ -0,0 +1,36 @@
+package lifecycle
+
+import (
+  "context"
+  "time"
+)
+
+// detachedContext keep values of the parent, but is never canceled
+type detachedContext struct {
+  parent context.Context
+}
+
+// WithoutCancel return context not canceled with the parent.
+// it is used by storages and queues, so in-flight requests and deliveries
+// are finished on shutdown and the clients are closed by registered closers
+func WithoutCancel(parent context.Context) context.Context {
+  return detachedContext{
+    parent: parent,
+  }
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+  return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+  return nil
+}
+
+func (detachedContext) Err() error {
+  return nil
+}
+
+func (c detachedContext) Value(key any) any {
+  return c.parent.Value(key)
+}
@@ -0,0 +1,167 @@
+package lifecycle
+
+import (
+  "context"
+  "errors"
+  "fmt"
+  "net/http"
+  "os"
+  "os/signal"
+  "sync"
+  "syscall"
+  "time"
+
+  log "github.com/sirupsen/logrus"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+type CloseFunc func(ctx context.Context) error
+
+type closer struct {
+  name  string
+  close CloseFunc
+}
+
+// Lifecycle cancel the root context on shutdown signal and stop the service in order:
+// drain http servers, wait background jobs, then call closers in registration order
+type Lifecycle struct {
+  ctx             context.Context
+  cancel          context.CancelFunc
+  shutdownTimeout time.Duration
+  mu              sync.Mutex
+  servers         []*http.Server
+  closers         []*closer
+  jobs            sync.WaitGroup
+}
+
+type Options func(l *Lifecycle)
+
+func WithShutdownTimeout(timeout time.Duration) Options {
+  return func(l *Lifecycle) {
+    if timeout > 0 {
+      l.shutdownTimeout = timeout
+    }
+  }
+}
+
+func New(options ...Options) *Lifecycle {
+  ctx, cancel := context.WithCancel(context.Background())
+
+  l := &Lifecycle{
+    ctx:             ctx,
+    cancel:          cancel,
+    shutdownTimeout: defaultShutdownTimeout,
+  }
+  for _, opt := range options {
+    opt(l)
+  }
+  return l
+}
+
+// Context return the root context canceled on shutdown signal
+func (l *Lifecycle) Context() context.Context {
+  return l.ctx
+}
+
+// Go run background job, shutdown wait the job return after the root context canceled
+func (l *Lifecycle) Go(name string, job func()) {
+  l.jobs.Add(1)
+
+  go func() {
+    defer l.jobs.Done()
+    job()
+    log.Infof("background job '%s' stopped", name)
+  }()
+}
+
+// ServeHttp serve default mux on port. failed serving start the shutdown
+func (l *Lifecycle) ServeHttp(port string) {
+  server := &http.Server{
+    Addr: fmt.Sprint(":", port),
+  }
+  l.mu.Lock()
+  l.servers = append(l.servers, server)
+  l.mu.Unlock()
+
+  go func() {
+    if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+      log.Errorf("listen and serve error: %v", err)
+      l.cancel()
+    }
+  }()
+}
+
+// Stop cancel the root context to start the shutdown, used by background jobs failed without recovery
+func (l *Lifecycle) Stop() {
+  l.cancel()
+}
+
+// OnShutdown register closer called after http servers drained and background jobs stopped
+func (l *Lifecycle) OnShutdown(name string, closeFunc CloseFunc) {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+
+  l.closers = append(l.closers, &closer{
+    name:  name,
+    close: closeFunc,
+  })
+}
+
+// Wait block until shutdown signal or root context cancellation and then shutdown the service
+func (l *Lifecycle) Wait() {
+  exitSignal := make(chan os.Signal, 1)
+  signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
+  defer signal.Stop(exitSignal)
+
+  select {
+  case sig := <-exitSignal:
+    log.Infof("received signal '%v'. shutdown started", sig)
+  case <-l.ctx.Done():
+    log.Infof("root context canceled. shutdown started")
+  }
+  l.cancel()
+  l.shutdown()
+}
+
+func (l *Lifecycle) shutdown() {
+  ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+  defer cancel()
+
+  l.mu.Lock()
+  servers := l.servers
+  closers := l.closers
+  l.mu.Unlock()
+
+  for _, server := range servers {
+    if err := server.Shutdown(ctx); err != nil {
+      log.Errorf("cannot drain http server on '%s': %v", server.Addr, err)
+    }
+  }
+  if !l.waitJobs(ctx) {
+    log.Warnf("background jobs not stopped before shutdown timeout")
+  }
+  for _, c := range closers {
+    if err := c.close(ctx); err != nil {
+      log.Errorf("cannot close %s: %v", c.name, err)
+      continue
+    }
+    log.Infof("%s closed", c.name)
+  }
+  log.Infof("shutdown finished")
+}
+
+func (l *Lifecycle) waitJobs(ctx context.Context) bool {
+  done := make(chan struct{})
+
+  go func() {
+    l.jobs.Wait()
+    close(done)
+  }()
+  select {
+  case <-done:
+    return true
+  case <-ctx.Done():
+    return false
+  }
+}
@@ -1,14 +1,11 @@
 package main
 
 import (
-  "context"
   "flag"
   "main/internal/handler"
-  "os"
-  "os/signal"
-  "syscall"
 
   "github.com/UshakovN/stock-predictor-service/config"
+  "github.com/UshakovN/stock-predictor-service/lifecycle"
   log "github.com/sirupsen/logrus"
 )
 
@@ -25,7 +22,8 @@ import (
 // @name X-Auth-Token
 //
 func main() {
-  ctx := context.Background()
+  lc := lifecycle.New()
+  ctx := lc.Context()
 
   servePort := flag.String("port", "8084", "serving port")
   configPath := flag.String("path", "", "path to service config file")
@@ -36,23 +34,19 @@ func main() {
     log.Fatalf("cannot parse fetcher config: %v", err)
   }
 
-  h, err := handler.NewHandler(ctx, cfg)
+  // clients are not canceled until in-flight requests are served
+  h, err := handler.NewHandler(lifecycle.WithoutCancel(ctx), cfg)
   if err != nil {
     log.Fatalf("cannot create new handler: %v", err)
   }
   h.BindRouter()
 
-  go h.ContinuouslyServeHttp(*servePort)
+  lc.ServeHttp(*servePort)
   log.Infof("ready for serve http on port: %s", *servePort)
 
-  go h.UpdateElasticScheduled()
+  lc.Go("elasticsearch index update", func() {
+    h.UpdateElasticScheduled(ctx)
+  })
 
-  serviceShutdown()
-}
-
-func serviceShutdown() {
-  exitSignal := make(chan os.Signal)
-  signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
-
-  <-exitSignal
+  lc.Wait()
 }
@@ -202,6 +202,24 @@ const docTemplate = `{
                 "homepage_url": {
                     "type": "string"
                 },
+                "list_date": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "primary_exchange": {
+                    "type": "string"
+                },
+                "sector": {
+                    "type": "string"
+                },
+                "sic_code": {
+                    "type": "string"
+                },
+                "sic_description": {
+                    "type": "string"
+                },
                 "ticker_id": {
                     "type": "string"
                 },
@@ -179,13 +179,6 @@ func (h *Handler) HandleHealth(w http.ResponseWriter, _ *http.Request) error {
   return nil
 }
 
-func (h *Handler) ContinuouslyServeHttp(port string) {
-  err := http.ListenAndServe(fmt.Sprint(":", port), nil)
-  if err != nil {
-    log.Fatalf("listen and serve error: %v", err)
-  }
-}
-
 func (h *Handler) formTickersRequest(
   req *searchservice.SearchRequest,
   results *es.SearchResults[*searchservice.Info],
@@ -256,7 +249,8 @@ func (h *Handler) formSearchOptions(req *searchservice.ResourceRequest) *es.Sear
   }
 }
 
-func (h *Handler) UpdateElasticScheduled() {
+// UpdateElasticScheduled update elasticsearch index by schedule until context is done
+func (h *Handler) UpdateElasticScheduled(ctx context.Context) {
   const (
     startTimer = 0
     timeFormat = "2006-01-02 15:04:05"
@@ -264,46 +258,58 @@ func (h *Handler) UpdateElasticScheduled() {
   timer := time.NewTimer(startTimer)
   defer timer.Stop()
 
-  for timerTime := range timer.C {
-    log.Infof("start scheduled update elasticsearch index: %s", timerTime.UTC().Format(timeFormat))
-    if err := h.updateElasticsearchIndex(); err != nil {
-      log.Errorf("update elasticsearch index failed: %v", err)
+  for {
+    select {
+    case <-ctx.Done():
       return
+
+    case timerTime := <-timer.C:
+      log.Infof("start scheduled update elasticsearch index: %s", timerTime.UTC().Format(timeFormat))
+      if err := h.updateElasticsearchIndex(); err != nil {
+        log.Errorf("update elasticsearch index failed: %v", err)
+        return
+      }
+      log.Infof("scheduled update elasticsearch index success: %s", utils.NotTimeUTC().Format(timeFormat))
+      timer.Reset(h.elasticIndexUpdateDuration)
     }
-    log.Infof("scheduled update elasticsearch index success: %s", utils.NotTimeUTC().Format(timeFormat))
-    timer.Reset(h.elasticIndexUpdateDuration)
   }
 }
 
 func (h *Handler) updateElasticsearchIndex() error {
-  resp, err := h.serviceClient.GetTickers(&clientservice.TickersRequest{})
-  if err != nil {
-    return fmt.Errorf("client service cannot get tickers: %v", err)
-  }
-  log.Infof("client service return %d tickers for es index", resp.Count)
-
+  const (
+    tickersPageCount   = 1000
+    createdCountForLog = 25
+  )
   var createdDocs int
-  const createdCountForLog = 25
 
-  for _, ticker := range resp.Tickers {
-    if ticker.Fields == nil || ticker.Fields.TickerId == "" {
-      log.Errorf("encountered ticker with nil fields")
-      continue
-    }
-    partInfo := searchservice.Info{
-      TickerId:           ticker.Fields.TickerId,
-      CompanyName:        ticker.Fields.CompanyName,
-      CompanyDescription: ticker.Fields.CompanyDescription,
-      HomepageUrl:        ticker.Fields.HomepageUrl,
-    }
-    if err = h.elasticClient.CreateDoc(h.elasticIndexName, partInfo.TickerId, &partInfo); err != nil {
-      return fmt.Errorf("es client cannot create doc in index %s: %v", h.elasticIndexName, err)
-    }
-    createdDocs++
-
-    if createdDocs%createdCountForLog == 0 {
-      log.Infof("es client create %d docs in index %s", createdDocs, h.elasticIndexName)
-    }
+  err := h.serviceClient.PageTickers(&clientservice.TickersRequest{}, tickersPageCount,
+    func(tickers []*clientservice.Ticker) error {
+      log.Infof("client service return %d tickers for es index", len(tickers))
+
+      for _, ticker := range tickers {
+        if ticker.Fields == nil || ticker.Fields.TickerId == "" {
+          log.Errorf("encountered ticker with nil fields")
+          continue
+        }
+        partInfo := searchservice.Info{
+          TickerId:           ticker.Fields.TickerId,
+          CompanyName:        ticker.Fields.CompanyName,
+          CompanyDescription: ticker.Fields.CompanyDescription,
+          HomepageUrl:        ticker.Fields.HomepageUrl,
+        }
+        if err := h.elasticClient.CreateDoc(h.elasticIndexName, partInfo.TickerId, &partInfo); err != nil {
+          return fmt.Errorf("es client cannot create doc in index %s: %v", h.elasticIndexName, err)
+        }
+        createdDocs++
+
+        if createdDocs%createdCountForLog == 0 {
+          log.Infof("es client create %d docs in index %s", createdDocs, h.elasticIndexName)
+        }
+      }
+      return nil
+    })
+  if err != nil {
+    return fmt.Errorf("cannot page tickers from client service: %v", err)
   }
   return nil
 }
@@ -10,7 +10,6 @@ import (
   "github.com/UshakovN/stock-predictor-service/errs"
   "github.com/UshakovN/stock-predictor-service/swagger"
   "github.com/UshakovN/stock-predictor-service/utils"
-  log "github.com/sirupsen/logrus"
 )
 
 type Handler struct {
@@ -125,13 +124,6 @@ func (h *Handler) HandleHealthWrapper() http.HandlerFunc {
   return utils.HandleHealth()
 }
 
-func (h *Handler) ContinuouslyServeHttp(port string) {
-  err := http.ListenAndServe(fmt.Sprint(":", port), nil)
-  if err != nil {
-    log.Fatalf("listen and serve error: %v", err)
-  }
-}
-
 type ServicesResponse struct {
   Success      bool     `json:"success"`
   ServiceNames []string `json:"service_names"`